@@ -0,0 +1,117 @@
+// kluster-storage-migrate: etcd에 저장되어 있는 Kluster를 CRD의 현재 storage version으로 다시 쓰고,
+// CRD의 status.storedVersions를 storage version 하나로 줄인다. storedVersions에서 빠져야 나중에 이전 버전 serving을 끌 수 있음.
+// 다시 쓰는 동안 버전 변환이 필요하므로 CRD의 conversion strategy가 Webhook이어야 한다.
+//
+//	go run ./cmd/kluster-storage-migrate --kubeconfig ~/.kube/config
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	klient "github.com/inspirit941/kluster/pkg/client/clientset/versioned"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
+	"os"
+)
+
+const crdName = "klusters.inspirit941.dev"
+
+func main() {
+	kubeconfig := flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
+	dryRun := flag.Bool("dry-run", false, "only print the Klusters that would be rewritten")
+	flag.Parse()
+
+	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "building config: %s\n", err.Error())
+		os.Exit(1)
+	}
+	klientset := klient.NewForConfigOrDie(config)
+	apiextensions := apiextensionsclient.NewForConfigOrDie(config)
+
+	ctx := context.Background()
+	storageVersion, err := checkCRD(ctx, apiextensions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "checking %s: %s\n", crdName, err.Error())
+		os.Exit(1)
+	}
+	if err := rewriteKlusters(ctx, klientset, *dryRun); err != nil {
+		fmt.Fprintf(os.Stderr, "rewriting Klusters: %s\n", err.Error())
+		os.Exit(1)
+	}
+	if *dryRun {
+		return
+	}
+	if err := updateStoredVersions(ctx, apiextensions, storageVersion); err != nil {
+		fmt.Fprintf(os.Stderr, "updating storedVersions of %s: %s\n", crdName, err.Error())
+		os.Exit(1)
+	}
+}
+
+// storage version을 리턴한다. conversion webhook 없이 다시 쓰면 API server가 apiVersion만 바꾸고
+// 다른 버전에만 있는 필드는 schema pruning으로 지워지므로, strategy가 Webhook이 아니면 거절한다.
+func checkCRD(ctx context.Context, apiextensions apiextensionsclient.Interface) (string, error) {
+	crd, err := apiextensions.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, crdName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if crd.Spec.Conversion == nil || crd.Spec.Conversion.Strategy != apiextensionsv1.WebhookConverter {
+		return "", fmt.Errorf("conversion strategy is not %s, apply manifests/webhook/crd-conversion-patch.yaml first", apiextensionsv1.WebhookConverter)
+	}
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no storage version")
+}
+
+// 변경 없이 update만 해도 API server는 object를 storage version으로 encode해서 etcd에 다시 쓴다.
+func rewriteKlusters(ctx context.Context, klientset klient.Interface, dryRun bool) error {
+	list, err := klientset.Inspirit941V1beta1().Klusters(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, item := range list.Items {
+		if dryRun {
+			fmt.Printf("would rewrite Kluster %s/%s\n", item.Namespace, item.Name)
+			continue
+		}
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			k, err := klientset.Inspirit941V1beta1().Klusters(item.Namespace).Get(ctx, item.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			_, err = klientset.Inspirit941V1beta1().Klusters(k.Namespace).Update(ctx, k, metav1.UpdateOptions{})
+			return err
+		})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("Kluster %s/%s: %w", item.Namespace, item.Name, err)
+		}
+		fmt.Printf("rewrote Kluster %s/%s\n", item.Namespace, item.Name)
+	}
+	return nil
+}
+
+func updateStoredVersions(ctx context.Context, apiextensions apiextensionsclient.Interface, storageVersion string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		crd, err := apiextensions.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, crdName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		crd.Status.StoredVersions = []string{storageVersion}
+		_, err = apiextensions.ApiextensionsV1().CustomResourceDefinitions().UpdateStatus(ctx, crd, metav1.UpdateOptions{})
+		if err == nil {
+			fmt.Printf("storedVersions of %s set to %v\n", crdName, crd.Status.StoredVersions)
+		}
+		return err
+	})
+}
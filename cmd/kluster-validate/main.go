@@ -0,0 +1,78 @@
+// kluster-validate: Kluster manifest를 클러스터에 배포하기 전에 webhook / controller와 같은 규칙으로 검증하는 CLI.
+//
+//	go run ./cmd/kluster-validate manifests/kluster-cr.yaml
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1/validation"
+	klusterscheme "github.com/inspirit941/kluster/pkg/client/clientset/versioned/scheme"
+	"io"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"os"
+)
+
+func main() {
+	namespace := flag.String("namespace", "default", "namespace used for objects without metadata.namespace")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <file>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	invalid := false
+	for _, file := range flag.Args() {
+		ok, err := validateFile(file, *namespace)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", file, err.Error())
+			os.Exit(1)
+		}
+		invalid = invalid || !ok
+	}
+	if invalid {
+		os.Exit(1)
+	}
+}
+
+// 파일 안의 Kluster를 전부 검증하고, 하나라도 잘못된 게 있으면 false를 리턴한다.
+func validateFile(file, namespace string) (bool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+	ok := true
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		kluster := &v1alpha1.Kluster{}
+		if err := decoder.Decode(kluster); err != nil {
+			if errors.Is(err, io.EOF) {
+				return ok, nil
+			}
+			return false, err
+		}
+		if kluster.Kind != "Kluster" {
+			continue
+		}
+		if kluster.Namespace == "" {
+			kluster.Namespace = namespace
+		}
+		// webhook과 마찬가지로 기본값을 채운 뒤 검증
+		klusterscheme.Scheme.Default(kluster)
+		if errs := validation.ValidateKluster(kluster); len(errs) > 0 {
+			ok = false
+			for _, e := range errs {
+				fmt.Printf("%s: Kluster %s/%s: %s\n", file, kluster.Namespace, kluster.Name, e.Error())
+			}
+			continue
+		}
+		fmt.Printf("%s: Kluster %s/%s is valid\n", file, kluster.Namespace, kluster.Name)
+	}
+}
@@ -1,24 +1,46 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
-	"fmt"
 	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
 	klient "github.com/inspirit941/kluster/pkg/client/clientset/versioned"
 	"github.com/inspirit941/kluster/pkg/client/informers/externalversions"
 	"github.com/inspirit941/kluster/pkg/controller"
+	"github.com/inspirit941/kluster/pkg/digitalocean"
+	"github.com/inspirit941/kluster/pkg/policy"
+	"github.com/inspirit941/kluster/pkg/tracing"
+	"github.com/inspirit941/kluster/pkg/webhook"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"log"
+	"k8s.io/component-base/featuregate"
+	logsapi "k8s.io/component-base/logs/api/v1"
+	_ "k8s.io/component-base/logs/json/register" // --logging-format=json 사용을 위해 json 포맷을 등록
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
+	_ "time/tzdata" // alpine 이미지에는 tzdata가 없으므로 node pool schedule의 timeZone을 위해 바이너리에 포함
 )
 
 func main() {
-	k := v1alpha1.Kluster{}
-	fmt.Println(k)
+	// 0이 아니면 나머지 defer(tracing flush, klog.Flush)가 모두 실행된 뒤에 이 값으로 종료한다. 그래서 가장 먼저 defer한다.
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
 
 	// Kluster 사용
 	var kubeconfig *string
@@ -28,36 +50,182 @@ func main() {
 	} else {
 		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
 	}
-	flag.Parse()
+
+	// tracing 설정. exporter는 none(기본값) / stdout / otlp 중 하나.
+	var tracingOpts tracing.Options
+	flag.StringVar(&tracingOpts.Exporter, "tracing-exporter", tracing.ExporterNone, "OpenTelemetry trace exporter: none, stdout or otlp")
+	flag.StringVar(&tracingOpts.OTLPEndpoint, "otlp-endpoint", "localhost:4317", "OTLP gRPC collector endpoint, used when --tracing-exporter=otlp")
+	flag.BoolVar(&tracingOpts.OTLPInsecure, "otlp-insecure", false, "disable TLS when connecting to the OTLP collector")
+
+	// admission webhook 설정. 인증서가 없으면 webhook 서버를 띄우지 않는다.
+	webhookAddr := flag.String("webhook-addr", ":8443", "address the admission webhook server listens on")
+	tlsCertFile := flag.String("tls-cert-file", "", "TLS certificate for the webhook server. The webhook server is disabled when empty")
+	tlsKeyFile := flag.String("tls-private-key-file", "", "TLS private key for the webhook server")
+
+	// dry-run: 운영 중인 CR을 대상으로 새 controller를 돌려볼 때 사용. DigitalOcean 조회는 그대로 하지만 변경 요청은 로그 / 이벤트로만 남긴다.
+	dryRun := flag.Bool("dry-run", false, "log and record events for mutating DigitalOcean API calls instead of executing them")
+
+	// management cluster ID는 생성한 클러스터의 owner tag에 들어가서, orphan sweeper가 이 controller가 만든 클러스터만 보도록 한다.
+	managementClusterID := flag.String("management-cluster-id", "", "ID of the cluster this controller runs in, tagged on created clusters. Defaults to the UID of the kube-system namespace")
+	var orphanOpts controller.OrphanOptions
+	flag.DurationVar(&orphanOpts.Interval, "orphan-sweep-interval", 30*time.Minute, "interval of the sweep for DigitalOcean clusters without a matching Kluster. 0 disables the sweeper")
+	orphanTokenSecrets := flag.String("orphan-token-secrets", "", "comma separated <namespace>/<name> token secrets of DigitalOcean accounts to sweep in addition to the ones referenced by Klusters")
+	flag.BoolVar(&orphanOpts.Delete, "delete-orphans", false, "delete orphaned clusters after --orphan-grace-period. When false they are only reported via metrics and events")
+	flag.DurationVar(&orphanOpts.GracePeriod, "orphan-grace-period", 24*time.Hour, "how long a cluster has to stay orphaned before it is deleted")
+	ttlWarning := flag.Duration("ttl-warning", 15*time.Minute, "how long before a Kluster's spec.ttl expires the Expiring condition and a warning event are set")
+	metricsAddr := flag.String("metrics-addr", ":8080", "address the /metrics endpoint listens on. Disabled when empty")
+
+	// Kluster spec 기본값. mutating webhook과 controller 모두 이 값을 사용한다.
+	flag.StringVar(&v1alpha1.DefaultRegion, "default-region", v1alpha1.DefaultRegion, "region used when spec.region is empty")
+	flag.StringVar(&v1alpha1.DefaultVersion, "default-version", v1alpha1.DefaultVersion, "kubernetes version slug used when spec.version is empty")
+	flag.StringVar(&v1alpha1.DefaultTokenSecretName, "default-token-secret-name", v1alpha1.DefaultTokenSecretName, "name of the secret in the Kluster's namespace used when spec.tokenSecret is empty")
+	flag.StringVar(&v1alpha1.DefaultNodePoolSize, "default-node-pool-size", v1alpha1.DefaultNodePoolSize, "droplet size of the node pool created when spec.nodePools is empty")
+	flag.IntVar(&v1alpha1.DefaultNodePoolCount, "default-node-pool-count", v1alpha1.DefaultNodePoolCount, "node count of the node pool created when spec.nodePools is empty")
+
+	// 로깅 설정. --logging-format=text|json, -v=<level> 로 출력 형식과 verbosity를 지정한다.
+	// contextual logging을 켜야 klog.FromContext로 kluster / clusterID 등의 값이 붙은 logger를 꺼내 쓸 수 있음.
+	logConfig := logsapi.NewLoggingConfiguration()
+	featureGate := featuregate.NewFeatureGate()
+	runtime.Must(logsapi.AddFeatureGates(featureGate))
+	runtime.Must(featureGate.SetFromMap(map[string]bool{string(logsapi.ContextualLogging): true}))
+	logsapi.AddFlags(logConfig, pflag.CommandLine)
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	pflag.Parse()
+	if err := logsapi.ValidateAndApply(logConfig, featureGate); err != nil {
+		klog.ErrorS(err, "invalid logging configuration")
+		os.Exit(1)
+	}
+	defer klog.Flush()
+	logger := klog.Background()
+
+	// 잘못된 secret 참조는 sweeper goroutine에서 실패하기 전에 시작할 때 거절한다.
+	if *orphanTokenSecrets != "" {
+		orphanOpts.TokenSecrets = strings.Split(*orphanTokenSecrets, ",")
+		for _, secret := range orphanOpts.TokenSecrets {
+			if _, _, err := digitalocean.SplitSecretRef(secret); err != nil {
+				logger.Error(err, "invalid --orphan-token-secrets")
+				os.Exit(1)
+			}
+		}
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingOpts)
+	if err != nil {
+		logger.Error(err, "setting up tracing")
+		os.Exit(1)
+	}
+	// 종료할 때 아직 export되지 않은 span을 보낸다. collector가 응답하지 않아도 종료가 멈추지 않도록 timeout을 둔다.
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error(err, "shutting down tracer provider")
+		}
+	}()
 
 	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
 	if err != nil {
-		log.Printf("building config from flags, trying to build inclusterconfig. %s", err.Error())
+		logger.Info("building config from flags failed, trying to build inclusterconfig", "err", err)
 		// kubeconfig 파일이 없는 경우
 		config, err = rest.InClusterConfig() // kubernetes pod에 mount된 secret Account를 사용
 		if err != nil {
-			log.Printf("error %s building inclusterconfig: %s", err.Error())
+			logger.Error(err, "building inclusterconfig")
+			exitCode = 1
+			return
 		}
 	}
 
 	//generated code 사용
 	klientset, err := klient.NewForConfig(config)
 	if err != nil {
-		log.Printf("getting klientset %s", err.Error())
+		logger.Error(err, "getting klientset")
+		exitCode = 1
+		return
 	}
 	client, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		log.Printf("getting k8s client %s", err.Error())
+		logger.Error(err, "getting k8s client")
+		exitCode = 1
+		return
 	}
 
+	if *managementClusterID == "" {
+		// kube-system namespace UID는 클러스터마다 다르고, 클러스터가 다시 만들어지지 않는 한 바뀌지 않는다.
+		ns, err := client.CoreV1().Namespaces().Get(context.Background(), metav1.NamespaceSystem, metav1.GetOptions{})
+		if err != nil {
+			logger.Error(err, "getting kube-system namespace for the management cluster ID, set --management-cluster-id")
+			exitCode = 1
+			return
+		}
+		*managementClusterID = string(ns.UID)
+	}
+	logger.Info("management cluster", "id", *managementClusterID)
+
 	// informer를 호출하려면 informerFactory를 사용해야 함.
 	informerFactory := externalversions.NewSharedInformerFactory(klientset, 20*time.Minute) // resync 시간은 20분으로 정의.
-	// informer를 동작시키려면 chan이 필요.
+	// informer를 동작시키려면 chan이 필요. SIGTERM / SIGINT를 받으면 닫아서 informer / worker / webhook 서버를 멈춘다.
 	ch := make(chan struct{})
-	c := controller.NewController(client, klientset, informerFactory.Inspirit941().V1alpha1().Klusters())
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	c := controller.NewController(client, klientset, informerFactory.Inspirit941().V1alpha1(), controller.Options{
+		DryRun:              *dryRun,
+		ManagementClusterID: *managementClusterID,
+		TTLWarning:          *ttlWarning,
+		Orphans:             orphanOpts,
+	})
+	if *dryRun {
+		logger.Info("running in dry-run mode, mutating DigitalOcean API calls are not executed")
+	}
+
+	// webhook의 KlusterPolicy 검사에 쓰는 informer도 같이 시작되도록 Start 전에 만든다.
+	var webhookServer *webhook.Server
+	if *tlsCertFile != "" {
+		webhookServer = webhook.NewServer(*webhookAddr, *tlsCertFile, *tlsKeyFile, policy.NewChecker(informerFactory.Inspirit941().V1alpha1()))
+	}
 
 	informerFactory.Start(ch)
+	// goroutine에서 os.Exit을 호출하면 defer가 실행되지 않으므로, webhook 서버 에러는 main으로 보내서 signal과 같은 경로로 종료한다.
+	webhookErrs := make(chan error, 1)
+	if webhookServer != nil {
+		go func() {
+			webhookErrs <- webhookServer.Run(ch)
+		}()
+	} else {
+		logger.Info("--tls-cert-file is not set, admission webhook server is disabled")
+	}
+	if *metricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", legacyregistry.Handler())
+		metricsServer := &http.Server{Addr: *metricsAddr, Handler: mux}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error(err, "error running metrics server")
+			}
+		}()
+		// controller가 멈춘 뒤 tracing과 같이 timeout을 두고 닫는다.
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := metricsServer.Shutdown(ctx); err != nil {
+				logger.Error(err, "shutting down metrics server")
+			}
+		}()
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			logger.Info("received signal, shutting down")
+		case err := <-webhookErrs:
+			if err == nil {
+				err = errors.New("webhook server stopped")
+			}
+			logger.Error(err, "error running webhook server")
+			exitCode = 1
+		}
+		close(ch)
+	}()
 	if err := c.Run(ch); err != nil {
-		log.Printf("error running controller %s\n", err.Error())
+		logger.Error(err, "error running controller")
+		exitCode = 1
 	}
 }
@@ -0,0 +1,174 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1beta1"
+	"k8s.io/apimachinery/pkg/conversion"
+	"strings"
+)
+
+// hub-and-spoke conversion. v1beta1이 hub이고 v1alpha1은 spoke.
+// 필드 이름 / 타입이 같은 부분은 conversion-gen이 만든 zz_generated.conversion.go가 처리하고,
+// 구조가 다른 필드(tokenSecret, status의 cluster ID, node pool autoscaling)만 아래에서 직접 변환한다.
+
+// v1beta1에만 있는 값(provider, secret key)을 v1alpha1 object에 보관해두는 annotation.
+// v1alpha1으로 읽고 다시 쓰더라도 v1beta1 값이 유실되지 않도록 ConvertTo에서 복원한다.
+const ConversionDataAnnotation = "inspirit941.dev/conversion-data"
+
+// DefaultTokenSecretKey: v1beta1 SecretReference의 key 기본값. key를 지정하지 않은 token secret은 이 key에서 token을 읽는다.
+const DefaultTokenSecretKey = "token"
+
+// TokenSecretKey: spec.tokenSecret에서 token을 읽을 data key.
+// v1alpha1에는 key 필드가 없으므로 v1beta1로 지정한 key는 conversion-data annotation에서 읽는다.
+func TokenSecretKey(k *Kluster) string {
+	data, ok := k.Annotations[ConversionDataAnnotation]
+	if !ok {
+		return DefaultTokenSecretKey
+	}
+	restored := v1beta1.KlusterSpec{}
+	if err := json.Unmarshal([]byte(data), &restored); err != nil || restored.TokenSecretRef.Key == "" {
+		return DefaultTokenSecretKey
+	}
+	return restored.TokenSecretRef.Key
+}
+
+// ConvertTo converts this Kluster to the hub version (v1beta1).
+func (src *Kluster) ConvertTo(dst *v1beta1.Kluster) error {
+	if err := Convert_v1alpha1_Kluster_To_v1beta1_Kluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	data, ok := src.Annotations[ConversionDataAnnotation]
+	if !ok {
+		return nil
+	}
+	restored := v1beta1.KlusterSpec{}
+	if err := json.Unmarshal([]byte(data), &restored); err != nil {
+		return err
+	}
+	if restored.Provider != "" {
+		dst.Spec.Provider = restored.Provider
+	}
+	if restored.TokenSecretRef.Key != "" && dst.Spec.TokenSecretRef.Name != "" {
+		dst.Spec.TokenSecretRef.Key = restored.TokenSecretRef.Key
+	}
+	// annotation map은 src와 공유하고 있으므로 복사한 뒤 지운다.
+	dst.Annotations = copyWithout(dst.Annotations, ConversionDataAnnotation)
+	return nil
+}
+
+// ConvertFrom converts the hub version (v1beta1) to this Kluster.
+func (dst *Kluster) ConvertFrom(src *v1beta1.Kluster) error {
+	if err := Convert_v1beta1_Kluster_To_v1alpha1_Kluster(src, dst, nil); err != nil {
+		return err
+	}
+	// v1beta1에서는 secret namespace를 생략하면 Kluster의 namespace를 사용한다.
+	if ref := src.Spec.TokenSecretRef; ref.Name != "" && ref.Namespace == "" {
+		dst.Spec.TokenSecret = src.Namespace + "/" + ref.Name
+	}
+
+	dst.Annotations = copyWithout(dst.Annotations, ConversionDataAnnotation)
+	if !hasV1beta1OnlyData(&src.Spec) {
+		return nil
+	}
+	data, err := json.Marshal(src.Spec)
+	if err != nil {
+		return err
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[ConversionDataAnnotation] = string(data)
+	return nil
+}
+
+// v1alpha1로 표현할 수 없는 값이 있는지 확인. 기본값만 있으면 annotation을 남기지 않는다.
+func hasV1beta1OnlyData(spec *v1beta1.KlusterSpec) bool {
+	if spec.Provider != "" && spec.Provider != v1beta1.ProviderDigitalOcean {
+		return true
+	}
+	return spec.TokenSecretRef.Key != "" && spec.TokenSecretRef.Key != DefaultTokenSecretKey
+}
+
+func copyWithout(m map[string]string, key string) map[string]string {
+	if _, ok := m[key]; !ok {
+		return m
+	}
+	out := make(map[string]string, len(m)-1)
+	for k, v := range m {
+		if k != key {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func Convert_v1alpha1_KlusterSpec_To_v1beta1_KlusterSpec(in *KlusterSpec, out *v1beta1.KlusterSpec, s conversion.Scope) error {
+	if err := autoConvert_v1alpha1_KlusterSpec_To_v1beta1_KlusterSpec(in, out, s); err != nil {
+		return err
+	}
+	// v1alpha1은 DigitalOcean만 지원
+	out.Provider = v1beta1.ProviderDigitalOcean
+	// "<namespace>/<name>" -> SecretReference
+	if in.TokenSecret != "" {
+		out.TokenSecretRef = v1beta1.SecretReference{Name: in.TokenSecret, Key: DefaultTokenSecretKey}
+		if namespace, name, ok := strings.Cut(in.TokenSecret, "/"); ok {
+			out.TokenSecretRef.Namespace = namespace
+			out.TokenSecretRef.Name = name
+		}
+	}
+	return nil
+}
+
+func Convert_v1beta1_KlusterSpec_To_v1alpha1_KlusterSpec(in *v1beta1.KlusterSpec, out *KlusterSpec, s conversion.Scope) error {
+	if err := autoConvert_v1beta1_KlusterSpec_To_v1alpha1_KlusterSpec(in, out, s); err != nil {
+		return err
+	}
+	// SecretReference -> "<namespace>/<name>". namespace가 없는 경우는 ConvertFrom에서 Kluster의 namespace로 채운다.
+	if ref := in.TokenSecretRef; ref.Name != "" {
+		out.TokenSecret = ref.Name
+		if ref.Namespace != "" {
+			out.TokenSecret = ref.Namespace + "/" + ref.Name
+		}
+	}
+	return nil
+}
+
+func Convert_v1alpha1_KlusterStatus_To_v1beta1_KlusterStatus(in *KlusterStatus, out *v1beta1.KlusterStatus, s conversion.Scope) error {
+	if err := autoConvert_v1alpha1_KlusterStatus_To_v1beta1_KlusterStatus(in, out, s); err != nil {
+		return err
+	}
+	out.ClusterID = in.KlusterID
+	return nil
+}
+
+func Convert_v1beta1_KlusterStatus_To_v1alpha1_KlusterStatus(in *v1beta1.KlusterStatus, out *KlusterStatus, s conversion.Scope) error {
+	if err := autoConvert_v1beta1_KlusterStatus_To_v1alpha1_KlusterStatus(in, out, s); err != nil {
+		return err
+	}
+	out.KlusterID = in.ClusterID
+	return nil
+}
+
+// v1alpha1의 autoScale / minNodes / maxNodes -> v1beta1의 autoScaling. autoScale이 꺼져 있으면 min / max는 validation에서 거절되므로 버린다.
+func Convert_v1alpha1_NodePool_To_v1beta1_NodePool(in *NodePool, out *v1beta1.NodePool, s conversion.Scope) error {
+	if err := autoConvert_v1alpha1_NodePool_To_v1beta1_NodePool(in, out, s); err != nil {
+		return err
+	}
+	out.AutoScaling = nil
+	if in.AutoScale {
+		out.AutoScaling = &v1beta1.NodePoolAutoScaling{MinNodes: in.MinNodes, MaxNodes: in.MaxNodes}
+	}
+	return nil
+}
+
+func Convert_v1beta1_NodePool_To_v1alpha1_NodePool(in *v1beta1.NodePool, out *NodePool, s conversion.Scope) error {
+	if err := autoConvert_v1beta1_NodePool_To_v1alpha1_NodePool(in, out, s); err != nil {
+		return err
+	}
+	out.AutoScale, out.MinNodes, out.MaxNodes = false, 0, 0
+	if in.AutoScaling != nil {
+		out.AutoScale, out.MinNodes, out.MaxNodes = true, in.AutoScaling.MinNodes, in.AutoScaling.MaxNodes
+	}
+	return nil
+}
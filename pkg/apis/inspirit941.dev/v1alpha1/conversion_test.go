@@ -0,0 +1,199 @@
+package v1alpha1
+
+import (
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"testing"
+)
+
+func TestConvertRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		kluster *Kluster
+	}{
+		{
+			name: "minimal",
+			kluster: &Kluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kluster-0", Namespace: "default"},
+				Spec:       KlusterSpec{Name: "kluster-0", TokenSecret: "default/dosecret"},
+			},
+		},
+		{
+			name: "full",
+			kluster: &Kluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kluster-0", Namespace: "default", Annotations: map[string]string{"team": "a"}},
+				Spec: KlusterSpec{
+					Name:        "kluster-0",
+					Region:      "nyc1",
+					Version:     "1.25.4-do.0",
+					TokenSecret: "secrets/dosecret",
+					NodePools: []NodePool{
+						{Name: "pool-0", Size: "s-2vcpu-2gb", Count: 3, Labels: map[string]string{"role": "web"}},
+						{Name: "pool-1", Size: "s-2vcpu-2gb", Count: 2, AutoScale: true, MinNodes: 1, MaxNodes: 5},
+					},
+					PrimaryNodePool: "pool-0",
+					Replicas:        pointer.Int32(3),
+					HA:              pointer.Bool(true),
+					SurgeUpgrade:    pointer.Bool(false),
+					DeletionPolicy:  DeletionPolicyRetain,
+				},
+				Status: KlusterStatus{KlusterID: "1234", Progress: "running"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &v1beta1.Kluster{}
+			if err := tt.kluster.ConvertTo(hub); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+			if hub.Spec.Provider != v1beta1.ProviderDigitalOcean {
+				t.Errorf("provider = %q, want %q", hub.Spec.Provider, v1beta1.ProviderDigitalOcean)
+			}
+			if hub.Status.ClusterID != tt.kluster.Status.KlusterID {
+				t.Errorf("clusterID = %q, want %q", hub.Status.ClusterID, tt.kluster.Status.KlusterID)
+			}
+			got := &Kluster{}
+			if err := got.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			if !equality.Semantic.DeepEqual(got, tt.kluster) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.kluster)
+			}
+		})
+	}
+}
+
+func TestConvertNodePoolAutoScaling(t *testing.T) {
+	tests := []struct {
+		name string
+		pool NodePool
+		want *v1beta1.NodePoolAutoScaling
+	}{
+		{
+			name: "autoscale disabled",
+			pool: NodePool{Name: "pool-0", Size: "s-2vcpu-2gb", Count: 3},
+		},
+		{
+			name: "autoscale enabled",
+			pool: NodePool{Name: "pool-0", Size: "s-2vcpu-2gb", Count: 3, AutoScale: true, MinNodes: 1, MaxNodes: 5},
+			want: &v1beta1.NodePoolAutoScaling{MinNodes: 1, MaxNodes: 5},
+		},
+		{
+			name: "range without autoscale is dropped",
+			pool: NodePool{Name: "pool-0", Size: "s-2vcpu-2gb", Count: 3, MinNodes: 1, MaxNodes: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := v1beta1.NodePool{}
+			if err := Convert_v1alpha1_NodePool_To_v1beta1_NodePool(&tt.pool, &got, nil); err != nil {
+				t.Fatalf("conversion error = %v", err)
+			}
+			if !equality.Semantic.DeepEqual(got.AutoScaling, tt.want) {
+				t.Errorf("autoScaling = %+v, want %+v", got.AutoScaling, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertFromHub(t *testing.T) {
+	tests := []struct {
+		name            string
+		ref             v1beta1.SecretReference
+		wantTokenSecret string
+		wantKey         string
+		wantAnnotation  bool
+	}{
+		{
+			name:            "default key",
+			ref:             v1beta1.SecretReference{Namespace: "secrets", Name: "dosecret", Key: "token"},
+			wantTokenSecret: "secrets/dosecret",
+			wantKey:         "token",
+		},
+		{
+			name:            "custom key",
+			ref:             v1beta1.SecretReference{Namespace: "secrets", Name: "dosecret", Key: "do-token"},
+			wantTokenSecret: "secrets/dosecret",
+			wantKey:         "do-token",
+			wantAnnotation:  true,
+		},
+		{
+			name:            "namespace defaults to the Kluster's",
+			ref:             v1beta1.SecretReference{Name: "dosecret"},
+			wantTokenSecret: "default/dosecret",
+			wantKey:         "token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &v1beta1.Kluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kluster-0", Namespace: "default"},
+				Spec:       v1beta1.KlusterSpec{Name: "kluster-0", Provider: v1beta1.ProviderDigitalOcean, TokenSecretRef: tt.ref},
+			}
+			spoke := &Kluster{}
+			if err := spoke.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			if spoke.Spec.TokenSecret != tt.wantTokenSecret {
+				t.Errorf("tokenSecret = %q, want %q", spoke.Spec.TokenSecret, tt.wantTokenSecret)
+			}
+			if _, ok := spoke.Annotations[ConversionDataAnnotation]; ok != tt.wantAnnotation {
+				t.Errorf("has %s annotation = %v, want %v", ConversionDataAnnotation, ok, tt.wantAnnotation)
+			}
+			if got := TokenSecretKey(spoke); got != tt.wantKey {
+				t.Errorf("TokenSecretKey() = %q, want %q", got, tt.wantKey)
+			}
+
+			// v1alpha1 client로 읽고 다시 써도 key가 유지되고, annotation은 v1beta1에 남지 않는다.
+			back := &v1beta1.Kluster{}
+			if err := spoke.ConvertTo(back); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+			if back.Spec.TokenSecretRef.Key != tt.wantKey {
+				t.Errorf("key after round trip = %q, want %q", back.Spec.TokenSecretRef.Key, tt.wantKey)
+			}
+			if _, ok := back.Annotations[ConversionDataAnnotation]; ok {
+				t.Errorf("%s annotation was not removed", ConversionDataAnnotation)
+			}
+		})
+	}
+}
+
+func TestTokenSecretKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{
+		{
+			name: "no annotation",
+			want: "token",
+		},
+		{
+			name:        "key in conversion data",
+			annotations: map[string]string{ConversionDataAnnotation: `{"tokenSecretRef":{"name":"dosecret","key":"do-token"}}`},
+			want:        "do-token",
+		},
+		{
+			name:        "conversion data without key",
+			annotations: map[string]string{ConversionDataAnnotation: `{"provider":"digitalocean"}`},
+			want:        "token",
+		},
+		{
+			name:        "invalid conversion data",
+			annotations: map[string]string{ConversionDataAnnotation: `{`},
+			want:        "token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &Kluster{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if got := TokenSecretKey(k); got != tt.want {
+				t.Errorf("TokenSecretKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package v1alpha1
+
+import (
+	"context"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+	"os"
+	"sigs.k8s.io/yaml"
+	"strings"
+	"testing"
+)
+
+// crdValidator: manifests의 CRD에서 version의 schema를 읽어서, API server와 같은 방식으로 OpenAPI schema와 CEL 규칙을 검사한다.
+// controller-gen으로 다시 만든 CRD가 kubebuilder marker와 맞는지 확인하기 위한 것.
+type crdValidator struct {
+	structural *structuralschema.Structural
+	validate   func(obj interface{}) field.ErrorList
+	cel        *cel.Validator
+}
+
+func newCRDValidator(t *testing.T, path, version string) *crdValidator {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := yaml.Unmarshal(raw, crd); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Name != version {
+			continue
+		}
+		schema := &apiextensions.JSONSchemaProps{}
+		if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(v.Schema.OpenAPIV3Schema, schema, nil); err != nil {
+			t.Fatal(err)
+		}
+		structural, err := structuralschema.NewStructural(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		validator, _, err := apiservervalidation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: schema})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &crdValidator{
+			structural: structural,
+			validate: func(obj interface{}) field.ErrorList {
+				return apiservervalidation.ValidateCustomResource(nil, obj, validator)
+			},
+			cel: cel.NewValidator(structural, true, cel.PerCallLimit),
+		}
+	}
+	t.Fatalf("version %s not found in %s", version, path)
+	return nil
+}
+
+// Validate: obj(와 update면 old)를 검사한 에러 메시지들
+func (v *crdValidator) Validate(t *testing.T, obj, old runtime.Object) []string {
+	t.Helper()
+	toMap := func(obj runtime.Object) map[string]interface{} {
+		if obj == nil {
+			return nil
+		}
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u
+	}
+	newObj, oldObj := toMap(obj), toMap(old)
+	errs := v.validate(newObj)
+	var oldValue interface{}
+	if oldObj != nil {
+		oldValue = oldObj
+	}
+	celErrs, _ := v.cel.Validate(context.Background(), nil, v.structural, newObj, oldValue, cel.RuntimeCELCostBudget)
+	var messages []string
+	for _, err := range append(errs, celErrs...) {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+func crdKluster() *Kluster {
+	return &Kluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "Kluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: "kluster-0", Namespace: "default"},
+		Spec: KlusterSpec{
+			Name:            "kluster-0",
+			Region:          "nyc1",
+			Version:         "1.25.4-do.0",
+			TokenSecret:     "default/dosecret",
+			NodePools:       []NodePool{{Name: "pool-0", Size: "s-2vcpu-2gb", Count: 3}},
+			PrimaryNodePool: "pool-0",
+			DeletionPolicy:  DeletionPolicyDelete,
+			DriftPolicy:     DriftPolicyCorrect,
+		},
+	}
+}
+
+func TestKlusterCRDValidation(t *testing.T) {
+	v := newCRDValidator(t, "../../../../manifests/inspirit941.dev_klusters.yaml", "v1alpha1")
+	tests := []struct {
+		name    string
+		mutate  func(k *Kluster)
+		wantErr string
+	}{
+		{name: "valid", mutate: func(k *Kluster) {}},
+		{name: "region pattern", mutate: func(k *Kluster) { k.Spec.Region = "new-york" }, wantErr: "spec.region"},
+		{name: "node count minimum", mutate: func(k *Kluster) { k.Spec.NodePools[0].Count = 0 }, wantErr: "spec.nodePools[0].count"},
+		{name: "deletion policy enum", mutate: func(k *Kluster) { k.Spec.DeletionPolicy = "Orphan" }, wantErr: "spec.deletionPolicy"},
+		{name: "maintenance start time", mutate: func(k *Kluster) { k.Spec.MaintenancePolicy = &MaintenancePolicy{StartTime: "25:00"} }, wantErr: "spec.maintenancePolicy.startTime"},
+		{
+			name: "primary node pool not in node pools",
+			mutate: func(k *Kluster) {
+				k.Spec.PrimaryNodePool = "pool-1"
+			},
+			wantErr: "primaryNodePool must be one of nodePools",
+		},
+		{
+			name: "duplicate node pool names",
+			mutate: func(k *Kluster) {
+				k.Spec.NodePools = append(k.Spec.NodePools, k.Spec.NodePools[0])
+			},
+			wantErr: "node pool names must be unique",
+		},
+		{
+			name: "autoscale without maxNodes",
+			mutate: func(k *Kluster) {
+				k.Spec.NodePools[0].AutoScale = true
+			},
+			wantErr: "maxNodes must be set",
+		},
+		{
+			name: "autoscale with schedules",
+			mutate: func(k *Kluster) {
+				k.Spec.NodePools[0].AutoScale = true
+				k.Spec.NodePools[0].MaxNodes = 5
+				k.Spec.NodePools[0].Schedules = []ScalingSchedule{{Name: "day", Schedule: "0 9 * * *", Count: 5}}
+			},
+			wantErr: "schedules cannot be used when autoScale is enabled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kluster := crdKluster()
+			tt.mutate(kluster)
+			assertCRDErrors(t, v.Validate(t, kluster, nil), tt.wantErr)
+		})
+	}
+}
+
+// update에만 적용되는 transition rule(oldSelf)
+func TestKlusterCRDTransitionRules(t *testing.T) {
+	v := newCRDValidator(t, "../../../../manifests/inspirit941.dev_klusters.yaml", "v1alpha1")
+	tests := []struct {
+		name    string
+		old     func(k *Kluster)
+		mutate  func(k *Kluster)
+		wantErr string
+	}{
+		{name: "version upgrade", mutate: func(k *Kluster) { k.Spec.Version = "1.26.3-do.0" }},
+		{name: "name", mutate: func(k *Kluster) { k.Spec.Name = "kluster-1" }, wantErr: "name is immutable"},
+		{name: "region", mutate: func(k *Kluster) { k.Spec.Region = "sfo3" }, wantErr: "region is immutable"},
+		{
+			name:    "vpcUUID",
+			old:     func(k *Kluster) { k.Spec.VPCUUID = "c33931f2-a26a-4e61-b85c-4e95a2ec431b" },
+			mutate:  func(k *Kluster) { k.Spec.VPCUUID = "d33931f2-a26a-4e61-b85c-4e95a2ec431b" },
+			wantErr: "vpcUUID is immutable",
+		},
+		{name: "enable ha", old: func(k *Kluster) { k.Spec.HA = pointer.Bool(false) }, mutate: func(k *Kluster) { k.Spec.HA = pointer.Bool(true) }},
+		{name: "disable ha", old: func(k *Kluster) { k.Spec.HA = pointer.Bool(true) }, mutate: func(k *Kluster) { k.Spec.HA = pointer.Bool(false) }, wantErr: "ha cannot be disabled once enabled"},
+		{
+			name:    "disable surgeUpgrade",
+			old:     func(k *Kluster) { k.Spec.SurgeUpgrade = pointer.Bool(true) },
+			mutate:  func(k *Kluster) { k.Spec.SurgeUpgrade = pointer.Bool(false) },
+			wantErr: "surgeUpgrade cannot be disabled once enabled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := crdKluster()
+			if tt.old != nil {
+				tt.old(old)
+			}
+			kluster := old.DeepCopy()
+			tt.mutate(kluster)
+			assertCRDErrors(t, v.Validate(t, kluster, old), tt.wantErr)
+		})
+	}
+}
+
+func assertCRDErrors(t *testing.T, errs []string, wantErr string) {
+	t.Helper()
+	if wantErr == "" {
+		if len(errs) > 0 {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+		return
+	}
+	for _, err := range errs {
+		if strings.Contains(err, wantErr) {
+			return
+		}
+	}
+	t.Errorf("errors = %v, want one containing %q", errs, wantErr)
+}
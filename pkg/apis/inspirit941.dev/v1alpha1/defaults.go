@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// spec에 값이 없을 때 채워넣을 기본값. operator 설정(main.go의 --default-* flag)으로 덮어쓸 수 있다.
+var (
+	// DigitalOcean API는 version slug로 "latest"를 받으면 최신 stable 버전으로 클러스터를 만든다.
+	DefaultVersion = "latest"
+	DefaultRegion  = "nyc1"
+	// tokenSecret이 비어 있으면 Kluster와 같은 namespace의 이 이름을 가진 secret을 사용한다.
+	DefaultTokenSecretName = "dosecret"
+
+	DefaultNodePoolName  = "default-pool"
+	DefaultNodePoolSize  = "s-2vcpu-2gb"
+	DefaultNodePoolCount = 3
+)
+
+// defaulter-gen이 만든 RegisterDefaults를 scheme에 등록. scheme.Default(obj)를 호출하면 SetObjectDefaults_Kluster가 실행된다.
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	return RegisterDefaults(scheme)
+}
+
+// SetDefaults_Kluster: metadata 값이 필요한 기본값은 Kluster 단위에서 채운다.
+// template을 참조하는 Kluster는 template과 합친 뒤에 기본값을 채워야 template 값이 기본값에 가려지지 않는다.
+// 그래서 webhook에서는 name만 채우고, 나머지는 controller가 MergeTemplate 이후에 채운다.
+func SetDefaults_Kluster(obj *Kluster) {
+	if obj.Spec.Name == "" {
+		obj.Spec.Name = obj.Name
+	}
+	if obj.Spec.Template != "" {
+		return
+	}
+	if obj.Spec.TokenSecret == "" && obj.Namespace != "" {
+		obj.Spec.TokenSecret = obj.Namespace + "/" + DefaultTokenSecretName
+	}
+}
+
+func SetDefaults_KlusterSpec(obj *KlusterSpec) {
+	if obj.Template != "" {
+		return
+	}
+	if obj.Version == "" {
+		obj.Version = DefaultVersion
+	}
+	if obj.Region == "" {
+		obj.Region = DefaultRegion
+	}
+	if obj.DeletionPolicy == "" {
+		obj.DeletionPolicy = DeletionPolicyDelete
+	}
+	if obj.DriftPolicy == "" {
+		obj.DriftPolicy = DriftPolicyCorrect
+	}
+	if len(obj.NodePools) == 0 {
+		obj.NodePools = []NodePool{
+			{
+				Name:  DefaultNodePoolName,
+				Size:  DefaultNodePoolSize,
+				Count: DefaultNodePoolCount,
+			},
+		}
+	}
+	setPrimaryNodePoolDefaults(obj)
+}
+
+// scale subresource는 spec.replicas 하나만 바꾸므로, replicas가 primary node pool의 count를 덮어쓰도록 맞춘다.
+// replicas가 없으면 반대로 pool의 count로 채운다.
+func setPrimaryNodePoolDefaults(obj *KlusterSpec) {
+	if obj.PrimaryNodePool == "" && len(obj.NodePools) > 0 {
+		obj.PrimaryNodePool = obj.NodePools[0].Name
+	}
+	for i := range obj.NodePools {
+		pool := &obj.NodePools[i]
+		if pool.Name != obj.PrimaryNodePool {
+			continue
+		}
+		if obj.Replicas == nil {
+			replicas := int32(pool.Count)
+			obj.Replicas = &replicas
+		} else {
+			pool.Count = int(*obj.Replicas)
+		}
+		return
+	}
+}
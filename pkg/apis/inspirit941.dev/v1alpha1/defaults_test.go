@@ -0,0 +1,89 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"testing"
+)
+
+func TestSetObjectDefaultsKluster(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "kluster-0", Namespace: "team-a"}
+	tests := []struct {
+		name string
+		spec KlusterSpec
+		want KlusterSpec
+	}{
+		{
+			name: "empty spec",
+			want: KlusterSpec{
+				Name:            "kluster-0",
+				Region:          DefaultRegion,
+				Version:         DefaultVersion,
+				TokenSecret:     "team-a/" + DefaultTokenSecretName,
+				NodePools:       []NodePool{{Name: DefaultNodePoolName, Size: DefaultNodePoolSize, Count: DefaultNodePoolCount}},
+				PrimaryNodePool: DefaultNodePoolName,
+				Replicas:        pointer.Int32(int32(DefaultNodePoolCount)),
+				DeletionPolicy:  DeletionPolicyDelete,
+				DriftPolicy:     DriftPolicyCorrect,
+			},
+		},
+		{
+			name: "values are kept",
+			spec: KlusterSpec{
+				Name:           "other",
+				Region:         "sfo3",
+				Version:        "1.25.4-do.0",
+				TokenSecret:    "secrets/dosecret",
+				NodePools:      []NodePool{{Name: "web", Size: "s-4vcpu-8gb", Count: 2}},
+				DeletionPolicy: DeletionPolicyRetain,
+				DriftPolicy:    DriftPolicyReport,
+			},
+			want: KlusterSpec{
+				Name:            "other",
+				Region:          "sfo3",
+				Version:         "1.25.4-do.0",
+				TokenSecret:     "secrets/dosecret",
+				NodePools:       []NodePool{{Name: "web", Size: "s-4vcpu-8gb", Count: 2}},
+				PrimaryNodePool: "web",
+				Replicas:        pointer.Int32(2),
+				DeletionPolicy:  DeletionPolicyRetain,
+				DriftPolicy:     DriftPolicyReport,
+			},
+		},
+		{
+			name: "replicas overrides the primary pool count",
+			spec: KlusterSpec{
+				NodePools:       []NodePool{{Name: "web", Size: "s-2vcpu-2gb", Count: 2}, {Name: "batch", Size: "s-2vcpu-2gb", Count: 1}},
+				PrimaryNodePool: "batch",
+				Replicas:        pointer.Int32(4),
+			},
+			want: KlusterSpec{
+				Name:            "kluster-0",
+				Region:          DefaultRegion,
+				Version:         DefaultVersion,
+				TokenSecret:     "team-a/" + DefaultTokenSecretName,
+				NodePools:       []NodePool{{Name: "web", Size: "s-2vcpu-2gb", Count: 2}, {Name: "batch", Size: "s-2vcpu-2gb", Count: 4}},
+				PrimaryNodePool: "batch",
+				Replicas:        pointer.Int32(4),
+				DeletionPolicy:  DeletionPolicyDelete,
+				DriftPolicy:     DriftPolicyCorrect,
+			},
+		},
+		{
+			// template과 합치기 전에 기본값을 채우면 template 값이 가려지므로 name만 채운다.
+			name: "template",
+			spec: KlusterSpec{Template: "nyc"},
+			want: KlusterSpec{Name: "kluster-0", Template: "nyc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kluster := &Kluster{ObjectMeta: meta, Spec: tt.spec}
+			SetObjectDefaults_Kluster(kluster)
+			if !equality.Semantic.DeepEqual(kluster.Spec, tt.want) {
+				t.Errorf("spec = %+v, want %+v", kluster.Spec, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,6 @@
 // +k8s:deepcopy-gen=package
 // +k8s:defaulter-gen=TypeMeta
+// +k8s:conversion-gen=github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1beta1
 // +groupName=inspirit941.dev
 
 package v1alpha1
@@ -0,0 +1,67 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KlusterPolicy limits the Klusters of its namespace. When a namespace has several policies a Kluster has to satisfy all of them.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type KlusterPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KlusterPolicySpec `json:"spec,omitempty"`
+}
+
+// KlusterPolicySpec is the set of rules the Klusters of the namespace must follow. Empty fields do not restrict anything.
+type KlusterPolicySpec struct {
+	// Regions Klusters may be created in.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:items:Pattern=`^[a-z]{3}[0-9]$`
+	AllowedRegions []string `json:"allowedRegions,omitempty"`
+	// Droplet size slugs node pools may use.
+	// +optional
+	// +listType=set
+	AllowedNodeSizes []string `json:"allowedNodeSizes,omitempty"`
+	// Kubernetes versions Klusters may run. A version has to be in one of the ranges.
+	// "latest" is not allowed when ranges are set, because the version it resolves to is not known in advance.
+	// +optional
+	AllowedVersions []VersionRange `json:"allowedVersions,omitempty"`
+	// Maximum number of nodes of a node pool. For autoscaling pools maxNodes is checked, for scheduled pools the largest count.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxNodesPerPool *int32 `json:"maxNodesPerPool,omitempty"`
+	// Maximum number of nodes of all Klusters in the namespace, counted the same way as maxNodesPerPool.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxNodesPerNamespace *int32 `json:"maxNodesPerNamespace,omitempty"`
+	// Tags every Kluster must have in spec.tags.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_:\-]{1,255}$`
+	RequiredTags []string `json:"requiredTags,omitempty"`
+}
+
+// VersionRange is an inclusive range of Kubernetes versions, i.e. min 1.25 and max 1.26 allows 1.25.0 up to any 1.26 patch.
+type VersionRange struct {
+	// Lowest allowed version, major.minor or major.minor.patch.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+(\.[0-9]+)?$`
+	Min string `json:"min,omitempty"`
+	// Highest allowed version, major.minor or major.minor.patch. With major.minor all patches of that minor version are allowed.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+(\.[0-9]+)?$`
+	Max string `json:"max,omitempty"`
+}
+
+// KlusterPolicyList is a list of KlusterPolicies.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KlusterPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KlusterPolicy `json:"items,omitempty"`
+}
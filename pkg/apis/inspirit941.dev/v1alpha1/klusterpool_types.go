@@ -0,0 +1,159 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KlusterPool keeps a number of ready Klusters created from the same spec, so that a KlusterClaim can bind one in seconds.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Size",type=integer,JSONPath=`.spec.size`
+// +kubebuilder:validation:XValidation:rule="size(self.metadata.name) <= 56",message="name must be no more than 56 characters, Klusters of the pool are named <pool name>-<index>"
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.ready`
+// +kubebuilder:printcolumn:name="Provisioning",type=integer,JSONPath=`.status.provisioning`
+// +kubebuilder:printcolumn:name="Claimed",type=integer,JSONPath=`.status.claimed`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type KlusterPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KlusterPoolSpec   `json:"spec,omitempty"`
+	Status KlusterPoolStatus `json:"status,omitempty"`
+}
+
+// KlusterPoolSpec is the desired number and spec of the unclaimed Klusters of the pool.
+type KlusterPoolSpec struct {
+	// Number of unclaimed Klusters to keep. Claimed Klusters are backfilled.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=50
+	Size int32 `json:"size"`
+	// Name of a KlusterTemplate in the same namespace used by the Klusters of the pool.
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	Template string `json:"template,omitempty"`
+	// Spec of the Klusters of the pool. The name of each cluster is generated from the pool name.
+	// When it changes, unclaimed Klusters created from the previous spec are deleted and recreated. Claimed Klusters are not changed.
+	// +optional
+	KlusterSpec KlusterTemplateSpec `json:"klusterSpec,omitempty"`
+}
+
+// KlusterPoolStatus is the observed state of the pool's Klusters.
+type KlusterPoolStatus struct {
+	// Number of unclaimed Klusters whose cluster is running.
+	// +optional
+	Ready int32 `json:"ready,omitempty"`
+	// Number of unclaimed Klusters whose cluster is still being provisioned.
+	// +optional
+	Provisioning int32 `json:"provisioning,omitempty"`
+	// Number of Klusters of the pool bound to a KlusterClaim.
+	// +optional
+	Claimed int32 `json:"claimed,omitempty"`
+	// Generation of the pool that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// KlusterPoolList is a list of KlusterPools.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KlusterPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KlusterPool `json:"items,omitempty"`
+}
+
+// KlusterClaim binds a ready Kluster of a KlusterPool and receives its kubeconfig in a Secret.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Pool",type=string,JSONPath=`.spec.poolName`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Kluster",type=string,JSONPath=`.status.klusterName`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type KlusterClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KlusterClaimSpec   `json:"spec,omitempty"`
+	Status KlusterClaimStatus `json:"status,omitempty"`
+}
+
+// KlusterClaimSpec is the pool to claim a Kluster from and what happens to it on release.
+type KlusterClaimSpec struct {
+	// Name of the KlusterPool in the same namespace. Cannot be changed.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="poolName is immutable"
+	PoolName string `json:"poolName"`
+	// What happens to the bound Kluster when the claim is released or deleted. Delete deletes the Kluster,
+	// Retain keeps it as a standalone Kluster outside of the pool. Defaults to Delete.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	ReleasePolicy ReleasePolicy `json:"releasePolicy,omitempty"`
+	// Release the bound Kluster while keeping the claim. Cannot be undone.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self || !oldSelf",message="released cannot be unset"
+	Released bool `json:"released,omitempty"`
+	// Name of the Secret the kubeconfig of the bound cluster is written to, under the "kubeconfig" key.
+	// Defaults to <claim name>-kubeconfig.
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	KubeconfigSecretName string `json:"kubeconfigSecretName,omitempty"`
+}
+
+// ReleasePolicy decides what happens to a claimed Kluster when its claim is released.
+type ReleasePolicy string
+
+const (
+	ReleasePolicyDelete ReleasePolicy = "Delete"
+	ReleasePolicyRetain ReleasePolicy = "Retain"
+)
+
+// ClaimPhase is the binding state of a KlusterClaim.
+type ClaimPhase string
+
+const (
+	// 바인딩할 ready Kluster를 기다리는 중
+	ClaimPhasePending ClaimPhase = "Pending"
+	// Kluster가 바인딩되어 kubeconfig secret이 만들어진 상태
+	ClaimPhaseBound ClaimPhase = "Bound"
+	// Kluster를 놓아준 상태. 다시 바인딩하지 않는다.
+	ClaimPhaseReleased ClaimPhase = "Released"
+)
+
+// KlusterClaimStatus is the binding state of the claim.
+type KlusterClaimStatus struct {
+	// Pending, Bound or Released.
+	// +optional
+	Phase ClaimPhase `json:"phase,omitempty"`
+	// Name of the bound Kluster.
+	// +optional
+	KlusterName string `json:"klusterName,omitempty"`
+	// Name of the Secret holding the kubeconfig of the bound cluster.
+	// +optional
+	KubeconfigSecret string `json:"kubeconfigSecret,omitempty"`
+	// Time the Kluster was bound.
+	// +optional
+	BoundAt *metav1.Time `json:"boundAt,omitempty"`
+	// Time the Kluster was released.
+	// +optional
+	ReleasedAt *metav1.Time `json:"releasedAt,omitempty"`
+}
+
+// KlusterClaimList is a list of KlusterClaims.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KlusterClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KlusterClaim `json:"items,omitempty"`
+}
+
+// pool이 만든 Kluster에 붙는 label. 값은 pool 이름.
+const PoolLabel = "inspirit941.dev/pool"
+
+// claim에 바인딩된 Kluster에 붙는 label. 값은 claim 이름.
+const ClaimLabel = "inspirit941.dev/claim"
+
+// claim이 삭제될 때 releasePolicy에 따라 Kluster를 정리한 뒤에 object가 지워지도록 붙이는 finalizer.
+const ClaimFinalizer = "inspirit941.dev/claim-release"
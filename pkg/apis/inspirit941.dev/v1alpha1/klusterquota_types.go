@@ -0,0 +1,62 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KlusterQuota caps the estimated monthly cost of the Klusters of its namespace.
+// When a namespace has several quotas a Kluster has to fit in all of them.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Budget",type=string,JSONPath=`.spec.monthlyBudget`
+// +kubebuilder:printcolumn:name="Used",type=string,JSONPath=`.status.used`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type KlusterQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KlusterQuotaSpec   `json:"spec,omitempty"`
+	Status KlusterQuotaStatus `json:"status,omitempty"`
+}
+
+// KlusterQuotaSpec is the budget of the namespace.
+type KlusterQuotaSpec struct {
+	// Maximum estimated monthly cost in USD of all Klusters in the namespace, i.e. "500" or "499.99".
+	// Creating a Kluster or changing node pools in a way that raises the total above the budget is refused.
+	// Lowering the budget does not affect clusters that already exist.
+	// The budget is enforced by the controller, not at admission: a Kluster over the budget is accepted by the API server,
+	// but its cloud cluster is not created or changed until it fits, which is reported by the QuotaExceeded condition.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]{1,2})?$`
+	MonthlyBudget string `json:"monthlyBudget"`
+}
+
+// KlusterQuotaStatus is the current usage of the namespace.
+type KlusterQuotaStatus struct {
+	// Sum of the estimated monthly cost in USD of the Klusters in the namespace.
+	// +optional
+	Used string `json:"used,omitempty"`
+	// Estimated monthly cost of each Kluster in the namespace that has one.
+	// +optional
+	Klusters []KlusterQuotaUsage `json:"klusters,omitempty"`
+	// Generation of the spec that was last reported.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// KlusterQuotaUsage is the estimated monthly cost of one Kluster.
+type KlusterQuotaUsage struct {
+	// Name of the Kluster.
+	Name string `json:"name"`
+	// Estimated monthly cost in USD, copied from status.estimatedMonthlyCost of the Kluster.
+	MonthlyCost string `json:"monthlyCost"`
+}
+
+// KlusterQuotaList is a list of KlusterQuotas.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KlusterQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KlusterQuota `json:"items,omitempty"`
+}
@@ -0,0 +1,145 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KlusterRollout upgrades the Klusters selected by a label selector to a target version in waves.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.version`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Wave",type=integer,JSONPath=`.status.currentWave`
+// +kubebuilder:printcolumn:name="Upgraded",type=integer,JSONPath=`.status.upgraded`
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=`.status.total`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type KlusterRollout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KlusterRolloutSpec   `json:"spec,omitempty"`
+	Status KlusterRolloutStatus `json:"status,omitempty"`
+}
+
+// KlusterRolloutSpec is the Klusters to upgrade, the target version and how the upgrade is split into waves.
+// Changing the selector or the version plans the rollout again. The other fields apply to the next plan,
+// except paused, soakTime and progressDeadline which apply right away.
+type KlusterRolloutSpec struct {
+	// Label selector of the Klusters in the same namespace to upgrade.
+	Selector metav1.LabelSelector `json:"selector"`
+	// Kubernetes version slug to upgrade to, i.e. 1.25.4-do.0.
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+\.[0-9]+-do\.[0-9]+$`
+	Version string `json:"version"`
+	// Number of Klusters in the first (canary) wave. Defaults to 1, 0 skips the canary wave.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Canary *int32 `json:"canary,omitempty"`
+	// Maximum number of Klusters upgraded at the same time in the waves after the canary. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxConcurrent int32 `json:"maxConcurrent,omitempty"`
+	// How long every cluster of a wave has to stay running on the new version before the next wave starts. Defaults to 10m.
+	// +optional
+	SoakTime *metav1.Duration `json:"soakTime,omitempty"`
+	// How long a cluster may take to run on the new version before it is marked failed. Defaults to 60m.
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+	// Stops starting new upgrades. Set by the controller when a cluster fails, set it to false to continue with the failed
+	// clusters left as they are.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// RolloutPhase is the overall state of a KlusterRollout.
+type RolloutPhase string
+
+const (
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	RolloutPhasePaused      RolloutPhase = "Paused"
+	RolloutPhaseCompleted   RolloutPhase = "Completed"
+)
+
+// RolloutKlusterPhase is the upgrade state of a Kluster in a KlusterRollout.
+type RolloutKlusterPhase string
+
+const (
+	// 아직 wave 차례가 오지 않음
+	RolloutKlusterPending RolloutKlusterPhase = "Pending"
+	// spec.version을 바꾸고 새 버전으로 running이 되기를 기다리는 중
+	RolloutKlusterUpgrading RolloutKlusterPhase = "Upgrading"
+	// 새 버전으로 running이 된 뒤 soakTime 동안 지켜보는 중
+	RolloutKlusterSoaking   RolloutKlusterPhase = "Soaking"
+	RolloutKlusterSucceeded RolloutKlusterPhase = "Succeeded"
+	// degraded / error 상태가 되거나 progressDeadline 안에 끝나지 않음. rollout이 자동으로 멈춘다.
+	RolloutKlusterFailed RolloutKlusterPhase = "Failed"
+	// pause 중이거나 삭제되는 등 upgrade할 수 없는 Kluster
+	RolloutKlusterSkipped RolloutKlusterPhase = "Skipped"
+)
+
+// KlusterRolloutStatus is the plan and progress of the rollout.
+type KlusterRolloutStatus struct {
+	// Progressing, Paused or Completed.
+	// +optional
+	Phase RolloutPhase `json:"phase,omitempty"`
+	// Human readable reason of the phase, i.e. which cluster failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Index of the wave being upgraded. The canary wave is 0.
+	// +optional
+	CurrentWave int32 `json:"currentWave,omitempty"`
+	// Number of waves.
+	// +optional
+	Waves int32 `json:"waves,omitempty"`
+	// Number of selected Klusters.
+	// +optional
+	Total int32 `json:"total,omitempty"`
+	// Number of Klusters running on the target version.
+	// +optional
+	Upgraded int32 `json:"upgraded,omitempty"`
+	// Upgrade progress of each selected Kluster.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Klusters []RolloutKlusterStatus `json:"klusters,omitempty"`
+	// Version the plan was made for.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Selector the plan was made for, in the kubectl label selector format.
+	// +optional
+	Selector string `json:"selector,omitempty"`
+	// Generation of the rollout that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// RolloutKlusterStatus is the upgrade progress of a Kluster.
+type RolloutKlusterStatus struct {
+	// Name of the Kluster.
+	Name string `json:"name"`
+	// Wave the Kluster is upgraded in.
+	Wave int32 `json:"wave"`
+	// Pending, Upgrading, Soaking, Succeeded, Failed or Skipped.
+	Phase RolloutKlusterPhase `json:"phase"`
+	// spec.version of the Kluster before the upgrade.
+	// +optional
+	PreviousVersion string `json:"previousVersion,omitempty"`
+	// Time spec.version was changed.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// Time the cluster was running on the new version.
+	// +optional
+	ReadyAt *metav1.Time `json:"readyAt,omitempty"`
+	// Reason of a Failed or Skipped phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// KlusterRolloutList is a list of KlusterRollouts.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KlusterRolloutList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KlusterRollout `json:"items,omitempty"`
+}
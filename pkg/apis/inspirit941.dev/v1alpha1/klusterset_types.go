@@ -0,0 +1,101 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KlusterSet creates and owns one Kluster per region from a common spec.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:validation:XValidation:rule="size(self.metadata.name) <= 58",message="name must be no more than 58 characters, Klusters of the set are named <set name>-<region>"
+// +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.status.desiredRegions`
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyRegions`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type KlusterSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KlusterSetSpec   `json:"spec,omitempty"`
+	Status KlusterSetStatus `json:"status,omitempty"`
+}
+
+// KlusterSetSpec is the list of regions and the spec shared by the Klusters of the set.
+// +kubebuilder:validation:XValidation:rule="!has(self.klusterSpec) || !has(self.klusterSpec.region)",message="klusterSpec.region must not be set, the region of each Kluster comes from regions"
+type KlusterSetSpec struct {
+	// Regions to create a Kluster in, e.g. nyc1, sgp1. Removing a region deletes its Kluster.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	// +kubebuilder:validation:items:Pattern=`^[a-z]{3}[0-9]$`
+	Regions []string `json:"regions"`
+	// Name of a KlusterTemplate in the same namespace used by the Klusters of the set.
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	Template string `json:"template,omitempty"`
+	// Spec shared by the Klusters of the set. Changes are applied to all Klusters. The cluster name of each Kluster is <set name>-<region>.
+	// +optional
+	KlusterSpec KlusterTemplateSpec `json:"klusterSpec,omitempty"`
+}
+
+// KlusterSetStatus is the aggregated state of the Klusters of the set.
+type KlusterSetStatus struct {
+	// Number of regions in the spec.
+	// +optional
+	DesiredRegions int32 `json:"desiredRegions,omitempty"`
+	// Number of regions whose cluster is running.
+	// +optional
+	ReadyRegions int32 `json:"readyRegions,omitempty"`
+	// State of the Kluster of each region.
+	// +optional
+	// +listType=map
+	// +listMapKey=region
+	Regions []KlusterSetRegionStatus `json:"regions,omitempty"`
+	// Generation of the set that was last applied to its Klusters.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Latest observations of the set's state, one per condition type.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// KlusterSetRegionStatus is the state of the Kluster of a region.
+type KlusterSetRegionStatus struct {
+	// Region of the Kluster.
+	Region string `json:"region"`
+	// Name of the Kluster.
+	// +optional
+	Kluster string `json:"kluster,omitempty"`
+	// ID of the DigitalOcean cluster.
+	// +optional
+	KlusterID string `json:"klusterID,omitempty"`
+	// Progress of the Kluster, i.e. creating or running.
+	// +optional
+	Progress string `json:"progress,omitempty"`
+	// True when the cluster is running.
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+}
+
+// KlusterSetList is a list of KlusterSets.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KlusterSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KlusterSet `json:"items,omitempty"`
+}
+
+// set이 만든 Kluster에 붙는 label. 값은 set 이름.
+const SetLabel = "inspirit941.dev/set"
+
+// set이 만든 Kluster에 붙는 label. 값은 Kluster를 만든 region.
+// spec.region은 template에서 올 수 있어서 비어 있을 수 있으므로, set은 이 label로 region별 Kluster를 찾는다.
+const SetRegionLabel = "inspirit941.dev/set-region"
+
+// KlusterSet의 status.conditions type 값
+const (
+	// 모든 region의 클러스터가 running이면 True.
+	ConditionReady = "Ready"
+)
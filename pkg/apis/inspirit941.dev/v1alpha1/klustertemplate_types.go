@@ -0,0 +1,88 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KlusterTemplate is a reusable partial KlusterSpec. Klusters in the same namespace reference it with spec.template.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:printcolumn:name="Region",type=string,JSONPath=`.spec.region`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.version`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type KlusterTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KlusterTemplateSpec `json:"spec,omitempty"`
+}
+
+// template에는 클러스터마다 달라야 하는 값(name, replicas)과 template 참조를 제외한 KlusterSpec 필드가 들어간다.
+// 모든 필드가 optional이고, 필드 이름과 의미는 KlusterSpec과 같다.
+
+// KlusterTemplateSpec holds the KlusterSpec fields shared by the Klusters using the template. Every field is optional.
+// +kubebuilder:validation:XValidation:rule="!has(self.primaryNodePool) || (has(self.nodePools) && self.nodePools.exists(p, p.name == self.primaryNodePool))",message="primaryNodePool must be one of nodePools"
+type KlusterTemplateSpec struct {
+	// Region slug the cluster is created in, i.e. nyc1.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z]{3}[0-9]$`
+	Region string `json:"region,omitempty"`
+	// Kubernetes version slug, i.e. 1.25.4-do.0, or "latest" for the latest stable version.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(latest|[0-9]+\.[0-9]+\.[0-9]+-do\.[0-9]+)$`
+	Version string `json:"version,omitempty"`
+	// Secret holding the DigitalOcean API token under the "token" key, in the form <namespace>/<name>.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?/[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	TokenSecret string `json:"tokenSecret,omitempty"`
+	// Node pools of the cluster. A node pool of the Kluster with the same name replaces the pool of the template.
+	// +optional
+	// +kubebuilder:validation:MaxItems=32
+	// +kubebuilder:validation:XValidation:rule="self.all(p, self.exists_one(q, q.name == p.name))",message="node pool names must be unique"
+	NodePools []NodePool `json:"nodePools,omitempty"`
+	// Name of the node pool resized by the scale subresource.
+	// +optional
+	PrimaryNodePool string `json:"primaryNodePool,omitempty"`
+	// UUID of the VPC the cluster is created in.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`
+	VPCUUID string `json:"vpcUUID,omitempty"`
+	// Tags applied to the cluster.
+	// +optional
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_:\-]{1,255}$`
+	Tags []string `json:"tags,omitempty"`
+	// Run a highly available control plane.
+	// +optional
+	HA *bool `json:"ha,omitempty"`
+	// Upgrade the cluster to the latest patch release automatically during the maintenance window.
+	// +optional
+	AutoUpgrade *bool `json:"autoUpgrade,omitempty"`
+	// Create new nodes before draining old ones during upgrades.
+	// +optional
+	SurgeUpgrade *bool `json:"surgeUpgrade,omitempty"`
+	// Maintenance window for automatic upgrades.
+	// +optional
+	MaintenancePolicy *MaintenancePolicy `json:"maintenancePolicy,omitempty"`
+	// Integrate the account's DigitalOcean Container Registry with the cluster.
+	// +optional
+	RegistryEnabled *bool `json:"registryEnabled,omitempty"`
+	// What happens to the cloud cluster when the Kluster is deleted.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// Whether drift found on resync is corrected or only reported.
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// Lifetime of the Kluster counted from its creation.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// KlusterTemplateList is a list of KlusterTemplates.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KlusterTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KlusterTemplate `json:"items,omitempty"`
+}
@@ -14,7 +14,9 @@ var SchemeGroupVersion = schema.GroupVersion{
 
 var (
 	SchemeBuilder runtime.SchemeBuilder
-	AddToScheme   = SchemeBuilder.AddToScheme // go build에서 발생하는 undefined 에러 해결
+	// conversion-gen이 만든 zz_generated.conversion.go는 localSchemeBuilder에 RegisterConversions를 등록한다.
+	localSchemeBuilder = &SchemeBuilder
+	AddToScheme        = SchemeBuilder.AddToScheme // go build에서 발생하는 undefined 에러 해결
 )
 
 // code-generator가 구현한 lister에서 client.Resource("kluster") 메소드가 에러 남. Resource를 리턴하는 메소드 추가 구현.
@@ -24,11 +26,20 @@ func Resource(resource string) schema.GroupResource {
 
 // 패키지가 로드될 때 Kluster라는 struct를 등록.
 func init() {
-	SchemeBuilder.Register(addKnownTypes) // register 안에 파라미터로 들어갈 function이 type을 scheme에 등록하는 역할을 수행함.
+	SchemeBuilder.Register(addKnownTypes, addDefaultingFuncs) // register 안에 파라미터로 들어갈 function이 type을 scheme에 등록하는 역할을 수행함.
 }
 
 func addKnownTypes(scheme *runtime.Scheme) error {
-	scheme.AddKnownTypes(SchemeGroupVersion, &Kluster{}, &KlusterList{})
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&Kluster{}, &KlusterList{},
+		&KlusterTemplate{}, &KlusterTemplateList{},
+		&KlusterPool{}, &KlusterPoolList{},
+		&KlusterClaim{}, &KlusterClaimList{},
+		&KlusterSet{}, &KlusterSetList{},
+		&KlusterRollout{}, &KlusterRolloutList{},
+		&KlusterPolicy{}, &KlusterPolicyList{},
+		&KlusterQuota{}, &KlusterQuotaList{},
+	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,79 @@
+package v1alpha1
+
+// MergeTemplate: Kluster spec과 template을 합친 spec을 리턴한다. 우선순위는 Kluster > template > 기본값(defaults.go).
+//   - 값이 있는(zero value가 아닌) Kluster 필드가 template 필드보다 우선한다.
+//   - node pool은 이름으로 합친다. 같은 이름의 pool은 Kluster의 pool 전체가 template의 pool을 대체하고, 나머지는 template 순서 뒤에 붙는다.
+//   - bool 필드(ha, autoUpgrade, surgeUpgrade, registryEnabled)는 pointer라서, Kluster에 명시한 false도 template의 true보다 우선한다.
+//
+// 리턴된 spec의 Template은 비워서, 이후 scheme.Default가 합쳐진 spec에 기본값을 채우도록 한다.
+func MergeTemplate(spec KlusterSpec, template KlusterTemplateSpec) KlusterSpec {
+	merged := *spec.DeepCopy()
+	merged.Template = ""
+	t := template.DeepCopy()
+
+	if merged.Region == "" {
+		merged.Region = t.Region
+	}
+	if merged.Version == "" {
+		merged.Version = t.Version
+	}
+	if merged.TokenSecret == "" {
+		merged.TokenSecret = t.TokenSecret
+	}
+	merged.NodePools = mergeNodePools(t.NodePools, merged.NodePools)
+	if merged.PrimaryNodePool == "" {
+		merged.PrimaryNodePool = t.PrimaryNodePool
+	}
+	if merged.VPCUUID == "" {
+		merged.VPCUUID = t.VPCUUID
+	}
+	if len(merged.Tags) == 0 {
+		merged.Tags = t.Tags
+	}
+	if merged.HA == nil {
+		merged.HA = t.HA
+	}
+	if merged.AutoUpgrade == nil {
+		merged.AutoUpgrade = t.AutoUpgrade
+	}
+	if merged.SurgeUpgrade == nil {
+		merged.SurgeUpgrade = t.SurgeUpgrade
+	}
+	if merged.MaintenancePolicy == nil {
+		merged.MaintenancePolicy = t.MaintenancePolicy
+	}
+	if merged.RegistryEnabled == nil {
+		merged.RegistryEnabled = t.RegistryEnabled
+	}
+	if merged.DeletionPolicy == "" {
+		merged.DeletionPolicy = t.DeletionPolicy
+	}
+	if merged.DriftPolicy == "" {
+		merged.DriftPolicy = t.DriftPolicy
+	}
+	if merged.TTL == nil {
+		merged.TTL = t.TTL
+	}
+	return merged
+}
+
+func mergeNodePools(template, overrides []NodePool) []NodePool {
+	byName := map[string]NodePool{}
+	for _, pool := range overrides {
+		byName[pool.Name] = pool
+	}
+	var merged []NodePool
+	for _, pool := range template {
+		if override, ok := byName[pool.Name]; ok {
+			pool = override
+			delete(byName, pool.Name)
+		}
+		merged = append(merged, pool)
+	}
+	for _, pool := range overrides {
+		if _, ok := byName[pool.Name]; ok {
+			merged = append(merged, pool)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,123 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/pointer"
+	"testing"
+)
+
+func TestMergeTemplate(t *testing.T) {
+	template := KlusterTemplateSpec{
+		Region:       "nyc1",
+		Version:      "1.25.4-do.0",
+		TokenSecret:  "secrets/dosecret",
+		NodePools:    []NodePool{{Name: "web", Size: "s-2vcpu-2gb", Count: 3}, {Name: "batch", Size: "s-4vcpu-8gb", Count: 1}},
+		Tags:         []string{"team-a"},
+		HA:           pointer.Bool(true),
+		AutoUpgrade:  pointer.Bool(true),
+		SurgeUpgrade: pointer.Bool(true),
+		DriftPolicy:  DriftPolicyReport,
+	}
+	tests := []struct {
+		name string
+		spec KlusterSpec
+		want KlusterSpec
+	}{
+		{
+			name: "template fills empty fields",
+			spec: KlusterSpec{Template: "small", Name: "kluster-0"},
+			want: KlusterSpec{
+				Name:         "kluster-0",
+				Region:       "nyc1",
+				Version:      "1.25.4-do.0",
+				TokenSecret:  "secrets/dosecret",
+				NodePools:    template.NodePools,
+				Tags:         []string{"team-a"},
+				HA:           pointer.Bool(true),
+				AutoUpgrade:  pointer.Bool(true),
+				SurgeUpgrade: pointer.Bool(true),
+				DriftPolicy:  DriftPolicyReport,
+			},
+		},
+		{
+			name: "Kluster values take precedence",
+			spec: KlusterSpec{Template: "small", Name: "kluster-0", Region: "sfo3", Tags: []string{"team-b"}, DriftPolicy: DriftPolicyCorrect},
+			want: KlusterSpec{
+				Name:         "kluster-0",
+				Region:       "sfo3",
+				Version:      "1.25.4-do.0",
+				TokenSecret:  "secrets/dosecret",
+				NodePools:    template.NodePools,
+				Tags:         []string{"team-b"},
+				HA:           pointer.Bool(true),
+				AutoUpgrade:  pointer.Bool(true),
+				SurgeUpgrade: pointer.Bool(true),
+				DriftPolicy:  DriftPolicyCorrect,
+			},
+		},
+		{
+			name: "explicit false overrides the template",
+			spec: KlusterSpec{Template: "small", Name: "kluster-0", AutoUpgrade: pointer.Bool(false), SurgeUpgrade: pointer.Bool(false)},
+			want: KlusterSpec{
+				Name:         "kluster-0",
+				Region:       "nyc1",
+				Version:      "1.25.4-do.0",
+				TokenSecret:  "secrets/dosecret",
+				NodePools:    template.NodePools,
+				Tags:         []string{"team-a"},
+				HA:           pointer.Bool(true),
+				AutoUpgrade:  pointer.Bool(false),
+				SurgeUpgrade: pointer.Bool(false),
+				DriftPolicy:  DriftPolicyReport,
+			},
+		},
+		{
+			name: "node pools are merged by name",
+			spec: KlusterSpec{
+				Template:  "small",
+				Name:      "kluster-0",
+				NodePools: []NodePool{{Name: "gpu", Size: "g-2vcpu-8gb", Count: 1}, {Name: "web", Size: "s-2vcpu-2gb", Count: 5}},
+			},
+			want: KlusterSpec{
+				Name:        "kluster-0",
+				Region:      "nyc1",
+				Version:     "1.25.4-do.0",
+				TokenSecret: "secrets/dosecret",
+				NodePools: []NodePool{
+					{Name: "web", Size: "s-2vcpu-2gb", Count: 5},
+					{Name: "batch", Size: "s-4vcpu-8gb", Count: 1},
+					{Name: "gpu", Size: "g-2vcpu-8gb", Count: 1},
+				},
+				Tags:         []string{"team-a"},
+				HA:           pointer.Bool(true),
+				AutoUpgrade:  pointer.Bool(true),
+				SurgeUpgrade: pointer.Bool(true),
+				DriftPolicy:  DriftPolicyReport,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeTemplate(tt.spec, template)
+			if !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("MergeTemplate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// 합친 spec을 바꿔도 template은 바뀌지 않아야 한다. template은 lister cache의 object이므로 공유된다.
+func TestMergeTemplateDoesNotShareTemplate(t *testing.T) {
+	template := KlusterTemplateSpec{
+		NodePools: []NodePool{{Name: "web", Size: "s-2vcpu-2gb", Count: 3}},
+		Tags:      []string{"team-a"},
+		HA:        pointer.Bool(true),
+	}
+	merged := MergeTemplate(KlusterSpec{Template: "small"}, template)
+	merged.NodePools[0].Count = 5
+	merged.Tags[0] = "team-b"
+	*merged.HA = false
+	if template.NodePools[0].Count != 3 || template.Tags[0] != "team-a" || !*template.HA {
+		t.Errorf("template was modified through the merged spec: %+v", template)
+	}
+}
@@ -4,11 +4,20 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Kluster is a DigitalOcean Kubernetes cluster provisioned and managed by the kluster operator.
 // +genclient
+// +genclient:method=GetScale,verb=get,subresource=scale,result=k8s.io/api/autoscaling/v1.Scale
+// +genclient:method=UpdateScale,verb=update,subresource=scale,input=k8s.io/api/autoscaling/v1.Scale,result=k8s.io/api/autoscaling/v1.Scale
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="ClusterID",type=string,JSONPath=`.status.klusterID`
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:printcolumn:name="ClusterID",type=string,JSONPath=`.status.KlusterID`
 // +kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.progress`
+// +kubebuilder:printcolumn:name="Region",type=string,JSONPath=`.spec.region`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.version`
+// +kubebuilder:printcolumn:name="Nodes",type=integer,JSONPath=`.status.nodes`
+// +kubebuilder:printcolumn:name="Expires",type=string,format=date-time,JSONPath=`.status.expiresAt`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 type Kluster struct {
 	// k8s object / resource는 세 개의 main field가 필요함.
 	metav1.TypeMeta   `json:",inline"`            // type meta: which particular type of resources it is. client-go의 metav1을 쓸 수 있다.
@@ -18,21 +27,208 @@ type Kluster struct {
 	Status KlusterStatus `json:"status,omitempty"` // subresource. kubebuilder 어노테이션으로 code generate를 사용한다.
 }
 
+// operator가 DigitalOcean API를 호출할 때 input으로 필요한 값.
+// 아래 kubebuilder validation / CEL marker는 controller-gen이 CRD schema로 옮겨주므로, webhook 없이도 API server가 잘못된 object를 거절한다.
+// 기본값이 있는 필드(defaults.go)는 mutating webhook / controller가 채우므로 optional로 둔다.
+// 필드 doc comment는 CRD의 description이 되므로 영어로 작성.
+
+// KlusterSpec is the desired state of the DigitalOcean cluster.
+// +kubebuilder:validation:XValidation:rule="!has(self.primaryNodePool) || has(self.template) || (has(self.nodePools) && self.nodePools.exists(p, p.name == self.primaryNodePool))",message="primaryNodePool must be one of nodePools"
 type KlusterSpec struct {
-	// specify the field needed when the operator runs
-	// input으로 필요한 값.
-	Name        string `json:"name,omitempty"`
-	Region      string `json:"region,omitempty"`
-	Version     string `json:"version,omitempty"`
+	// Name of a KlusterTemplate in the same namespace. Fields set in this spec take precedence over the template,
+	// node pools are merged by name, and defaults are applied to the merged spec.
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	Template string `json:"template,omitempty"`
+	// Name of the DigitalOcean cluster. Defaults to metadata.name. Cannot be changed after creation.
+	// +optional
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	Name string `json:"name,omitempty"`
+	// Region slug the cluster is created in, i.e. nyc1. Cannot be changed after creation.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z]{3}[0-9]$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="region is immutable"
+	Region string `json:"region,omitempty"`
+	// Kubernetes version slug, i.e. 1.25.4-do.0, or "latest" for the latest stable version.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(latest|[0-9]+\.[0-9]+\.[0-9]+-do\.[0-9]+)$`
+	Version string `json:"version,omitempty"`
+	// Secret holding the DigitalOcean API token under the "token" key, in the form <namespace>/<name>, i.e. default/dosecret.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?/[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
 	TokenSecret string `json:"tokenSecret,omitempty"` // digitalOcean에서는 token이 있어야 api 호출이 가능. 따라서 새 필드 추가. digitalOcean token값을 평문으로 넣는 게 아니라, token이 저장된 K8s secret의 이름을 넣는다. i.e. default/dosecret
 
+	// Node pools of the cluster. Pool names must be unique.
+	// +optional
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=32
+	// +kubebuilder:validation:XValidation:rule="self.all(p, self.exists_one(q, q.name == p.name))",message="node pool names must be unique"
 	NodePools []NodePool `json:"nodePools,omitempty"` // digitalOcean api를 보면 size, name, count 값이 required인 array임.
+
+	// Name of the node pool resized by the scale subresource (kubectl scale). Defaults to the first node pool.
+	// +optional
+	PrimaryNodePool string `json:"primaryNodePool,omitempty"`
+	// Node count of the primary node pool. Overrides the count of that pool, so that the scale subresource can resize it.
+	// Defaults to the count of the primary node pool.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=512
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// UUID of the VPC the cluster is created in. Defaults to the region's default VPC. Cannot be changed after creation.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="vpcUUID is immutable"
+	VPCUUID string `json:"vpcUUID,omitempty"`
+	// Tags applied to the cluster. DigitalOcean additionally tags every cluster with k8s and k8s:<cluster id>.
+	// +optional
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_:\-]{1,255}$`
+	Tags []string `json:"tags,omitempty"`
+	// Run a highly available control plane. HA can be enabled on an existing cluster but cannot be disabled.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self || !oldSelf",message="ha cannot be disabled once enabled"
+	HA *bool `json:"ha,omitempty"`
+	// Upgrade the cluster to the latest patch release automatically during the maintenance window.
+	// +optional
+	AutoUpgrade *bool `json:"autoUpgrade,omitempty"`
+	// Create new nodes before draining old ones during upgrades. Surge upgrade can be enabled on an existing cluster but cannot be disabled.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self || !oldSelf",message="surgeUpgrade cannot be disabled once enabled"
+	SurgeUpgrade *bool `json:"surgeUpgrade,omitempty"`
+	// Maintenance window for automatic upgrades. DigitalOcean picks a window when empty.
+	// +optional
+	MaintenancePolicy *MaintenancePolicy `json:"maintenancePolicy,omitempty"`
+	// Integrate the account's DigitalOcean Container Registry with the cluster.
+	// +optional
+	RegistryEnabled *bool `json:"registryEnabled,omitempty"`
+	// What happens to the cloud cluster when the Kluster is deleted. Delete removes the cluster,
+	// Retain keeps it running and only removes the tags that mark it as managed by the Kluster. Defaults to Delete.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// Whether drift between the spec and the cloud cluster found on resync is corrected or only reported
+	// in the Drifted condition. Changes to the spec are always applied. Defaults to Correct.
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// Lifetime of the Kluster counted from its creation, i.e. 4h. When it expires the Kluster is deleted,
+	// and the cloud cluster with it according to deletionPolicy.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
 }
 
+// DeletionPolicy decides what happens to the cloud cluster when its Kluster is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain
+type DeletionPolicy string
+
+const (
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
+// DriftPolicy decides what the controller does when the cloud cluster no longer matches the spec.
+// +kubebuilder:validation:Enum=Correct;Report
+type DriftPolicy string
+
+const (
+	DriftPolicyCorrect DriftPolicy = "Correct"
+	DriftPolicyReport  DriftPolicy = "Report"
+)
+
+// MaintenancePolicy is the weekly window DigitalOcean may use for maintenance and automatic upgrades.
+type MaintenancePolicy struct {
+	// Day of the week, or "any".
+	// +optional
+	// +kubebuilder:validation:Enum=any;monday;tuesday;wednesday;thursday;friday;saturday;sunday
+	Day string `json:"day,omitempty"`
+	// Start time of the window in UTC, in the form HH:MM.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	StartTime string `json:"startTime,omitempty"`
+}
+
+// NodePool is a group of droplets of the same size in the cluster.
+// +kubebuilder:validation:XValidation:rule="!has(self.autoScale) || !self.autoScale || (has(self.maxNodes) && (has(self.minNodes) ? self.minNodes : 0) <= self.maxNodes)",message="maxNodes must be set and not less than minNodes when autoScale is enabled"
+// +kubebuilder:validation:XValidation:rule="!has(self.autoScale) || !self.autoScale || !has(self.schedules) || size(self.schedules) == 0",message="schedules cannot be used when autoScale is enabled"
 type NodePool struct {
-	Size  string `json:"size,omitempty"`
-	Name  string `json:"name,omitempty"`
-	Count int    `json:"count,omitempty"`
+	// Droplet size slug of the nodes, i.e. s-2vcpu-2gb.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+(-[a-z0-9]+)+$`
+	Size string `json:"size,omitempty"`
+	// Name of the node pool, unique within the cluster.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name,omitempty"`
+	// Number of nodes in the pool. When autoScale is enabled this is the initial node count.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=512
+	Count int `json:"count,omitempty"`
+	// Enable the cluster autoscaler for the pool. Count is then only the initial node count.
+	// +optional
+	AutoScale bool `json:"autoScale,omitempty"`
+	// Minimum number of nodes the autoscaler can scale the pool down to.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=512
+	MinNodes int `json:"minNodes,omitempty"`
+	// Maximum number of nodes the autoscaler can scale the pool up to. Required when autoScale is enabled.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=512
+	MaxNodes int `json:"maxNodes,omitempty"`
+	// Kubernetes labels applied to the nodes of the pool.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Kubernetes taints applied to the nodes of the pool.
+	// +optional
+	// +kubebuilder:validation:MaxItems=50
+	Taints []Taint `json:"taints,omitempty"`
+	// Tags applied to the droplets of the pool.
+	// +optional
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_:\-]{1,255}$`
+	Tags []string `json:"tags,omitempty"`
+	// Cron schedules that change the node count of the pool, i.e. scale down at night and on weekends.
+	// The count of the schedule that fired last is used until the next schedule fires or count is changed in the spec.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=20
+	Schedules []ScalingSchedule `json:"schedules,omitempty"`
+}
+
+// ScalingSchedule sets the node count of a node pool at the times given by a cron expression.
+type ScalingSchedule struct {
+	// Name of the schedule, unique within the node pool.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Cron expression in the standard five field format, i.e. "0 20 * * 1-5" for 20:00 on weekdays.
+	Schedule string `json:"schedule"`
+	// IANA time zone the schedule is evaluated in, i.e. Asia/Seoul. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// Node count of the pool from the time the schedule fires.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=512
+	Count int `json:"count"`
+}
+
+// Taint is a Kubernetes taint applied to the nodes of a node pool.
+type Taint struct {
+	// Taint key.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+	// Taint value.
+	// +optional
+	Value string `json:"value,omitempty"`
+	// Taint effect.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=NoSchedule;PreferNoSchedule;NoExecute
+	Effect string `json:"effect"`
 }
 
 // kubectl pod list처럼 kluster list 명령어로 리스트 호출하기 위해 정의.
@@ -44,8 +240,165 @@ type KlusterList struct {
 	Items []Kluster `json:"items,omitempty"`
 }
 
+// KlusterStatus is the observed state of the DigitalOcean cluster, written by the controller.
 type KlusterStatus struct {
-	KlusterID  string `json:"KlusterID,omitempty"`
-	Progress   string `json:"progress,omitempty"`
+	// ID of the DigitalOcean cluster.
+	KlusterID string `json:"KlusterID,omitempty"`
+	// Provisioning progress of the cluster, i.e. creating, running, degraded or upgrading.
+	Progress string `json:"progress,omitempty"`
+	// Kubeconfig of the cluster.
 	KubeConfig string `json:"kubeConfig,omitempty"`
+	// Kubernetes version slug the cluster is running.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// URL of the Kubernetes API server.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// Public IPv4 address of the Kubernetes API server.
+	// +optional
+	IPv4 string `json:"ipv4,omitempty"`
+	// UUID of the VPC the cluster runs in.
+	// +optional
+	VPCUUID string `json:"vpcUUID,omitempty"`
+	// Pod network CIDR of the cluster.
+	// +optional
+	ClusterSubnet string `json:"clusterSubnet,omitempty"`
+	// Service network CIDR of the cluster.
+	// +optional
+	ServiceSubnet string `json:"serviceSubnet,omitempty"`
+	// Human readable message from the provider about the cluster's state.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Time the cluster was created in the provider.
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+	// Time the cluster was last updated in the provider.
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+	// Total number of nodes across all node pools.
+	// +optional
+	Nodes int32 `json:"nodes,omitempty"`
+
+	// Time the Kluster expires and is deleted, set when spec.ttl is set.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// Estimated monthly cost of the node pools in USD, i.e. "72.00", recorded when the spec is applied to the cloud cluster.
+	// A higher cost is reserved before it is applied. Counted against the KlusterQuotas of the namespace.
+	// +optional
+	EstimatedMonthlyCost string `json:"estimatedMonthlyCost,omitempty"`
+	// Node count of the spec last applied to the cloud cluster, counting maxNodes of autoscaled pools and the largest
+	// schedule count. Used as the Kluster's current usage for the namespace node limit of KlusterPolicies.
+	// +optional
+	AppliedNodes int32 `json:"appliedNodes,omitempty"`
+
+	// Generation of the spec that was last applied to the cloud cluster.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Generation of the KlusterTemplate that was last applied to the cloud cluster.
+	// +optional
+	ObservedTemplateGeneration int64 `json:"observedTemplateGeneration,omitempty"`
+	// Actual node count of the primary node pool.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// Label selector of the nodes of the primary node pool, used by the scale subresource.
+	// +optional
+	Selector string `json:"selector,omitempty"`
+	// Observed state of each node pool.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	NodePools []NodePoolStatus `json:"nodePools,omitempty"`
+
+	// State of the scheduled scaling of each node pool that has schedules.
+	// +optional
+	// +listType=map
+	// +listMapKey=nodePool
+	ScheduledScaling []ScheduledScalingStatus `json:"scheduledScaling,omitempty"`
+
+	// Latest observations of the Kluster's state, one per condition type.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// NodePoolStatus is the observed state of a node pool.
+type NodePoolStatus struct {
+	// Name of the node pool.
+	Name string `json:"name"`
+	// ID of the node pool in the provider.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Actual number of nodes in the pool.
+	// +optional
+	Count int `json:"count,omitempty"`
+	// Nodes of the pool.
+	// +optional
+	Nodes []NodeStatus `json:"nodes,omitempty"`
+}
+
+// ScheduledScalingStatus is the state of the schedules of a node pool.
+type ScheduledScalingStatus struct {
+	// Name of the node pool.
+	NodePool string `json:"nodePool"`
+	// Schedule whose count is in effect. Empty when the count in the spec is used.
+	// +optional
+	ActiveSchedule string `json:"activeSchedule,omitempty"`
+	// Node count set by the active schedule.
+	// +optional
+	Count int `json:"count,omitempty"`
+	// Count in the spec when the active schedule fired. Once the spec count is changed the spec is used again.
+	// +optional
+	SpecCount int `json:"specCount,omitempty"`
+	// Time the active schedule fired.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// Schedule that fires next.
+	// +optional
+	NextSchedule string `json:"nextSchedule,omitempty"`
+	// Time the next schedule fires.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+	// Node count the pool is scaled to when the next schedule fires.
+	// +optional
+	NextCount int `json:"nextCount,omitempty"`
 }
+
+// NodeStatus is the observed state of a node in a node pool.
+type NodeStatus struct {
+	// Name of the node.
+	Name string `json:"name"`
+	// Provisioning state of the node, i.e. provisioning, running or draining.
+	// +optional
+	State string `json:"state,omitempty"`
+}
+
+// status.conditions의 type 값
+const (
+	// spec 검증에 실패하면 True. 이 상태에서는 DigitalOcean API를 호출하지 않는다.
+	ConditionInvalidSpec = "InvalidSpec"
+	// adopt 대상 클러스터가 spec과 일치해서 Kluster가 관리하기 시작하면 True.
+	ConditionAdopted = "Adopted"
+	// PausedAnnotation이 "true"이면 True. 이 상태에서는 DigitalOcean 클러스터를 변경하는 API를 호출하지 않는다.
+	ConditionPaused = "Paused"
+	// DigitalOcean 클러스터가 spec과 다르면 True. message에 필드별 차이가 들어간다.
+	ConditionDrifted = "Drifted"
+	// spec.ttl로 정한 만료 시각이 controller의 경고 시간(--ttl-warning) 안으로 들어오면 True.
+	ConditionExpiring = "Expiring"
+	// spec.template의 KlusterTemplate을 찾아서 spec과 합쳤으면 True. template이 없으면 False이고 DigitalOcean 클러스터를 변경하지 않는다.
+	ConditionTemplateResolved = "TemplateResolved"
+	// namespace의 KlusterPolicy를 어기면 True. message에 어긴 규칙이 들어가고, DigitalOcean 클러스터를 변경하지 않는다.
+	ConditionPolicyViolation = "PolicyViolation"
+	// spec을 반영하면 namespace의 KlusterQuota 예산을 넘으면 True. 클러스터를 생성하거나 비용이 늘어나는 변경을 하지 않는다.
+	ConditionQuotaExceeded = "QuotaExceeded"
+)
+
+// "true"로 설정하면 controller가 status만 갱신하고 DigitalOcean 클러스터를 생성 / 변경 / 삭제하지 않는다. 장애 대응 중 특정 클러스터를 고정할 때 사용.
+const PausedAnnotation = "inspirit941.dev/paused"
+
+// 이미 DigitalOcean에 있는 클러스터를 새로 만들지 않고 Kluster로 가져올 때, 해당 클러스터 ID를 이 annotation에 지정한다.
+// status에 cluster ID가 기록된 이후에는 사용하지 않는다.
+const AdoptClusterIDAnnotation = "inspirit941.dev/adopt-cluster-id"
+
+// Kluster가 삭제될 때 deletionPolicy에 따라 DigitalOcean 클러스터를 정리한 뒤에 object가 지워지도록 붙이는 finalizer.
+const KlusterFinalizer = "inspirit941.dev/cluster-cleanup"
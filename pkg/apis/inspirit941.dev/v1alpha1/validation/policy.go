@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/version"
+	"strings"
+)
+
+// ValidatePolicy: template과 합치고 기본값을 채운 spec이 KlusterPolicy 하나의 규칙을 지키는지 확인한다.
+// namespace 전체 노드 수(maxNodesPerNamespace)는 다른 Kluster를 알아야 하므로 호출하는 쪽에서 확인한다.
+func ValidatePolicy(spec *v1alpha1.KlusterSpec, policy *v1alpha1.KlusterPolicy, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	p := policy.Spec
+	notAllowed := func(allowed []string) string {
+		return fmt.Sprintf("not allowed by KlusterPolicy %s, allowed: %s", policy.Name, strings.Join(allowed, ", "))
+	}
+
+	if len(p.AllowedRegions) > 0 && !sets.NewString(p.AllowedRegions...).Has(spec.Region) {
+		errs = append(errs, field.Invalid(fldPath.Child("region"), spec.Region, notAllowed(p.AllowedRegions)))
+	}
+	if len(p.AllowedVersions) > 0 && !versionAllowed(spec.Version, p.AllowedVersions) {
+		var ranges []string
+		for _, r := range p.AllowedVersions {
+			ranges = append(ranges, versionRangeString(r))
+		}
+		errs = append(errs, field.Invalid(fldPath.Child("version"), spec.Version, notAllowed(ranges)))
+	}
+	if missing := sets.NewString(p.RequiredTags...).Difference(sets.NewString(spec.Tags...)); missing.Len() > 0 {
+		errs = append(errs, field.Required(fldPath.Child("tags"), fmt.Sprintf("KlusterPolicy %s requires tags: %s", policy.Name, strings.Join(missing.List(), ", "))))
+	}
+	for i, pool := range spec.NodePools {
+		poolPath := fldPath.Child("nodePools").Index(i)
+		if len(p.AllowedNodeSizes) > 0 && !sets.NewString(p.AllowedNodeSizes...).Has(pool.Size) {
+			errs = append(errs, field.Invalid(poolPath.Child("size"), pool.Size, notAllowed(p.AllowedNodeSizes)))
+		}
+		if p.MaxNodesPerPool != nil && PoolNodes(pool) > int(*p.MaxNodesPerPool) {
+			errs = append(errs, field.Invalid(poolPath, PoolNodes(pool), fmt.Sprintf("KlusterPolicy %s allows at most %d nodes per node pool", policy.Name, *p.MaxNodesPerPool)))
+		}
+	}
+	return errs
+}
+
+// PoolNodes: policy에서 세는 node pool의 노드 수. autoScale pool은 maxNodes, schedule이 있는 pool은 가장 큰 count.
+func PoolNodes(pool v1alpha1.NodePool) int {
+	if pool.AutoScale {
+		return pool.MaxNodes
+	}
+	nodes := pool.Count
+	for _, schedule := range pool.Schedules {
+		if schedule.Count > nodes {
+			nodes = schedule.Count
+		}
+	}
+	return nodes
+}
+
+// KlusterNodes: spec의 모든 node pool 노드 수의 합
+func KlusterNodes(spec *v1alpha1.KlusterSpec) int {
+	nodes := 0
+	for _, pool := range spec.NodePools {
+		nodes += PoolNodes(pool)
+	}
+	return nodes
+}
+
+// version slug(i.e. 1.25.4-do.0)가 범위 중 하나에 들어가면 true. latest는 실제 버전을 미리 알 수 없으므로 허용하지 않는다.
+func versionAllowed(slug string, ranges []v1alpha1.VersionRange) bool {
+	v, err := version.ParseGeneric(strings.SplitN(slug, "-", 2)[0])
+	if err != nil {
+		return false
+	}
+	for _, r := range ranges {
+		if versionInRange(v, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// max가 major.minor이면 그 minor 버전의 모든 patch를 허용한다.
+func versionInRange(v *version.Version, r v1alpha1.VersionRange) bool {
+	if r.Min != "" {
+		min, err := version.ParseGeneric(r.Min)
+		if err != nil || v.LessThan(min) {
+			return false
+		}
+	}
+	if r.Max != "" {
+		max, err := version.ParseGeneric(r.Max)
+		if err != nil {
+			return false
+		}
+		if strings.Count(r.Max, ".") == 1 {
+			// 1.26이면 1.26.x까지
+			return v.Major() < max.Major() || (v.Major() == max.Major() && v.Minor() <= max.Minor())
+		}
+		return !max.LessThan(v)
+	}
+	return true
+}
+
+func versionRangeString(r v1alpha1.VersionRange) string {
+	switch {
+	case r.Min == "" && r.Max == "":
+		return "any"
+	case r.Min == "":
+		return "<= " + r.Max
+	case r.Max == "":
+		return ">= " + r.Min
+	default:
+		return r.Min + " - " + r.Max
+	}
+}
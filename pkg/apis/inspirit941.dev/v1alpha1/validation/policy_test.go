@@ -0,0 +1,86 @@
+package validation
+
+import (
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+	"testing"
+)
+
+func TestValidatePolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy v1alpha1.KlusterPolicySpec
+		mutate func(spec *v1alpha1.KlusterSpec)
+		want   []string
+	}{
+		{name: "empty policy"},
+		{name: "allowed region", policy: v1alpha1.KlusterPolicySpec{AllowedRegions: []string{"nyc1", "fra1"}}},
+		{name: "region", policy: v1alpha1.KlusterPolicySpec{AllowedRegions: []string{"fra1"}}, want: []string{"FieldValueInvalid spec.region"}},
+		{name: "size", policy: v1alpha1.KlusterPolicySpec{AllowedNodeSizes: []string{"s-1vcpu-2gb"}}, want: []string{"FieldValueInvalid spec.nodePools[0].size"}},
+		{name: "version in range", policy: v1alpha1.KlusterPolicySpec{AllowedVersions: []v1alpha1.VersionRange{{Min: "1.24.0", Max: "1.25.4"}}}},
+		// max가 major.minor면 그 minor의 모든 patch를 허용한다.
+		{name: "version in minor", policy: v1alpha1.KlusterPolicySpec{AllowedVersions: []v1alpha1.VersionRange{{Max: "1.25"}}}},
+		{name: "version above max", policy: v1alpha1.KlusterPolicySpec{AllowedVersions: []v1alpha1.VersionRange{{Max: "1.25.3"}}}, want: []string{"FieldValueInvalid spec.version"}},
+		{name: "version below min", policy: v1alpha1.KlusterPolicySpec{AllowedVersions: []v1alpha1.VersionRange{{Min: "1.26"}}}, want: []string{"FieldValueInvalid spec.version"}},
+		{
+			name:   "version in one of the ranges",
+			policy: v1alpha1.KlusterPolicySpec{AllowedVersions: []v1alpha1.VersionRange{{Max: "1.23"}, {Min: "1.25.0"}}},
+		},
+		// latest는 실제 버전을 미리 알 수 없으므로 허용하지 않는다.
+		{
+			name:   "latest version",
+			policy: v1alpha1.KlusterPolicySpec{AllowedVersions: []v1alpha1.VersionRange{{Min: "1.24.0"}}},
+			mutate: func(spec *v1alpha1.KlusterSpec) { spec.Version = "latest" },
+			want:   []string{"FieldValueInvalid spec.version"},
+		},
+		{name: "required tags", policy: v1alpha1.KlusterPolicySpec{RequiredTags: []string{"team-a"}}, want: []string{"FieldValueRequired spec.tags"}},
+		{
+			name:   "required tags set",
+			policy: v1alpha1.KlusterPolicySpec{RequiredTags: []string{"team-a"}},
+			mutate: func(spec *v1alpha1.KlusterSpec) { spec.Tags = []string{"team-a", "env-dev"} },
+		},
+		{name: "nodes per pool", policy: v1alpha1.KlusterPolicySpec{MaxNodesPerPool: pointer.Int32(2)}, want: []string{"FieldValueInvalid spec.nodePools[0]"}},
+		// autoScale pool은 maxNodes, schedule이 있는 pool은 가장 큰 count로 센다.
+		{
+			name:   "autoscaled nodes per pool",
+			policy: v1alpha1.KlusterPolicySpec{MaxNodesPerPool: pointer.Int32(3)},
+			mutate: func(spec *v1alpha1.KlusterSpec) {
+				spec.NodePools[0].AutoScale, spec.NodePools[0].MinNodes, spec.NodePools[0].MaxNodes = true, 1, 5
+			},
+			want: []string{"FieldValueInvalid spec.nodePools[0]"},
+		},
+		{
+			name:   "scheduled nodes per pool",
+			policy: v1alpha1.KlusterPolicySpec{MaxNodesPerPool: pointer.Int32(3)},
+			mutate: func(spec *v1alpha1.KlusterSpec) {
+				spec.NodePools[0].Schedules = []v1alpha1.ScalingSchedule{{Name: "day", Schedule: "0 8 * * *", Count: 6}}
+			},
+			want: []string{"FieldValueInvalid spec.nodePools[0]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := validKluster().Spec
+			if tt.mutate != nil {
+				tt.mutate(&spec)
+			}
+			policy := &v1alpha1.KlusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "default"}, Spec: tt.policy}
+			if got := errorFields(ValidatePolicy(&spec, policy, field.NewPath("spec"))); !equalStrings(got, tt.want) {
+				t.Errorf("ValidatePolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKlusterNodes(t *testing.T) {
+	spec := &v1alpha1.KlusterSpec{NodePools: []v1alpha1.NodePool{
+		{Name: "fixed", Count: 3},
+		{Name: "autoscaled", Count: 2, AutoScale: true, MinNodes: 1, MaxNodes: 4},
+		{Name: "scheduled", Count: 1, Schedules: []v1alpha1.ScalingSchedule{{Name: "day", Count: 5}, {Name: "night", Count: 2}}},
+	}}
+	if got := KlusterNodes(spec); got != 12 {
+		t.Errorf("KlusterNodes() = %d, want 12", got)
+	}
+}
@@ -0,0 +1,381 @@
+package validation
+
+import (
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// webhook, controller, kluster-validate CLI가 같은 규칙으로 Kluster를 검증하도록 한 곳에 모아둔다.
+
+const (
+	// DigitalOcean 노드 풀 하나에 만들 수 있는 최대 노드 수
+	MaxNodesPerPool = 512
+	// version을 지정하지 않고 최신 stable 버전을 쓰겠다는 의미의 slug
+	LatestVersion = "latest"
+	// DigitalOcean 클러스터 하나에 붙일 수 있는 최대 tag 수
+	MaxTags = 50
+)
+
+var (
+	// droplet size slug. i.e. s-2vcpu-2gb, g-4vcpu-16gb
+	sizeSlugRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)+$`)
+	// DigitalOcean kubernetes version slug. i.e. 1.25.4-do.0
+	versionSlugRegexp = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+-do\.[0-9]+$`)
+	// region slug. i.e. nyc1, sfo3
+	regionSlugRegexp = regexp.MustCompile(`^[a-z]{3}[0-9]$`)
+	// VPC / cluster UUID
+	uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	// DigitalOcean tag. 영문, 숫자, '_', ':', '-' 만 허용
+	tagRegexp = regexp.MustCompile(`^[a-zA-Z0-9_:\-]{1,255}$`)
+	// maintenance window 시작 시각 (UTC). i.e. 04:00
+	startTimeRegexp = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+	// godo.KubernetesMaintenanceToDay가 받는 값
+	taintEffects     = sets.NewString(string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute))
+	deletionPolicies = sets.NewString(string(v1alpha1.DeletionPolicyDelete), string(v1alpha1.DeletionPolicyRetain))
+	driftPolicies    = sets.NewString(string(v1alpha1.DriftPolicyCorrect), string(v1alpha1.DriftPolicyReport))
+	maintenanceDays  = sets.NewString("any", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday")
+)
+
+// ValidateKluster: 생성 시점의 Kluster 검증
+func ValidateKluster(k *v1alpha1.Kluster) field.ErrorList {
+	errs := ValidateKlusterSpec(&k.Spec, field.NewPath("spec"))
+	if id, ok := k.Annotations[v1alpha1.AdoptClusterIDAnnotation]; ok && !uuidRegexp.MatchString(id) {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "annotations").Key(v1alpha1.AdoptClusterIDAnnotation), id, "must be a DigitalOcean cluster ID (UUID)"))
+	}
+	if paused, ok := k.Annotations[v1alpha1.PausedAnnotation]; ok && paused != "true" && paused != "false" {
+		errs = append(errs, field.NotSupported(field.NewPath("metadata", "annotations").Key(v1alpha1.PausedAnnotation), paused, []string{"true", "false"}))
+	}
+	return errs
+}
+
+// ValidateKlusterUpdate: 생성 시 검증에 더해, DigitalOcean에서 변경할 수 없는 필드가 바뀌었는지 확인한다.
+func ValidateKlusterUpdate(k, old *v1alpha1.Kluster) field.ErrorList {
+	errs := ValidateKluster(k)
+	return append(errs, validateSpecUpdate(&k.Spec, &old.Spec, field.NewPath("spec"))...)
+}
+
+// ValidateResolvedKlusterUpdate: ValidateKlusterUpdate와 같지만, 변경할 수 없는 필드를 template과 합친 spec끼리 비교한다.
+// template이 채운 region / vpcUUID / node pool size도 spec.template을 다른 template으로 바꾸면 달라지기 때문.
+func ValidateResolvedKlusterUpdate(k *v1alpha1.Kluster, spec, old *v1alpha1.KlusterSpec) field.ErrorList {
+	errs := ValidateKluster(k)
+	return append(errs, validateSpecUpdate(spec, old, field.NewPath("spec"))...)
+}
+
+// ValidateKlusterTemplateUpdate: template의 값은 참조하는 모든 Kluster에 반영되므로, Kluster와 같이 변경할 수 없는 필드를 확인한다.
+// 바꿀 수 없는 값을 바꾸면 Kluster들이 계속 drift 상태가 되거나 update에 실패하므로, 새 template을 만들어야 한다.
+func ValidateKlusterTemplateUpdate(t, old *v1alpha1.KlusterTemplate) field.ErrorList {
+	errs := ValidateKlusterTemplate(t)
+	spec := v1alpha1.MergeTemplate(v1alpha1.KlusterSpec{}, t.Spec)
+	oldSpec := v1alpha1.MergeTemplate(v1alpha1.KlusterSpec{}, old.Spec)
+	return append(errs, validateSpecUpdate(&spec, &oldSpec, field.NewPath("spec"))...)
+}
+
+func validateSpecUpdate(spec, old *v1alpha1.KlusterSpec, specPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, apivalidation.ValidateImmutableField(spec.Name, old.Name, specPath.Child("name"))...)
+	errs = append(errs, apivalidation.ValidateImmutableField(spec.Region, old.Region, specPath.Child("region"))...)
+	errs = append(errs, apivalidation.ValidateImmutableField(spec.VPCUUID, old.VPCUUID, specPath.Child("vpcUUID"))...)
+	// DigitalOcean은 node pool의 droplet size를 바꿀 수 없다. 다른 size가 필요하면 새 pool을 추가해야 함.
+	oldSizes := map[string]string{}
+	for _, pool := range old.NodePools {
+		oldSizes[pool.Name] = pool.Size
+	}
+	for i, pool := range spec.NodePools {
+		if size, ok := oldSizes[pool.Name]; ok {
+			errs = append(errs, apivalidation.ValidateImmutableField(pool.Size, size, specPath.Child("nodePools").Index(i).Child("size"))...)
+		}
+	}
+	// DigitalOcean은 HA control plane을 켜는 것만 허용하고 끄는 것은 허용하지 않는다.
+	if pointer.BoolDeref(old.HA, false) && !pointer.BoolDeref(spec.HA, false) {
+		errs = append(errs, field.Forbidden(specPath.Child("ha"), "cannot be disabled once enabled"))
+	}
+	// surge upgrade도 DigitalOcean update API로 끌 수 없다. (godo request의 omitempty 때문에 false는 전달되지 않음)
+	if pointer.BoolDeref(old.SurgeUpgrade, false) && !pointer.BoolDeref(spec.SurgeUpgrade, false) {
+		errs = append(errs, field.Forbidden(specPath.Child("surgeUpgrade"), "cannot be disabled once enabled"))
+	}
+	return errs
+}
+
+// template을 참조하는 spec은 비어 있는 필드를 template이 채우므로, 값이 있는 필드만 검증한다.
+// template과 합친 spec은 controller가 다시 ValidateKluster로 검증함.
+func ValidateKlusterSpec(spec *v1alpha1.KlusterSpec, fldPath *field.Path) field.ErrorList {
+	return validateSpec(spec, fldPath, spec.Template != "")
+}
+
+// ValidateKlusterTemplate: template의 값이 있는 필드를 Kluster와 같은 규칙으로 검증한다.
+func ValidateKlusterTemplate(t *v1alpha1.KlusterTemplate) field.ErrorList {
+	spec := v1alpha1.MergeTemplate(v1alpha1.KlusterSpec{}, t.Spec)
+	return validateSpec(&spec, field.NewPath("spec"), true)
+}
+
+// partial이면 비어 있는 필드의 required 검사를 하지 않는다.
+func validateSpec(spec *v1alpha1.KlusterSpec, fldPath *field.Path, partial bool) field.ErrorList {
+	var errs field.ErrorList
+	if !partial || spec.Name != "" {
+		errs = append(errs, validateName(spec.Name, fldPath.Child("name"))...)
+	}
+	if spec.Region == "" {
+		if !partial {
+			errs = append(errs, field.Required(fldPath.Child("region"), ""))
+		}
+	} else if !regionSlugRegexp.MatchString(spec.Region) {
+		errs = append(errs, field.Invalid(fldPath.Child("region"), spec.Region, "must be a DigitalOcean region slug, i.e. nyc1"))
+	}
+	if !partial || spec.Version != "" {
+		errs = append(errs, ValidateVersion(spec.Version, fldPath.Child("version"))...)
+	}
+	if !partial || spec.TokenSecret != "" {
+		errs = append(errs, ValidateSecretReference(spec.TokenSecret, fldPath.Child("tokenSecret"))...)
+	}
+	if !partial || len(spec.NodePools) > 0 {
+		errs = append(errs, ValidateNodePools(spec.NodePools, fldPath.Child("nodePools"))...)
+	}
+	// primary node pool은 template의 pool일 수 있으므로 합친 spec에서만 확인한다.
+	if !partial {
+		errs = append(errs, validatePrimaryNodePool(spec, fldPath)...)
+	}
+	if spec.DeletionPolicy != "" && !deletionPolicies.Has(string(spec.DeletionPolicy)) {
+		errs = append(errs, field.NotSupported(fldPath.Child("deletionPolicy"), spec.DeletionPolicy, deletionPolicies.List()))
+	}
+	if spec.DriftPolicy != "" && !driftPolicies.Has(string(spec.DriftPolicy)) {
+		errs = append(errs, field.NotSupported(fldPath.Child("driftPolicy"), spec.DriftPolicy, driftPolicies.List()))
+	}
+	if spec.TTL != nil && spec.TTL.Duration <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("ttl"), spec.TTL.Duration.String(), "must be greater than 0"))
+	}
+	if spec.VPCUUID != "" && !uuidRegexp.MatchString(spec.VPCUUID) {
+		errs = append(errs, field.Invalid(fldPath.Child("vpcUUID"), spec.VPCUUID, "must be a lowercase UUID"))
+	}
+	errs = append(errs, ValidateTags(spec.Tags, fldPath.Child("tags"))...)
+	if spec.MaintenancePolicy != nil {
+		errs = append(errs, ValidateMaintenancePolicy(spec.MaintenancePolicy, fldPath.Child("maintenancePolicy"))...)
+	}
+	return errs
+}
+
+// primaryNodePool은 spec.nodePools 중 하나를 가리켜야 한다. replicas는 기본값 적용 후 그 pool의 count와 같다.
+func validatePrimaryNodePool(spec *v1alpha1.KlusterSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if spec.Replicas != nil && (*spec.Replicas < 1 || *spec.Replicas > MaxNodesPerPool) {
+		errs = append(errs, field.Invalid(fldPath.Child("replicas"), *spec.Replicas, fmt.Sprintf("must be between 1 and %d", MaxNodesPerPool)))
+	}
+	if spec.PrimaryNodePool == "" {
+		if spec.Replicas != nil {
+			errs = append(errs, field.Required(fldPath.Child("primaryNodePool"), "must be set when replicas is set"))
+		}
+		return errs
+	}
+	for _, pool := range spec.NodePools {
+		if pool.Name == spec.PrimaryNodePool {
+			return errs
+		}
+	}
+	return append(errs, field.NotFound(fldPath.Child("primaryNodePool"), spec.PrimaryNodePool))
+}
+
+func ValidateTags(tags []string, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if len(tags) > MaxTags {
+		errs = append(errs, field.TooMany(fldPath, len(tags), MaxTags))
+	}
+	for i, tag := range tags {
+		if !tagRegexp.MatchString(tag) {
+			errs = append(errs, field.Invalid(fldPath.Index(i), tag, "must consist of letters, numbers, '_', ':' or '-' and be at most 255 characters"))
+		}
+	}
+	return errs
+}
+
+func ValidateMaintenancePolicy(policy *v1alpha1.MaintenancePolicy, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if policy.Day != "" && !maintenanceDays.Has(policy.Day) {
+		errs = append(errs, field.NotSupported(fldPath.Child("day"), policy.Day, maintenanceDays.List()))
+	}
+	if policy.StartTime != "" && !startTimeRegexp.MatchString(policy.StartTime) {
+		errs = append(errs, field.Invalid(fldPath.Child("startTime"), policy.StartTime, "must be a UTC time in the form HH:MM, i.e. 04:00"))
+	}
+	return errs
+}
+
+// DigitalOcean의 cluster / node pool 이름은 소문자, 숫자, '-' 만 허용한다. (DNS-1123 label)
+func validateName(name string, fldPath *field.Path) field.ErrorList {
+	if name == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	var errs field.ErrorList
+	for _, msg := range validation.IsDNS1123Label(name) {
+		errs = append(errs, field.Invalid(fldPath, name, msg))
+	}
+	return errs
+}
+
+// ValidateVersion: latest 또는 DigitalOcean version slug (i.e. 1.25.4-do.0)
+func ValidateVersion(version string, fldPath *field.Path) field.ErrorList {
+	if version == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	if version != LatestVersion && !versionSlugRegexp.MatchString(version) {
+		return field.ErrorList{field.Invalid(fldPath, version, fmt.Sprintf("must be %q or a DigitalOcean version slug, i.e. 1.25.4-do.0", LatestVersion))}
+	}
+	return nil
+}
+
+// ValidateSecretReference: <namespace>/<name> 형태의 secret 참조. i.e. default/dosecret
+func ValidateSecretReference(ref string, fldPath *field.Path) field.ErrorList {
+	if ref == "" {
+		return field.ErrorList{field.Required(fldPath, "must be a secret reference in the form <namespace>/<name>")}
+	}
+	parts := strings.Split(ref, "/")
+	if len(parts) != 2 {
+		return field.ErrorList{field.Invalid(fldPath, ref, "must be a secret reference in the form <namespace>/<name>")}
+	}
+	var errs field.ErrorList
+	for _, msg := range validation.IsDNS1123Label(parts[0]) {
+		errs = append(errs, field.Invalid(fldPath, ref, "namespace: "+msg))
+	}
+	for _, msg := range validation.IsDNS1123Subdomain(parts[1]) {
+		errs = append(errs, field.Invalid(fldPath, ref, "name: "+msg))
+	}
+	return errs
+}
+
+func ValidateNodePools(pools []v1alpha1.NodePool, fldPath *field.Path) field.ErrorList {
+	if len(pools) == 0 {
+		return field.ErrorList{field.Required(fldPath, "at least one node pool is required")}
+	}
+	var errs field.ErrorList
+	names := sets.NewString()
+	for i := range pools {
+		idxPath := fldPath.Index(i)
+		errs = append(errs, ValidateNodePool(&pools[i], idxPath)...)
+		if names.Has(pools[i].Name) {
+			errs = append(errs, field.Duplicate(idxPath.Child("name"), pools[i].Name))
+		}
+		names.Insert(pools[i].Name)
+	}
+	return errs
+}
+
+func ValidateNodePool(pool *v1alpha1.NodePool, fldPath *field.Path) field.ErrorList {
+	errs := validateName(pool.Name, fldPath.Child("name"))
+	if pool.Size == "" {
+		errs = append(errs, field.Required(fldPath.Child("size"), ""))
+	} else if !sizeSlugRegexp.MatchString(pool.Size) {
+		errs = append(errs, field.Invalid(fldPath.Child("size"), pool.Size, "must be a DigitalOcean droplet size slug, i.e. s-2vcpu-2gb"))
+	}
+	if pool.Count < 1 || pool.Count > MaxNodesPerPool {
+		errs = append(errs, field.Invalid(fldPath.Child("count"), pool.Count, fmt.Sprintf("must be between 1 and %d", MaxNodesPerPool)))
+	}
+	errs = append(errs, validateAutoScale(pool, fldPath)...)
+	errs = append(errs, metav1validation.ValidateLabels(pool.Labels, fldPath.Child("labels"))...)
+	errs = append(errs, ValidateTaints(pool.Taints, fldPath.Child("taints"))...)
+	errs = append(errs, ValidateTags(pool.Tags, fldPath.Child("tags"))...)
+	// autoScale pool의 노드 수는 autoscaler가 관리하므로 schedule로 바꿀 수 없다.
+	if pool.AutoScale && len(pool.Schedules) > 0 {
+		errs = append(errs, field.Forbidden(fldPath.Child("schedules"), "cannot be used when autoScale is enabled"))
+	}
+	errs = append(errs, ValidateSchedules(pool.Schedules, fldPath.Child("schedules"))...)
+	return errs
+}
+
+func ValidateSchedules(schedules []v1alpha1.ScalingSchedule, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	names := sets.NewString()
+	for i, schedule := range schedules {
+		idxPath := fldPath.Index(i)
+		errs = append(errs, validateName(schedule.Name, idxPath.Child("name"))...)
+		if names.Has(schedule.Name) {
+			errs = append(errs, field.Duplicate(idxPath.Child("name"), schedule.Name))
+		}
+		names.Insert(schedule.Name)
+		if strings.Contains(schedule.Schedule, "TZ") {
+			errs = append(errs, field.Invalid(idxPath.Child("schedule"), schedule.Schedule, "time zone must be set in timeZone, not with CRON_TZ or TZ"))
+		} else if _, err := cron.ParseStandard(schedule.Schedule); err != nil {
+			errs = append(errs, field.Invalid(idxPath.Child("schedule"), schedule.Schedule, err.Error()))
+		}
+		if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+			errs = append(errs, field.Invalid(idxPath.Child("timeZone"), schedule.TimeZone, "must be an IANA time zone, i.e. Asia/Seoul"))
+		}
+		if schedule.Count < 1 || schedule.Count > MaxNodesPerPool {
+			errs = append(errs, field.Invalid(idxPath.Child("count"), schedule.Count, fmt.Sprintf("must be between 1 and %d", MaxNodesPerPool)))
+		}
+	}
+	return errs
+}
+
+// ParseSchedule: cron 표현식과 time zone을 파싱한다. 다음 실행 시각은 schedule.Next(t.In(location))으로 구함.
+// timeZone이 비어 있으면 UTC.
+func ParseSchedule(schedule v1alpha1.ScalingSchedule) (cron.Schedule, *time.Location, error) {
+	location, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		return nil, nil, err
+	}
+	parsed, err := cron.ParseStandard(schedule.Schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parsed, location, nil
+}
+
+// autoScale이 켜져 있으면 count는 초기 노드 수이므로 minNodes ~ maxNodes 범위 안에 있어야 한다.
+func validateAutoScale(pool *v1alpha1.NodePool, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if !pool.AutoScale {
+		if pool.MinNodes != 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("minNodes"), "may only be set when autoScale is enabled"))
+		}
+		if pool.MaxNodes != 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("maxNodes"), "may only be set when autoScale is enabled"))
+		}
+		return errs
+	}
+	if pool.MinNodes < 0 || pool.MinNodes > MaxNodesPerPool {
+		errs = append(errs, field.Invalid(fldPath.Child("minNodes"), pool.MinNodes, fmt.Sprintf("must be between 0 and %d", MaxNodesPerPool)))
+	}
+	if pool.MaxNodes < 1 || pool.MaxNodes > MaxNodesPerPool {
+		errs = append(errs, field.Invalid(fldPath.Child("maxNodes"), pool.MaxNodes, fmt.Sprintf("must be between 1 and %d", MaxNodesPerPool)))
+	} else if pool.MaxNodes < pool.MinNodes {
+		errs = append(errs, field.Invalid(fldPath.Child("maxNodes"), pool.MaxNodes, "must not be less than minNodes"))
+	}
+	if len(errs) == 0 && (pool.Count < pool.MinNodes || pool.Count > pool.MaxNodes) {
+		errs = append(errs, field.Invalid(fldPath.Child("count"), pool.Count, "must be between minNodes and maxNodes when autoScale is enabled"))
+	}
+	return errs
+}
+
+func ValidateTaints(taints []v1alpha1.Taint, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	seen := sets.NewString()
+	for i, taint := range taints {
+		idxPath := fldPath.Index(i)
+		for _, msg := range validation.IsQualifiedName(taint.Key) {
+			errs = append(errs, field.Invalid(idxPath.Child("key"), taint.Key, msg))
+		}
+		if taint.Value != "" {
+			for _, msg := range validation.IsValidLabelValue(taint.Value) {
+				errs = append(errs, field.Invalid(idxPath.Child("value"), taint.Value, msg))
+			}
+		}
+		if !taintEffects.Has(taint.Effect) {
+			errs = append(errs, field.NotSupported(idxPath.Child("effect"), taint.Effect, taintEffects.List()))
+		}
+		// kubernetes와 같이 key + effect 조합은 중복될 수 없다.
+		if id := taint.Key + ":" + taint.Effect; seen.Has(id) {
+			errs = append(errs, field.Duplicate(idxPath, id))
+		} else {
+			seen.Insert(id)
+		}
+	}
+	return errs
+}
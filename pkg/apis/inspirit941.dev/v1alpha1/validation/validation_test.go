@@ -0,0 +1,370 @@
+package validation
+
+import (
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+	"testing"
+	"time"
+)
+
+func validKluster() *v1alpha1.Kluster {
+	return &v1alpha1.Kluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kluster-0", Namespace: "default"},
+		Spec: v1alpha1.KlusterSpec{
+			Name:        "kluster-0",
+			Region:      "nyc1",
+			Version:     "1.25.4-do.0",
+			TokenSecret: "default/dosecret",
+			NodePools: []v1alpha1.NodePool{
+				{Name: "pool-0", Size: "s-2vcpu-2gb", Count: 3},
+			},
+		},
+	}
+}
+
+// 에러가 난 field path 목록. 메시지까지 비교하면 테스트가 k8s 버전에 따라 깨지므로 path와 type만 비교한다.
+func errorFields(errs field.ErrorList) []string {
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, string(err.Type)+" "+err.Field)
+	}
+	return fields
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestValidateKluster(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(k *v1alpha1.Kluster)
+		want   []string
+	}{
+		{
+			name:   "valid",
+			mutate: func(k *v1alpha1.Kluster) {},
+		},
+		{
+			name:   "latest version",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.Version = LatestVersion },
+		},
+		{
+			name:   "empty name",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.Name = "" },
+			want:   []string{"FieldValueRequired spec.name"},
+		},
+		{
+			name:   "uppercase name",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.Name = "Kluster" },
+			want:   []string{"FieldValueInvalid spec.name"},
+		},
+		{
+			name:   "empty region",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.Region = "" },
+			want:   []string{"FieldValueRequired spec.region"},
+		},
+		{
+			name:   "invalid region",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.Region = "new-york" },
+			want:   []string{"FieldValueInvalid spec.region"},
+		},
+		{
+			name:   "invalid version",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.Version = "1.25" },
+			want:   []string{"FieldValueInvalid spec.version"},
+		},
+		{
+			name:   "token secret without namespace",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.TokenSecret = "dosecret" },
+			want:   []string{"FieldValueInvalid spec.tokenSecret"},
+		},
+		{
+			name:   "no node pools",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.NodePools = nil },
+			want:   []string{"FieldValueRequired spec.nodePools"},
+		},
+		{
+			name: "duplicate node pool",
+			mutate: func(k *v1alpha1.Kluster) {
+				k.Spec.NodePools = append(k.Spec.NodePools, k.Spec.NodePools[0])
+			},
+			want: []string{"FieldValueDuplicate spec.nodePools[1].name"},
+		},
+		{
+			name:   "invalid size and count",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.NodePools[0].Size = "big"; k.Spec.NodePools[0].Count = 0 },
+			want:   []string{"FieldValueInvalid spec.nodePools[0].size", "FieldValueInvalid spec.nodePools[0].count"},
+		},
+		{
+			name:   "too many nodes",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.NodePools[0].Count = MaxNodesPerPool + 1 },
+			want:   []string{"FieldValueInvalid spec.nodePools[0].count"},
+		},
+		{
+			name:   "minNodes without autoScale",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.NodePools[0].MinNodes = 1 },
+			want:   []string{"FieldValueForbidden spec.nodePools[0].minNodes"},
+		},
+		{
+			name: "autoScale count outside of min and max",
+			mutate: func(k *v1alpha1.Kluster) {
+				pool := &k.Spec.NodePools[0]
+				pool.AutoScale, pool.MinNodes, pool.MaxNodes = true, 1, 2
+			},
+			want: []string{"FieldValueInvalid spec.nodePools[0].count"},
+		},
+		{
+			name: "autoScale maxNodes less than minNodes",
+			mutate: func(k *v1alpha1.Kluster) {
+				pool := &k.Spec.NodePools[0]
+				pool.AutoScale, pool.MinNodes, pool.MaxNodes = true, 3, 2
+			},
+			want: []string{"FieldValueInvalid spec.nodePools[0].maxNodes"},
+		},
+		{
+			name: "duplicate taint",
+			mutate: func(k *v1alpha1.Kluster) {
+				taint := v1alpha1.Taint{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}
+				k.Spec.NodePools[0].Taints = []v1alpha1.Taint{taint, taint}
+			},
+			want: []string{"FieldValueDuplicate spec.nodePools[0].taints[1]"},
+		},
+		{
+			name: "unsupported taint effect",
+			mutate: func(k *v1alpha1.Kluster) {
+				k.Spec.NodePools[0].Taints = []v1alpha1.Taint{{Key: "dedicated", Effect: "Never"}}
+			},
+			want: []string{"FieldValueNotSupported spec.nodePools[0].taints[0].effect"},
+		},
+		{
+			name:   "invalid tag",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.Tags = []string{"team a"} },
+			want:   []string{"FieldValueInvalid spec.tags[0]"},
+		},
+		{
+			name:   "invalid vpcUUID",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.VPCUUID = "vpc" },
+			want:   []string{"FieldValueInvalid spec.vpcUUID"},
+		},
+		{
+			name:   "non-positive ttl",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.TTL = &metav1.Duration{Duration: -time.Hour} },
+			want:   []string{"FieldValueInvalid spec.ttl"},
+		},
+		{
+			name:   "unsupported deletion policy",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.DeletionPolicy = "Orphan" },
+			want:   []string{"FieldValueNotSupported spec.deletionPolicy"},
+		},
+		{
+			name: "invalid maintenance policy",
+			mutate: func(k *v1alpha1.Kluster) {
+				k.Spec.MaintenancePolicy = &v1alpha1.MaintenancePolicy{Day: "someday", StartTime: "25:00"}
+			},
+			want: []string{"FieldValueNotSupported spec.maintenancePolicy.day", "FieldValueInvalid spec.maintenancePolicy.startTime"},
+		},
+		{
+			name:   "primary node pool not found",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.PrimaryNodePool = "pool-1" },
+			want:   []string{"FieldValueNotFound spec.primaryNodePool"},
+		},
+		{
+			name:   "replicas without primary node pool",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.Replicas = pointer.Int32(3) },
+			want:   []string{"FieldValueRequired spec.primaryNodePool"},
+		},
+		{
+			name: "schedule with autoScale",
+			mutate: func(k *v1alpha1.Kluster) {
+				pool := &k.Spec.NodePools[0]
+				pool.AutoScale, pool.MinNodes, pool.MaxNodes = true, 1, 3
+				pool.Schedules = []v1alpha1.ScalingSchedule{{Name: "night", Schedule: "0 20 * * *", Count: 1}}
+			},
+			want: []string{"FieldValueForbidden spec.nodePools[0].schedules"},
+		},
+		{
+			name: "schedule with CRON_TZ",
+			mutate: func(k *v1alpha1.Kluster) {
+				k.Spec.NodePools[0].Schedules = []v1alpha1.ScalingSchedule{{Name: "night", Schedule: "CRON_TZ=Asia/Seoul 0 20 * * *", Count: 1}}
+			},
+			want: []string{"FieldValueInvalid spec.nodePools[0].schedules[0].schedule"},
+		},
+		{
+			name: "invalid adopt annotation",
+			mutate: func(k *v1alpha1.Kluster) {
+				k.Annotations = map[string]string{v1alpha1.AdoptClusterIDAnnotation: "cluster"}
+			},
+			want: []string{"FieldValueInvalid metadata.annotations[" + v1alpha1.AdoptClusterIDAnnotation + "]"},
+		},
+		{
+			name:   "invalid paused annotation",
+			mutate: func(k *v1alpha1.Kluster) { k.Annotations = map[string]string{v1alpha1.PausedAnnotation: "yes"} },
+			want:   []string{"FieldValueNotSupported metadata.annotations[" + v1alpha1.PausedAnnotation + "]"},
+		},
+		{
+			name: "template reference skips required fields",
+			mutate: func(k *v1alpha1.Kluster) {
+				k.Spec = v1alpha1.KlusterSpec{Name: "kluster-0", Template: "small"}
+			},
+		},
+		{
+			name: "template reference still validates set fields",
+			mutate: func(k *v1alpha1.Kluster) {
+				k.Spec = v1alpha1.KlusterSpec{Name: "kluster-0", Template: "small", Region: "seoul"}
+			},
+			want: []string{"FieldValueInvalid spec.region"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := validKluster()
+			tt.mutate(k)
+			if got := errorFields(ValidateKluster(k)); !equalStrings(got, tt.want) {
+				t.Errorf("ValidateKluster() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateKlusterUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		old    func(k *v1alpha1.Kluster)
+		mutate func(k *v1alpha1.Kluster)
+		want   []string
+	}{
+		{
+			name:   "node count change",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.NodePools[0].Count = 5 },
+		},
+		{
+			name: "new node pool with another size",
+			mutate: func(k *v1alpha1.Kluster) {
+				k.Spec.NodePools = append(k.Spec.NodePools, v1alpha1.NodePool{Name: "pool-1", Size: "s-4vcpu-8gb", Count: 1})
+			},
+		},
+		{
+			name:   "name change",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.Name = "kluster-1" },
+			want:   []string{"FieldValueInvalid spec.name"},
+		},
+		{
+			name:   "region change",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.Region = "sfo3" },
+			want:   []string{"FieldValueInvalid spec.region"},
+		},
+		{
+			name:   "vpcUUID change",
+			old:    func(k *v1alpha1.Kluster) { k.Spec.VPCUUID = "c33931f2-a26a-4e61-b85c-4e95a2ec431b" },
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.VPCUUID = "" },
+			want:   []string{"FieldValueInvalid spec.vpcUUID"},
+		},
+		{
+			name:   "node pool size change",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.NodePools[0].Size = "s-4vcpu-8gb" },
+			want:   []string{"FieldValueInvalid spec.nodePools[0].size"},
+		},
+		{
+			name:   "enable ha",
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.HA = pointer.Bool(true) },
+		},
+		{
+			name:   "disable ha",
+			old:    func(k *v1alpha1.Kluster) { k.Spec.HA = pointer.Bool(true) },
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.HA = pointer.Bool(false) },
+			want:   []string{"FieldValueForbidden spec.ha"},
+		},
+		{
+			name:   "unset ha",
+			old:    func(k *v1alpha1.Kluster) { k.Spec.HA = pointer.Bool(true) },
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.HA = nil },
+			want:   []string{"FieldValueForbidden spec.ha"},
+		},
+		{
+			name:   "disable surgeUpgrade",
+			old:    func(k *v1alpha1.Kluster) { k.Spec.SurgeUpgrade = pointer.Bool(true) },
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.SurgeUpgrade = pointer.Bool(false) },
+			want:   []string{"FieldValueForbidden spec.surgeUpgrade"},
+		},
+		{
+			name:   "disable autoUpgrade",
+			old:    func(k *v1alpha1.Kluster) { k.Spec.AutoUpgrade = pointer.Bool(true) },
+			mutate: func(k *v1alpha1.Kluster) { k.Spec.AutoUpgrade = pointer.Bool(false) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := validKluster()
+			if tt.old != nil {
+				tt.old(old)
+			}
+			k := old.DeepCopy()
+			tt.mutate(k)
+			if got := errorFields(ValidateKlusterUpdate(k, old)); !equalStrings(got, tt.want) {
+				t.Errorf("ValidateKlusterUpdate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateKlusterTemplateUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(tmpl *v1alpha1.KlusterTemplate)
+		want   []string
+	}{
+		{
+			name:   "version change",
+			mutate: func(tmpl *v1alpha1.KlusterTemplate) { tmpl.Spec.Version = "1.26.3-do.0" },
+		},
+		{
+			name:   "region change",
+			mutate: func(tmpl *v1alpha1.KlusterTemplate) { tmpl.Spec.Region = "sfo3" },
+			want:   []string{"FieldValueInvalid spec.region"},
+		},
+		{
+			name:   "node pool size change",
+			mutate: func(tmpl *v1alpha1.KlusterTemplate) { tmpl.Spec.NodePools[0].Size = "s-4vcpu-8gb" },
+			want:   []string{"FieldValueInvalid spec.nodePools[0].size"},
+		},
+		{
+			name:   "disable ha",
+			mutate: func(tmpl *v1alpha1.KlusterTemplate) { tmpl.Spec.HA = pointer.Bool(false) },
+			want:   []string{"FieldValueForbidden spec.ha"},
+		},
+		{
+			name:   "invalid version",
+			mutate: func(tmpl *v1alpha1.KlusterTemplate) { tmpl.Spec.Version = "1.26" },
+			want:   []string{"FieldValueInvalid spec.version"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := &v1alpha1.KlusterTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "small", Namespace: "default"},
+				Spec: v1alpha1.KlusterTemplateSpec{
+					Region:    "nyc1",
+					Version:   "1.25.4-do.0",
+					NodePools: []v1alpha1.NodePool{{Name: "pool-0", Size: "s-2vcpu-2gb", Count: 3}},
+					HA:        pointer.Bool(true),
+				},
+			}
+			template := old.DeepCopy()
+			tt.mutate(template)
+			if got := errorFields(ValidateKlusterTemplateUpdate(template, old)); !equalStrings(got, tt.want) {
+				t.Errorf("ValidateKlusterTemplateUpdate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
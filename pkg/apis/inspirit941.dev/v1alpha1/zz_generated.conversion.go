@@ -0,0 +1,524 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by conversion-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	unsafe "unsafe"
+
+	v1beta1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+func init() {
+	localSchemeBuilder.Register(RegisterConversions)
+}
+
+// RegisterConversions adds conversion functions to the given scheme.
+// Public to allow building arbitrary schemes.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*Kluster)(nil), (*v1beta1.Kluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Kluster_To_v1beta1_Kluster(a.(*Kluster), b.(*v1beta1.Kluster), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.Kluster)(nil), (*Kluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Kluster_To_v1alpha1_Kluster(a.(*v1beta1.Kluster), b.(*Kluster), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KlusterList)(nil), (*v1beta1.KlusterList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_KlusterList_To_v1beta1_KlusterList(a.(*KlusterList), b.(*v1beta1.KlusterList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.KlusterList)(nil), (*KlusterList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_KlusterList_To_v1alpha1_KlusterList(a.(*v1beta1.KlusterList), b.(*KlusterList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MaintenancePolicy)(nil), (*v1beta1.MaintenancePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_MaintenancePolicy_To_v1beta1_MaintenancePolicy(a.(*MaintenancePolicy), b.(*v1beta1.MaintenancePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.MaintenancePolicy)(nil), (*MaintenancePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_MaintenancePolicy_To_v1alpha1_MaintenancePolicy(a.(*v1beta1.MaintenancePolicy), b.(*MaintenancePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodePoolStatus)(nil), (*v1beta1.NodePoolStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NodePoolStatus_To_v1beta1_NodePoolStatus(a.(*NodePoolStatus), b.(*v1beta1.NodePoolStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NodePoolStatus)(nil), (*NodePoolStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NodePoolStatus_To_v1alpha1_NodePoolStatus(a.(*v1beta1.NodePoolStatus), b.(*NodePoolStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeStatus)(nil), (*v1beta1.NodeStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NodeStatus_To_v1beta1_NodeStatus(a.(*NodeStatus), b.(*v1beta1.NodeStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NodeStatus)(nil), (*NodeStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NodeStatus_To_v1alpha1_NodeStatus(a.(*v1beta1.NodeStatus), b.(*NodeStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ScalingSchedule)(nil), (*v1beta1.ScalingSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ScalingSchedule_To_v1beta1_ScalingSchedule(a.(*ScalingSchedule), b.(*v1beta1.ScalingSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ScalingSchedule)(nil), (*ScalingSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ScalingSchedule_To_v1alpha1_ScalingSchedule(a.(*v1beta1.ScalingSchedule), b.(*ScalingSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ScheduledScalingStatus)(nil), (*v1beta1.ScheduledScalingStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ScheduledScalingStatus_To_v1beta1_ScheduledScalingStatus(a.(*ScheduledScalingStatus), b.(*v1beta1.ScheduledScalingStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ScheduledScalingStatus)(nil), (*ScheduledScalingStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ScheduledScalingStatus_To_v1alpha1_ScheduledScalingStatus(a.(*v1beta1.ScheduledScalingStatus), b.(*ScheduledScalingStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*Taint)(nil), (*v1beta1.Taint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Taint_To_v1beta1_Taint(a.(*Taint), b.(*v1beta1.Taint), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.Taint)(nil), (*Taint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Taint_To_v1alpha1_Taint(a.(*v1beta1.Taint), b.(*Taint), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*KlusterSpec)(nil), (*v1beta1.KlusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_KlusterSpec_To_v1beta1_KlusterSpec(a.(*KlusterSpec), b.(*v1beta1.KlusterSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*KlusterStatus)(nil), (*v1beta1.KlusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_KlusterStatus_To_v1beta1_KlusterStatus(a.(*KlusterStatus), b.(*v1beta1.KlusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*NodePool)(nil), (*v1beta1.NodePool)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NodePool_To_v1beta1_NodePool(a.(*NodePool), b.(*v1beta1.NodePool), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.KlusterSpec)(nil), (*KlusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_KlusterSpec_To_v1alpha1_KlusterSpec(a.(*v1beta1.KlusterSpec), b.(*KlusterSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.KlusterStatus)(nil), (*KlusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_KlusterStatus_To_v1alpha1_KlusterStatus(a.(*v1beta1.KlusterStatus), b.(*KlusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.NodePool)(nil), (*NodePool)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NodePool_To_v1alpha1_NodePool(a.(*v1beta1.NodePool), b.(*NodePool), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha1_Kluster_To_v1beta1_Kluster(in *Kluster, out *v1beta1.Kluster, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha1_KlusterSpec_To_v1beta1_KlusterSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_KlusterStatus_To_v1beta1_KlusterStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_Kluster_To_v1beta1_Kluster is an autogenerated conversion function.
+func Convert_v1alpha1_Kluster_To_v1beta1_Kluster(in *Kluster, out *v1beta1.Kluster, s conversion.Scope) error {
+	return autoConvert_v1alpha1_Kluster_To_v1beta1_Kluster(in, out, s)
+}
+
+func autoConvert_v1beta1_Kluster_To_v1alpha1_Kluster(in *v1beta1.Kluster, out *Kluster, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_KlusterSpec_To_v1alpha1_KlusterSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_KlusterStatus_To_v1alpha1_KlusterStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_Kluster_To_v1alpha1_Kluster is an autogenerated conversion function.
+func Convert_v1beta1_Kluster_To_v1alpha1_Kluster(in *v1beta1.Kluster, out *Kluster, s conversion.Scope) error {
+	return autoConvert_v1beta1_Kluster_To_v1alpha1_Kluster(in, out, s)
+}
+
+func autoConvert_v1alpha1_KlusterList_To_v1beta1_KlusterList(in *KlusterList, out *v1beta1.KlusterList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1beta1.Kluster, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_Kluster_To_v1beta1_Kluster(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha1_KlusterList_To_v1beta1_KlusterList is an autogenerated conversion function.
+func Convert_v1alpha1_KlusterList_To_v1beta1_KlusterList(in *KlusterList, out *v1beta1.KlusterList, s conversion.Scope) error {
+	return autoConvert_v1alpha1_KlusterList_To_v1beta1_KlusterList(in, out, s)
+}
+
+func autoConvert_v1beta1_KlusterList_To_v1alpha1_KlusterList(in *v1beta1.KlusterList, out *KlusterList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Kluster, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_Kluster_To_v1alpha1_Kluster(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1beta1_KlusterList_To_v1alpha1_KlusterList is an autogenerated conversion function.
+func Convert_v1beta1_KlusterList_To_v1alpha1_KlusterList(in *v1beta1.KlusterList, out *KlusterList, s conversion.Scope) error {
+	return autoConvert_v1beta1_KlusterList_To_v1alpha1_KlusterList(in, out, s)
+}
+
+func autoConvert_v1alpha1_KlusterSpec_To_v1beta1_KlusterSpec(in *KlusterSpec, out *v1beta1.KlusterSpec, s conversion.Scope) error {
+	out.Template = in.Template
+	out.Name = in.Name
+	out.Region = in.Region
+	out.Version = in.Version
+	// WARNING: in.TokenSecret requires manual conversion: does not exist in peer-type
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]v1beta1.NodePool, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_NodePool_To_v1beta1_NodePool(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.NodePools = nil
+	}
+	out.PrimaryNodePool = in.PrimaryNodePool
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.VPCUUID = in.VPCUUID
+	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
+	out.HA = (*bool)(unsafe.Pointer(in.HA))
+	out.AutoUpgrade = (*bool)(unsafe.Pointer(in.AutoUpgrade))
+	out.SurgeUpgrade = (*bool)(unsafe.Pointer(in.SurgeUpgrade))
+	out.MaintenancePolicy = (*v1beta1.MaintenancePolicy)(unsafe.Pointer(in.MaintenancePolicy))
+	out.RegistryEnabled = (*bool)(unsafe.Pointer(in.RegistryEnabled))
+	out.DeletionPolicy = v1beta1.DeletionPolicy(in.DeletionPolicy)
+	out.DriftPolicy = v1beta1.DriftPolicy(in.DriftPolicy)
+	out.TTL = (*v1.Duration)(unsafe.Pointer(in.TTL))
+	return nil
+}
+
+func autoConvert_v1beta1_KlusterSpec_To_v1alpha1_KlusterSpec(in *v1beta1.KlusterSpec, out *KlusterSpec, s conversion.Scope) error {
+	out.Template = in.Template
+	// WARNING: in.Provider requires manual conversion: does not exist in peer-type
+	out.Name = in.Name
+	out.Region = in.Region
+	out.Version = in.Version
+	// WARNING: in.TokenSecretRef requires manual conversion: does not exist in peer-type
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePool, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_NodePool_To_v1alpha1_NodePool(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.NodePools = nil
+	}
+	out.PrimaryNodePool = in.PrimaryNodePool
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.VPCUUID = in.VPCUUID
+	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
+	out.HA = (*bool)(unsafe.Pointer(in.HA))
+	out.AutoUpgrade = (*bool)(unsafe.Pointer(in.AutoUpgrade))
+	out.SurgeUpgrade = (*bool)(unsafe.Pointer(in.SurgeUpgrade))
+	out.MaintenancePolicy = (*MaintenancePolicy)(unsafe.Pointer(in.MaintenancePolicy))
+	out.RegistryEnabled = (*bool)(unsafe.Pointer(in.RegistryEnabled))
+	out.DeletionPolicy = DeletionPolicy(in.DeletionPolicy)
+	out.DriftPolicy = DriftPolicy(in.DriftPolicy)
+	out.TTL = (*v1.Duration)(unsafe.Pointer(in.TTL))
+	return nil
+}
+
+func autoConvert_v1alpha1_KlusterStatus_To_v1beta1_KlusterStatus(in *KlusterStatus, out *v1beta1.KlusterStatus, s conversion.Scope) error {
+	// WARNING: in.KlusterID requires manual conversion: does not exist in peer-type
+	out.Progress = in.Progress
+	out.KubeConfig = in.KubeConfig
+	out.Version = in.Version
+	out.Endpoint = in.Endpoint
+	out.IPv4 = in.IPv4
+	out.VPCUUID = in.VPCUUID
+	out.ClusterSubnet = in.ClusterSubnet
+	out.ServiceSubnet = in.ServiceSubnet
+	out.Message = in.Message
+	out.CreatedAt = (*v1.Time)(unsafe.Pointer(in.CreatedAt))
+	out.UpdatedAt = (*v1.Time)(unsafe.Pointer(in.UpdatedAt))
+	out.Nodes = in.Nodes
+	out.ExpiresAt = (*v1.Time)(unsafe.Pointer(in.ExpiresAt))
+	out.EstimatedMonthlyCost = in.EstimatedMonthlyCost
+	out.AppliedNodes = in.AppliedNodes
+	out.ObservedGeneration = in.ObservedGeneration
+	out.ObservedTemplateGeneration = in.ObservedTemplateGeneration
+	out.Replicas = in.Replicas
+	out.Selector = in.Selector
+	out.NodePools = *(*[]v1beta1.NodePoolStatus)(unsafe.Pointer(&in.NodePools))
+	out.ScheduledScaling = *(*[]v1beta1.ScheduledScalingStatus)(unsafe.Pointer(&in.ScheduledScaling))
+	out.Conditions = *(*[]v1.Condition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+func autoConvert_v1beta1_KlusterStatus_To_v1alpha1_KlusterStatus(in *v1beta1.KlusterStatus, out *KlusterStatus, s conversion.Scope) error {
+	// WARNING: in.ClusterID requires manual conversion: does not exist in peer-type
+	out.Progress = in.Progress
+	out.KubeConfig = in.KubeConfig
+	out.Version = in.Version
+	out.Endpoint = in.Endpoint
+	out.IPv4 = in.IPv4
+	out.VPCUUID = in.VPCUUID
+	out.ClusterSubnet = in.ClusterSubnet
+	out.ServiceSubnet = in.ServiceSubnet
+	out.Message = in.Message
+	out.CreatedAt = (*v1.Time)(unsafe.Pointer(in.CreatedAt))
+	out.UpdatedAt = (*v1.Time)(unsafe.Pointer(in.UpdatedAt))
+	out.Nodes = in.Nodes
+	out.ExpiresAt = (*v1.Time)(unsafe.Pointer(in.ExpiresAt))
+	out.EstimatedMonthlyCost = in.EstimatedMonthlyCost
+	out.AppliedNodes = in.AppliedNodes
+	out.ObservedGeneration = in.ObservedGeneration
+	out.ObservedTemplateGeneration = in.ObservedTemplateGeneration
+	out.Replicas = in.Replicas
+	out.Selector = in.Selector
+	out.NodePools = *(*[]NodePoolStatus)(unsafe.Pointer(&in.NodePools))
+	out.ScheduledScaling = *(*[]ScheduledScalingStatus)(unsafe.Pointer(&in.ScheduledScaling))
+	out.Conditions = *(*[]v1.Condition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+func autoConvert_v1alpha1_MaintenancePolicy_To_v1beta1_MaintenancePolicy(in *MaintenancePolicy, out *v1beta1.MaintenancePolicy, s conversion.Scope) error {
+	out.Day = in.Day
+	out.StartTime = in.StartTime
+	return nil
+}
+
+// Convert_v1alpha1_MaintenancePolicy_To_v1beta1_MaintenancePolicy is an autogenerated conversion function.
+func Convert_v1alpha1_MaintenancePolicy_To_v1beta1_MaintenancePolicy(in *MaintenancePolicy, out *v1beta1.MaintenancePolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_MaintenancePolicy_To_v1beta1_MaintenancePolicy(in, out, s)
+}
+
+func autoConvert_v1beta1_MaintenancePolicy_To_v1alpha1_MaintenancePolicy(in *v1beta1.MaintenancePolicy, out *MaintenancePolicy, s conversion.Scope) error {
+	out.Day = in.Day
+	out.StartTime = in.StartTime
+	return nil
+}
+
+// Convert_v1beta1_MaintenancePolicy_To_v1alpha1_MaintenancePolicy is an autogenerated conversion function.
+func Convert_v1beta1_MaintenancePolicy_To_v1alpha1_MaintenancePolicy(in *v1beta1.MaintenancePolicy, out *MaintenancePolicy, s conversion.Scope) error {
+	return autoConvert_v1beta1_MaintenancePolicy_To_v1alpha1_MaintenancePolicy(in, out, s)
+}
+
+func autoConvert_v1alpha1_NodePool_To_v1beta1_NodePool(in *NodePool, out *v1beta1.NodePool, s conversion.Scope) error {
+	out.Size = in.Size
+	out.Name = in.Name
+	out.Count = in.Count
+	// WARNING: in.AutoScale requires manual conversion: does not exist in peer-type
+	// WARNING: in.MinNodes requires manual conversion: does not exist in peer-type
+	// WARNING: in.MaxNodes requires manual conversion: does not exist in peer-type
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.Taints = *(*[]v1beta1.Taint)(unsafe.Pointer(&in.Taints))
+	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
+	out.Schedules = *(*[]v1beta1.ScalingSchedule)(unsafe.Pointer(&in.Schedules))
+	return nil
+}
+
+func autoConvert_v1beta1_NodePool_To_v1alpha1_NodePool(in *v1beta1.NodePool, out *NodePool, s conversion.Scope) error {
+	out.Size = in.Size
+	out.Name = in.Name
+	out.Count = in.Count
+	// WARNING: in.AutoScaling requires manual conversion: does not exist in peer-type
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.Taints = *(*[]Taint)(unsafe.Pointer(&in.Taints))
+	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
+	out.Schedules = *(*[]ScalingSchedule)(unsafe.Pointer(&in.Schedules))
+	return nil
+}
+
+func autoConvert_v1alpha1_NodePoolStatus_To_v1beta1_NodePoolStatus(in *NodePoolStatus, out *v1beta1.NodePoolStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.ID = in.ID
+	out.Count = in.Count
+	out.Nodes = *(*[]v1beta1.NodeStatus)(unsafe.Pointer(&in.Nodes))
+	return nil
+}
+
+// Convert_v1alpha1_NodePoolStatus_To_v1beta1_NodePoolStatus is an autogenerated conversion function.
+func Convert_v1alpha1_NodePoolStatus_To_v1beta1_NodePoolStatus(in *NodePoolStatus, out *v1beta1.NodePoolStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NodePoolStatus_To_v1beta1_NodePoolStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_NodePoolStatus_To_v1alpha1_NodePoolStatus(in *v1beta1.NodePoolStatus, out *NodePoolStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.ID = in.ID
+	out.Count = in.Count
+	out.Nodes = *(*[]NodeStatus)(unsafe.Pointer(&in.Nodes))
+	return nil
+}
+
+// Convert_v1beta1_NodePoolStatus_To_v1alpha1_NodePoolStatus is an autogenerated conversion function.
+func Convert_v1beta1_NodePoolStatus_To_v1alpha1_NodePoolStatus(in *v1beta1.NodePoolStatus, out *NodePoolStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_NodePoolStatus_To_v1alpha1_NodePoolStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_NodeStatus_To_v1beta1_NodeStatus(in *NodeStatus, out *v1beta1.NodeStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.State = in.State
+	return nil
+}
+
+// Convert_v1alpha1_NodeStatus_To_v1beta1_NodeStatus is an autogenerated conversion function.
+func Convert_v1alpha1_NodeStatus_To_v1beta1_NodeStatus(in *NodeStatus, out *v1beta1.NodeStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NodeStatus_To_v1beta1_NodeStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_NodeStatus_To_v1alpha1_NodeStatus(in *v1beta1.NodeStatus, out *NodeStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.State = in.State
+	return nil
+}
+
+// Convert_v1beta1_NodeStatus_To_v1alpha1_NodeStatus is an autogenerated conversion function.
+func Convert_v1beta1_NodeStatus_To_v1alpha1_NodeStatus(in *v1beta1.NodeStatus, out *NodeStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_NodeStatus_To_v1alpha1_NodeStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_ScalingSchedule_To_v1beta1_ScalingSchedule(in *ScalingSchedule, out *v1beta1.ScalingSchedule, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Schedule = in.Schedule
+	out.TimeZone = in.TimeZone
+	out.Count = in.Count
+	return nil
+}
+
+// Convert_v1alpha1_ScalingSchedule_To_v1beta1_ScalingSchedule is an autogenerated conversion function.
+func Convert_v1alpha1_ScalingSchedule_To_v1beta1_ScalingSchedule(in *ScalingSchedule, out *v1beta1.ScalingSchedule, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ScalingSchedule_To_v1beta1_ScalingSchedule(in, out, s)
+}
+
+func autoConvert_v1beta1_ScalingSchedule_To_v1alpha1_ScalingSchedule(in *v1beta1.ScalingSchedule, out *ScalingSchedule, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Schedule = in.Schedule
+	out.TimeZone = in.TimeZone
+	out.Count = in.Count
+	return nil
+}
+
+// Convert_v1beta1_ScalingSchedule_To_v1alpha1_ScalingSchedule is an autogenerated conversion function.
+func Convert_v1beta1_ScalingSchedule_To_v1alpha1_ScalingSchedule(in *v1beta1.ScalingSchedule, out *ScalingSchedule, s conversion.Scope) error {
+	return autoConvert_v1beta1_ScalingSchedule_To_v1alpha1_ScalingSchedule(in, out, s)
+}
+
+func autoConvert_v1alpha1_ScheduledScalingStatus_To_v1beta1_ScheduledScalingStatus(in *ScheduledScalingStatus, out *v1beta1.ScheduledScalingStatus, s conversion.Scope) error {
+	out.NodePool = in.NodePool
+	out.ActiveSchedule = in.ActiveSchedule
+	out.Count = in.Count
+	out.SpecCount = in.SpecCount
+	out.LastScheduleTime = (*v1.Time)(unsafe.Pointer(in.LastScheduleTime))
+	out.NextSchedule = in.NextSchedule
+	out.NextScheduleTime = (*v1.Time)(unsafe.Pointer(in.NextScheduleTime))
+	out.NextCount = in.NextCount
+	return nil
+}
+
+// Convert_v1alpha1_ScheduledScalingStatus_To_v1beta1_ScheduledScalingStatus is an autogenerated conversion function.
+func Convert_v1alpha1_ScheduledScalingStatus_To_v1beta1_ScheduledScalingStatus(in *ScheduledScalingStatus, out *v1beta1.ScheduledScalingStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ScheduledScalingStatus_To_v1beta1_ScheduledScalingStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_ScheduledScalingStatus_To_v1alpha1_ScheduledScalingStatus(in *v1beta1.ScheduledScalingStatus, out *ScheduledScalingStatus, s conversion.Scope) error {
+	out.NodePool = in.NodePool
+	out.ActiveSchedule = in.ActiveSchedule
+	out.Count = in.Count
+	out.SpecCount = in.SpecCount
+	out.LastScheduleTime = (*v1.Time)(unsafe.Pointer(in.LastScheduleTime))
+	out.NextSchedule = in.NextSchedule
+	out.NextScheduleTime = (*v1.Time)(unsafe.Pointer(in.NextScheduleTime))
+	out.NextCount = in.NextCount
+	return nil
+}
+
+// Convert_v1beta1_ScheduledScalingStatus_To_v1alpha1_ScheduledScalingStatus is an autogenerated conversion function.
+func Convert_v1beta1_ScheduledScalingStatus_To_v1alpha1_ScheduledScalingStatus(in *v1beta1.ScheduledScalingStatus, out *ScheduledScalingStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_ScheduledScalingStatus_To_v1alpha1_ScheduledScalingStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_Taint_To_v1beta1_Taint(in *Taint, out *v1beta1.Taint, s conversion.Scope) error {
+	out.Key = in.Key
+	out.Value = in.Value
+	out.Effect = in.Effect
+	return nil
+}
+
+// Convert_v1alpha1_Taint_To_v1beta1_Taint is an autogenerated conversion function.
+func Convert_v1alpha1_Taint_To_v1beta1_Taint(in *Taint, out *v1beta1.Taint, s conversion.Scope) error {
+	return autoConvert_v1alpha1_Taint_To_v1beta1_Taint(in, out, s)
+}
+
+func autoConvert_v1beta1_Taint_To_v1alpha1_Taint(in *v1beta1.Taint, out *Taint, s conversion.Scope) error {
+	out.Key = in.Key
+	out.Value = in.Value
+	out.Effect = in.Effect
+	return nil
+}
+
+// Convert_v1beta1_Taint_To_v1alpha1_Taint is an autogenerated conversion function.
+func Convert_v1beta1_Taint_To_v1alpha1_Taint(in *v1beta1.Taint, out *Taint, s conversion.Scope) error {
+	return autoConvert_v1beta1_Taint_To_v1alpha1_Taint(in, out, s)
+}
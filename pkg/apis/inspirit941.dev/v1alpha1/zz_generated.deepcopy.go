@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -31,7 +32,7 @@ func (in *Kluster) DeepCopyInto(out *Kluster) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -53,6 +54,107 @@ func (in *Kluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterClaim) DeepCopyInto(out *KlusterClaim) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterClaim.
+func (in *KlusterClaim) DeepCopy() *KlusterClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterClaim) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterClaimList) DeepCopyInto(out *KlusterClaimList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KlusterClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterClaimList.
+func (in *KlusterClaimList) DeepCopy() *KlusterClaimList {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterClaimList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterClaimList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterClaimSpec) DeepCopyInto(out *KlusterClaimSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterClaimSpec.
+func (in *KlusterClaimSpec) DeepCopy() *KlusterClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterClaimStatus) DeepCopyInto(out *KlusterClaimStatus) {
+	*out = *in
+	if in.BoundAt != nil {
+		in, out := &in.BoundAt, &out.BoundAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ReleasedAt != nil {
+		in, out := &in.ReleasedAt, &out.ReleasedAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterClaimStatus.
+func (in *KlusterClaimStatus) DeepCopy() *KlusterClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KlusterList) DeepCopyInto(out *KlusterList) {
 	*out = *in
@@ -87,54 +189,975 @@ func (in *KlusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KlusterSpec) DeepCopyInto(out *KlusterSpec) {
+func (in *KlusterPolicy) DeepCopyInto(out *KlusterPolicy) {
 	*out = *in
-	if in.NodePools != nil {
-		in, out := &in.NodePools, &out.NodePools
-		*out = make([]NodePool, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterPolicy.
+func (in *KlusterPolicy) DeepCopy() *KlusterPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterPolicyList) DeepCopyInto(out *KlusterPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KlusterPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterPolicyList.
+func (in *KlusterPolicyList) DeepCopy() *KlusterPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterPolicySpec) DeepCopyInto(out *KlusterPolicySpec) {
+	*out = *in
+	if in.AllowedRegions != nil {
+		in, out := &in.AllowedRegions, &out.AllowedRegions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedNodeSizes != nil {
+		in, out := &in.AllowedNodeSizes, &out.AllowedNodeSizes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedVersions != nil {
+		in, out := &in.AllowedVersions, &out.AllowedVersions
+		*out = make([]VersionRange, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxNodesPerPool != nil {
+		in, out := &in.MaxNodesPerPool, &out.MaxNodesPerPool
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxNodesPerNamespace != nil {
+		in, out := &in.MaxNodesPerNamespace, &out.MaxNodesPerNamespace
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RequiredTags != nil {
+		in, out := &in.RequiredTags, &out.RequiredTags
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterSpec.
-func (in *KlusterSpec) DeepCopy() *KlusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterPolicySpec.
+func (in *KlusterPolicySpec) DeepCopy() *KlusterPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KlusterSpec)
+	out := new(KlusterPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KlusterStatus) DeepCopyInto(out *KlusterStatus) {
+func (in *KlusterPool) DeepCopyInto(out *KlusterPool) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterStatus.
-func (in *KlusterStatus) DeepCopy() *KlusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterPool.
+func (in *KlusterPool) DeepCopy() *KlusterPool {
 	if in == nil {
 		return nil
 	}
-	out := new(KlusterStatus)
+	out := new(KlusterPool)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodePool) DeepCopyInto(out *NodePool) {
+func (in *KlusterPoolList) DeepCopyInto(out *KlusterPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KlusterPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterPoolList.
+func (in *KlusterPoolList) DeepCopy() *KlusterPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterPoolSpec) DeepCopyInto(out *KlusterPoolSpec) {
 	*out = *in
+	in.KlusterSpec.DeepCopyInto(&out.KlusterSpec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
-func (in *NodePool) DeepCopy() *NodePool {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterPoolSpec.
+func (in *KlusterPoolSpec) DeepCopy() *KlusterPoolSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NodePool)
+	out := new(KlusterPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterPoolStatus) DeepCopyInto(out *KlusterPoolStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterPoolStatus.
+func (in *KlusterPoolStatus) DeepCopy() *KlusterPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterQuota) DeepCopyInto(out *KlusterQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterQuota.
+func (in *KlusterQuota) DeepCopy() *KlusterQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterQuotaList) DeepCopyInto(out *KlusterQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KlusterQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterQuotaList.
+func (in *KlusterQuotaList) DeepCopy() *KlusterQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterQuotaSpec) DeepCopyInto(out *KlusterQuotaSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterQuotaSpec.
+func (in *KlusterQuotaSpec) DeepCopy() *KlusterQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterQuotaStatus) DeepCopyInto(out *KlusterQuotaStatus) {
+	*out = *in
+	if in.Klusters != nil {
+		in, out := &in.Klusters, &out.Klusters
+		*out = make([]KlusterQuotaUsage, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterQuotaStatus.
+func (in *KlusterQuotaStatus) DeepCopy() *KlusterQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterQuotaUsage) DeepCopyInto(out *KlusterQuotaUsage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterQuotaUsage.
+func (in *KlusterQuotaUsage) DeepCopy() *KlusterQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterRollout) DeepCopyInto(out *KlusterRollout) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterRollout.
+func (in *KlusterRollout) DeepCopy() *KlusterRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterRollout) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterRolloutList) DeepCopyInto(out *KlusterRolloutList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KlusterRollout, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterRolloutList.
+func (in *KlusterRolloutList) DeepCopy() *KlusterRolloutList {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterRolloutList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterRolloutList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterRolloutSpec) DeepCopyInto(out *KlusterRolloutSpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SoakTime != nil {
+		in, out := &in.SoakTime, &out.SoakTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterRolloutSpec.
+func (in *KlusterRolloutSpec) DeepCopy() *KlusterRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterRolloutStatus) DeepCopyInto(out *KlusterRolloutStatus) {
+	*out = *in
+	if in.Klusters != nil {
+		in, out := &in.Klusters, &out.Klusters
+		*out = make([]RolloutKlusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterRolloutStatus.
+func (in *KlusterRolloutStatus) DeepCopy() *KlusterRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterSet) DeepCopyInto(out *KlusterSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterSet.
+func (in *KlusterSet) DeepCopy() *KlusterSet {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterSetList) DeepCopyInto(out *KlusterSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KlusterSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterSetList.
+func (in *KlusterSetList) DeepCopy() *KlusterSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterSetRegionStatus) DeepCopyInto(out *KlusterSetRegionStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterSetRegionStatus.
+func (in *KlusterSetRegionStatus) DeepCopy() *KlusterSetRegionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterSetRegionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterSetSpec) DeepCopyInto(out *KlusterSetSpec) {
+	*out = *in
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.KlusterSpec.DeepCopyInto(&out.KlusterSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterSetSpec.
+func (in *KlusterSetSpec) DeepCopy() *KlusterSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterSetStatus) DeepCopyInto(out *KlusterSetStatus) {
+	*out = *in
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]KlusterSetRegionStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterSetStatus.
+func (in *KlusterSetStatus) DeepCopy() *KlusterSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterSpec) DeepCopyInto(out *KlusterSpec) {
+	*out = *in
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HA != nil {
+		in, out := &in.HA, &out.HA
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoUpgrade != nil {
+		in, out := &in.AutoUpgrade, &out.AutoUpgrade
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SurgeUpgrade != nil {
+		in, out := &in.SurgeUpgrade, &out.SurgeUpgrade
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaintenancePolicy != nil {
+		in, out := &in.MaintenancePolicy, &out.MaintenancePolicy
+		*out = new(MaintenancePolicy)
+		**out = **in
+	}
+	if in.RegistryEnabled != nil {
+		in, out := &in.RegistryEnabled, &out.RegistryEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterSpec.
+func (in *KlusterSpec) DeepCopy() *KlusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterStatus) DeepCopyInto(out *KlusterStatus) {
+	*out = *in
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePoolStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScheduledScaling != nil {
+		in, out := &in.ScheduledScaling, &out.ScheduledScaling
+		*out = make([]ScheduledScalingStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterStatus.
+func (in *KlusterStatus) DeepCopy() *KlusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterTemplate) DeepCopyInto(out *KlusterTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterTemplate.
+func (in *KlusterTemplate) DeepCopy() *KlusterTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterTemplateList) DeepCopyInto(out *KlusterTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KlusterTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterTemplateList.
+func (in *KlusterTemplateList) DeepCopy() *KlusterTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KlusterTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KlusterTemplateSpec) DeepCopyInto(out *KlusterTemplateSpec) {
+	*out = *in
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HA != nil {
+		in, out := &in.HA, &out.HA
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoUpgrade != nil {
+		in, out := &in.AutoUpgrade, &out.AutoUpgrade
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SurgeUpgrade != nil {
+		in, out := &in.SurgeUpgrade, &out.SurgeUpgrade
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaintenancePolicy != nil {
+		in, out := &in.MaintenancePolicy, &out.MaintenancePolicy
+		*out = new(MaintenancePolicy)
+		**out = **in
+	}
+	if in.RegistryEnabled != nil {
+		in, out := &in.RegistryEnabled, &out.RegistryEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KlusterTemplateSpec.
+func (in *KlusterTemplateSpec) DeepCopy() *KlusterTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KlusterTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenancePolicy) DeepCopyInto(out *MaintenancePolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenancePolicy.
+func (in *MaintenancePolicy) DeepCopy() *MaintenancePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenancePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePool) DeepCopyInto(out *NodePool) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]Taint, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]ScalingSchedule, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
+func (in *NodePool) DeepCopy() *NodePool {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolStatus.
+func (in *NodePoolStatus) DeepCopy() *NodePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeStatus.
+func (in *NodeStatus) DeepCopy() *NodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutKlusterStatus) DeepCopyInto(out *RolloutKlusterStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ReadyAt != nil {
+		in, out := &in.ReadyAt, &out.ReadyAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutKlusterStatus.
+func (in *RolloutKlusterStatus) DeepCopy() *RolloutKlusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutKlusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingSchedule) DeepCopyInto(out *ScalingSchedule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingSchedule.
+func (in *ScalingSchedule) DeepCopy() *ScalingSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledScalingStatus) DeepCopyInto(out *ScheduledScalingStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledScalingStatus.
+func (in *ScheduledScalingStatus) DeepCopy() *ScheduledScalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledScalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Taint) DeepCopyInto(out *Taint) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Taint.
+func (in *Taint) DeepCopy() *Taint {
+	if in == nil {
+		return nil
+	}
+	out := new(Taint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionRange) DeepCopyInto(out *VersionRange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionRange.
+func (in *VersionRange) DeepCopy() *VersionRange {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionRange)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,6 @@
+// +k8s:deepcopy-gen=package
+// +groupName=inspirit941.dev
+
+// v1beta1: secret 참조를 struct로 바꾸고, provider / node pool label·tag 등을 추가한 버전.
+// storage version이자 conversion의 hub 역할을 한다. v1alpha1은 ConvertTo / ConvertFrom으로 이 버전과 변환됨.
+package v1beta1
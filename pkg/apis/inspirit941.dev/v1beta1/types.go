@@ -0,0 +1,403 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Kluster is a Kubernetes cluster provisioned and managed by the kluster operator.
+// +genclient
+// +genclient:method=GetScale,verb=get,subresource=scale,result=k8s.io/api/autoscaling/v1.Scale
+// +genclient:method=UpdateScale,verb=update,subresource=scale,input=k8s.io/api/autoscaling/v1.Scale,result=k8s.io/api/autoscaling/v1.Scale
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:printcolumn:name="ClusterID",type=string,JSONPath=`.status.clusterID`
+// +kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.progress`
+// +kubebuilder:printcolumn:name="Region",type=string,JSONPath=`.spec.region`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.version`
+// +kubebuilder:printcolumn:name="Nodes",type=integer,JSONPath=`.status.nodes`
+// +kubebuilder:printcolumn:name="Expires",type=string,format=date-time,JSONPath=`.status.expiresAt`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type Kluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KlusterSpec   `json:"spec,omitempty"`
+	Status KlusterStatus `json:"status,omitempty"`
+}
+
+// Hub: conversion의 hub 버전임을 표시한다. 다른 버전(spoke)은 이 버전으로 / 이 버전에서 변환된다.
+func (*Kluster) Hub() {}
+
+// Provider is the cloud provider a cluster is created in.
+// +kubebuilder:validation:Enum=digitalocean
+type Provider string
+
+const (
+	ProviderDigitalOcean Provider = "digitalocean"
+)
+
+// KlusterSpec is the desired state of the cluster.
+// +kubebuilder:validation:XValidation:rule="!has(self.primaryNodePool) || has(self.template) || (has(self.nodePools) && self.nodePools.exists(p, p.name == self.primaryNodePool))",message="primaryNodePool must be one of nodePools"
+type KlusterSpec struct {
+	// Name of a KlusterTemplate in the same namespace. Fields set in this spec take precedence over the template,
+	// node pools are merged by name, and defaults are applied to the merged spec.
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	Template string `json:"template,omitempty"`
+	// Cloud provider the cluster is created in.
+	// +optional
+	// +kubebuilder:default=digitalocean
+	Provider Provider `json:"provider,omitempty"`
+	// Name of the cluster. Defaults to metadata.name. Cannot be changed after creation.
+	// +optional
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	Name string `json:"name,omitempty"`
+	// Region slug the cluster is created in, i.e. nyc1. Cannot be changed after creation.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z]{3}[0-9]$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="region is immutable"
+	Region string `json:"region,omitempty"`
+	// Kubernetes version slug, i.e. 1.25.4-do.0, or "latest" for the latest stable version.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(latest|[0-9]+\.[0-9]+\.[0-9]+-do\.[0-9]+)$`
+	Version string `json:"version,omitempty"`
+	// Secret holding the provider API token.
+	// +optional
+	TokenSecretRef SecretReference `json:"tokenSecretRef,omitempty"`
+
+	// Node pools of the cluster. Pool names must be unique.
+	// +optional
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=32
+	// +kubebuilder:validation:XValidation:rule="self.all(p, self.exists_one(q, q.name == p.name))",message="node pool names must be unique"
+	NodePools []NodePool `json:"nodePools,omitempty"`
+
+	// Name of the node pool resized by the scale subresource (kubectl scale). Defaults to the first node pool.
+	// +optional
+	PrimaryNodePool string `json:"primaryNodePool,omitempty"`
+	// Node count of the primary node pool. Overrides the count of that pool, so that the scale subresource can resize it.
+	// Defaults to the count of the primary node pool.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=512
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// UUID of the VPC the cluster is created in. Defaults to the region's default VPC. Cannot be changed after creation.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="vpcUUID is immutable"
+	VPCUUID string `json:"vpcUUID,omitempty"`
+	// Tags applied to the cluster. DigitalOcean additionally tags every cluster with k8s and k8s:<cluster id>.
+	// +optional
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_:\-]{1,255}$`
+	Tags []string `json:"tags,omitempty"`
+	// Run a highly available control plane. HA can be enabled on an existing cluster but cannot be disabled.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self || !oldSelf",message="ha cannot be disabled once enabled"
+	HA *bool `json:"ha,omitempty"`
+	// Upgrade the cluster to the latest patch release automatically during the maintenance window.
+	// +optional
+	AutoUpgrade *bool `json:"autoUpgrade,omitempty"`
+	// Create new nodes before draining old ones during upgrades. Surge upgrade can be enabled on an existing cluster but cannot be disabled.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self || !oldSelf",message="surgeUpgrade cannot be disabled once enabled"
+	SurgeUpgrade *bool `json:"surgeUpgrade,omitempty"`
+	// Maintenance window for automatic upgrades. DigitalOcean picks a window when empty.
+	// +optional
+	MaintenancePolicy *MaintenancePolicy `json:"maintenancePolicy,omitempty"`
+	// Integrate the account's DigitalOcean Container Registry with the cluster.
+	// +optional
+	RegistryEnabled *bool `json:"registryEnabled,omitempty"`
+	// What happens to the cloud cluster when the Kluster is deleted. Delete removes the cluster,
+	// Retain keeps it running and only removes the tags that mark it as managed by the Kluster. Defaults to Delete.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// Whether drift between the spec and the cloud cluster found on resync is corrected or only reported
+	// in the Drifted condition. Changes to the spec are always applied. Defaults to Correct.
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// Lifetime of the Kluster counted from its creation, i.e. 4h. When it expires the Kluster is deleted,
+	// and the cloud cluster with it according to deletionPolicy.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// DeletionPolicy decides what happens to the cloud cluster when its Kluster is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain
+type DeletionPolicy string
+
+const (
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
+// DriftPolicy decides what the controller does when the cloud cluster no longer matches the spec.
+// +kubebuilder:validation:Enum=Correct;Report
+type DriftPolicy string
+
+const (
+	DriftPolicyCorrect DriftPolicy = "Correct"
+	DriftPolicyReport  DriftPolicy = "Report"
+)
+
+// MaintenancePolicy is the weekly window DigitalOcean may use for maintenance and automatic upgrades.
+type MaintenancePolicy struct {
+	// Day of the week, or "any".
+	// +optional
+	// +kubebuilder:validation:Enum=any;monday;tuesday;wednesday;thursday;friday;saturday;sunday
+	Day string `json:"day,omitempty"`
+	// Start time of the window in UTC, in the form HH:MM.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	StartTime string `json:"startTime,omitempty"`
+}
+
+// SecretReference points to a key of a Secret.
+type SecretReference struct {
+	// Namespace of the secret.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the secret.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Key in the secret's data holding the token.
+	// +optional
+	// +kubebuilder:default=token
+	Key string `json:"key,omitempty"`
+}
+
+// NodePool is a group of nodes of the same size in the cluster.
+// +kubebuilder:validation:XValidation:rule="!has(self.autoScaling) || !has(self.schedules) || size(self.schedules) == 0",message="schedules cannot be used when autoScaling is set"
+type NodePool struct {
+	// Droplet size slug of the nodes, i.e. s-2vcpu-2gb.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+(-[a-z0-9]+)+$`
+	Size string `json:"size,omitempty"`
+	// Name of the node pool, unique within the cluster.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name,omitempty"`
+	// Number of nodes in the pool. When autoScaling is set this is the initial node count.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=512
+	Count int `json:"count,omitempty"`
+	// Enables the cluster autoscaler for the pool within the given node range. Count is then only the initial node count.
+	// +optional
+	AutoScaling *NodePoolAutoScaling `json:"autoScaling,omitempty"`
+	// Kubernetes labels applied to the nodes of the pool.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Kubernetes taints applied to the nodes of the pool.
+	// +optional
+	// +kubebuilder:validation:MaxItems=50
+	Taints []Taint `json:"taints,omitempty"`
+	// Tags applied to the droplets of the pool.
+	// +optional
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_:\-]{1,255}$`
+	Tags []string `json:"tags,omitempty"`
+	// Cron schedules that change the node count of the pool, i.e. scale down at night and on weekends.
+	// The count of the schedule that fired last is used until the next schedule fires or count is changed in the spec.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=20
+	Schedules []ScalingSchedule `json:"schedules,omitempty"`
+}
+
+// NodePoolAutoScaling is the node range the cluster autoscaler keeps a node pool in.
+// +kubebuilder:validation:XValidation:rule="(has(self.minNodes) ? self.minNodes : 0) <= self.maxNodes",message="maxNodes must not be less than minNodes"
+type NodePoolAutoScaling struct {
+	// Minimum number of nodes the autoscaler can scale the pool down to.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=512
+	MinNodes int `json:"minNodes,omitempty"`
+	// Maximum number of nodes the autoscaler can scale the pool up to.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=512
+	MaxNodes int `json:"maxNodes"`
+}
+
+// ScalingSchedule sets the node count of a node pool at the times given by a cron expression.
+type ScalingSchedule struct {
+	// Name of the schedule, unique within the node pool.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Cron expression in the standard five field format, i.e. "0 20 * * 1-5" for 20:00 on weekdays.
+	Schedule string `json:"schedule"`
+	// IANA time zone the schedule is evaluated in, i.e. Asia/Seoul. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// Node count of the pool from the time the schedule fires.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=512
+	Count int `json:"count"`
+}
+
+// Taint is a Kubernetes taint applied to the nodes of a node pool.
+type Taint struct {
+	// Taint key.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+	// Taint value.
+	// +optional
+	Value string `json:"value,omitempty"`
+	// Taint effect.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=NoSchedule;PreferNoSchedule;NoExecute
+	Effect string `json:"effect"`
+}
+
+// KlusterList is a list of Klusters.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KlusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Kluster `json:"items,omitempty"`
+}
+
+// KlusterStatus is the observed state of the cluster, written by the controller.
+type KlusterStatus struct {
+	// ID of the cluster in the provider.
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+	// Provisioning progress of the cluster, i.e. creating, running, degraded or upgrading.
+	// +optional
+	Progress string `json:"progress,omitempty"`
+	// Kubeconfig of the cluster.
+	// +optional
+	KubeConfig string `json:"kubeConfig,omitempty"`
+	// Kubernetes version slug the cluster is running.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// URL of the Kubernetes API server.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// Public IPv4 address of the Kubernetes API server.
+	// +optional
+	IPv4 string `json:"ipv4,omitempty"`
+	// UUID of the VPC the cluster runs in.
+	// +optional
+	VPCUUID string `json:"vpcUUID,omitempty"`
+	// Pod network CIDR of the cluster.
+	// +optional
+	ClusterSubnet string `json:"clusterSubnet,omitempty"`
+	// Service network CIDR of the cluster.
+	// +optional
+	ServiceSubnet string `json:"serviceSubnet,omitempty"`
+	// Human readable message from the provider about the cluster's state.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Time the cluster was created in the provider.
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+	// Time the cluster was last updated in the provider.
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+	// Total number of nodes across all node pools.
+	// +optional
+	Nodes int32 `json:"nodes,omitempty"`
+
+	// Time the Kluster expires and is deleted, set when spec.ttl is set.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// Estimated monthly cost of the node pools in USD, i.e. "72.00", recorded when the spec is applied to the cloud cluster.
+	// A higher cost is reserved before it is applied. Counted against the KlusterQuotas of the namespace.
+	// +optional
+	EstimatedMonthlyCost string `json:"estimatedMonthlyCost,omitempty"`
+	// Node count of the spec last applied to the cloud cluster, counting maxNodes of autoscaled pools and the largest
+	// schedule count. Used as the Kluster's current usage for the namespace node limit of KlusterPolicies.
+	// +optional
+	AppliedNodes int32 `json:"appliedNodes,omitempty"`
+
+	// Generation of the spec that was last applied to the cloud cluster.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Generation of the KlusterTemplate that was last applied to the cloud cluster.
+	// +optional
+	ObservedTemplateGeneration int64 `json:"observedTemplateGeneration,omitempty"`
+	// Actual node count of the primary node pool.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// Label selector of the nodes of the primary node pool, used by the scale subresource.
+	// +optional
+	Selector string `json:"selector,omitempty"`
+	// Observed state of each node pool.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	NodePools []NodePoolStatus `json:"nodePools,omitempty"`
+
+	// State of the scheduled scaling of each node pool that has schedules.
+	// +optional
+	// +listType=map
+	// +listMapKey=nodePool
+	ScheduledScaling []ScheduledScalingStatus `json:"scheduledScaling,omitempty"`
+
+	// Latest observations of the Kluster's state, one per condition type.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// NodePoolStatus is the observed state of a node pool.
+type NodePoolStatus struct {
+	// Name of the node pool.
+	Name string `json:"name"`
+	// ID of the node pool in the provider.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Actual number of nodes in the pool.
+	// +optional
+	Count int `json:"count,omitempty"`
+	// Nodes of the pool.
+	// +optional
+	Nodes []NodeStatus `json:"nodes,omitempty"`
+}
+
+// ScheduledScalingStatus is the state of the schedules of a node pool.
+type ScheduledScalingStatus struct {
+	// Name of the node pool.
+	NodePool string `json:"nodePool"`
+	// Schedule whose count is in effect. Empty when the count in the spec is used.
+	// +optional
+	ActiveSchedule string `json:"activeSchedule,omitempty"`
+	// Node count set by the active schedule.
+	// +optional
+	Count int `json:"count,omitempty"`
+	// Count in the spec when the active schedule fired. Once the spec count is changed the spec is used again.
+	// +optional
+	SpecCount int `json:"specCount,omitempty"`
+	// Time the active schedule fired.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// Schedule that fires next.
+	// +optional
+	NextSchedule string `json:"nextSchedule,omitempty"`
+	// Time the next schedule fires.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+	// Node count the pool is scaled to when the next schedule fires.
+	// +optional
+	NextCount int `json:"nextCount,omitempty"`
+}
+
+// NodeStatus is the observed state of a node in a node pool.
+type NodeStatus struct {
+	// Name of the node.
+	Name string `json:"name"`
+	// Provisioning state of the node, i.e. provisioning, running or draining.
+	// +optional
+	State string `json:"state,omitempty"`
+}
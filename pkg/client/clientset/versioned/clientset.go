@@ -23,6 +23,7 @@ import (
 	"net/http"
 
 	inspirit941v1alpha1 "github.com/inspirit941/kluster/pkg/client/clientset/versioned/typed/inspirit941.dev/v1alpha1"
+	inspirit941v1beta1 "github.com/inspirit941/kluster/pkg/client/clientset/versioned/typed/inspirit941.dev/v1beta1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -31,12 +32,14 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	Inspirit941V1alpha1() inspirit941v1alpha1.Inspirit941V1alpha1Interface
+	Inspirit941V1beta1() inspirit941v1beta1.Inspirit941V1beta1Interface
 }
 
 // Clientset contains the clients for groups.
 type Clientset struct {
 	*discovery.DiscoveryClient
 	inspirit941V1alpha1 *inspirit941v1alpha1.Inspirit941V1alpha1Client
+	inspirit941V1beta1  *inspirit941v1beta1.Inspirit941V1beta1Client
 }
 
 // Inspirit941V1alpha1 retrieves the Inspirit941V1alpha1Client
@@ -44,6 +47,11 @@ func (c *Clientset) Inspirit941V1alpha1() inspirit941v1alpha1.Inspirit941V1alpha
 	return c.inspirit941V1alpha1
 }
 
+// Inspirit941V1beta1 retrieves the Inspirit941V1beta1Client
+func (c *Clientset) Inspirit941V1beta1() inspirit941v1beta1.Inspirit941V1beta1Interface {
+	return c.inspirit941V1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -92,6 +100,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.inspirit941V1beta1, err = inspirit941v1beta1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -114,6 +126,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.inspirit941V1alpha1 = inspirit941v1alpha1.New(c)
+	cs.inspirit941V1beta1 = inspirit941v1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -22,6 +22,8 @@ import (
 	clientset "github.com/inspirit941/kluster/pkg/client/clientset/versioned"
 	inspirit941v1alpha1 "github.com/inspirit941/kluster/pkg/client/clientset/versioned/typed/inspirit941.dev/v1alpha1"
 	fakeinspirit941v1alpha1 "github.com/inspirit941/kluster/pkg/client/clientset/versioned/typed/inspirit941.dev/v1alpha1/fake"
+	inspirit941v1beta1 "github.com/inspirit941/kluster/pkg/client/clientset/versioned/typed/inspirit941.dev/v1beta1"
+	fakeinspirit941v1beta1 "github.com/inspirit941/kluster/pkg/client/clientset/versioned/typed/inspirit941.dev/v1beta1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -83,3 +85,8 @@ var (
 func (c *Clientset) Inspirit941V1alpha1() inspirit941v1alpha1.Inspirit941V1alpha1Interface {
 	return &fakeinspirit941v1alpha1.FakeInspirit941V1alpha1{Fake: &c.Fake}
 }
+
+// Inspirit941V1beta1 retrieves the Inspirit941V1beta1Client
+func (c *Clientset) Inspirit941V1beta1() inspirit941v1beta1.Inspirit941V1beta1Interface {
+	return &fakeinspirit941v1beta1.FakeInspirit941V1beta1{Fake: &c.Fake}
+}
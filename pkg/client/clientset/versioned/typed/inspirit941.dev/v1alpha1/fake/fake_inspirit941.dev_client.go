@@ -32,6 +32,34 @@ func (c *FakeInspirit941V1alpha1) Klusters(namespace string) v1alpha1.KlusterInt
 	return &FakeKlusters{c, namespace}
 }
 
+func (c *FakeInspirit941V1alpha1) KlusterClaims(namespace string) v1alpha1.KlusterClaimInterface {
+	return &FakeKlusterClaims{c, namespace}
+}
+
+func (c *FakeInspirit941V1alpha1) KlusterPolicies(namespace string) v1alpha1.KlusterPolicyInterface {
+	return &FakeKlusterPolicies{c, namespace}
+}
+
+func (c *FakeInspirit941V1alpha1) KlusterPools(namespace string) v1alpha1.KlusterPoolInterface {
+	return &FakeKlusterPools{c, namespace}
+}
+
+func (c *FakeInspirit941V1alpha1) KlusterQuotas(namespace string) v1alpha1.KlusterQuotaInterface {
+	return &FakeKlusterQuotas{c, namespace}
+}
+
+func (c *FakeInspirit941V1alpha1) KlusterRollouts(namespace string) v1alpha1.KlusterRolloutInterface {
+	return &FakeKlusterRollouts{c, namespace}
+}
+
+func (c *FakeInspirit941V1alpha1) KlusterSets(namespace string) v1alpha1.KlusterSetInterface {
+	return &FakeKlusterSets{c, namespace}
+}
+
+func (c *FakeInspirit941V1alpha1) KlusterTemplates(namespace string) v1alpha1.KlusterTemplateInterface {
+	return &FakeKlusterTemplates{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeInspirit941V1alpha1) RESTClient() rest.Interface {
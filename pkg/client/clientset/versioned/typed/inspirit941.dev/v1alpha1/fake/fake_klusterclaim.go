@@ -0,0 +1,142 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeKlusterClaims implements KlusterClaimInterface
+type FakeKlusterClaims struct {
+	Fake *FakeInspirit941V1alpha1
+	ns   string
+}
+
+var klusterclaimsResource = schema.GroupVersionResource{Group: "inspirit941.dev", Version: "v1alpha1", Resource: "klusterclaims"}
+
+var klusterclaimsKind = schema.GroupVersionKind{Group: "inspirit941.dev", Version: "v1alpha1", Kind: "KlusterClaim"}
+
+// Get takes name of the klusterClaim, and returns the corresponding klusterClaim object, and an error if there is any.
+func (c *FakeKlusterClaims) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.KlusterClaim, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(klusterclaimsResource, c.ns, name), &v1alpha1.KlusterClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterClaim), err
+}
+
+// List takes label and field selectors, and returns the list of KlusterClaims that match those selectors.
+func (c *FakeKlusterClaims) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.KlusterClaimList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(klusterclaimsResource, klusterclaimsKind, c.ns, opts), &v1alpha1.KlusterClaimList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.KlusterClaimList{ListMeta: obj.(*v1alpha1.KlusterClaimList).ListMeta}
+	for _, item := range obj.(*v1alpha1.KlusterClaimList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested klusterClaims.
+func (c *FakeKlusterClaims) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(klusterclaimsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a klusterClaim and creates it.  Returns the server's representation of the klusterClaim, and an error, if there is any.
+func (c *FakeKlusterClaims) Create(ctx context.Context, klusterClaim *v1alpha1.KlusterClaim, opts v1.CreateOptions) (result *v1alpha1.KlusterClaim, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(klusterclaimsResource, c.ns, klusterClaim), &v1alpha1.KlusterClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterClaim), err
+}
+
+// Update takes the representation of a klusterClaim and updates it. Returns the server's representation of the klusterClaim, and an error, if there is any.
+func (c *FakeKlusterClaims) Update(ctx context.Context, klusterClaim *v1alpha1.KlusterClaim, opts v1.UpdateOptions) (result *v1alpha1.KlusterClaim, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(klusterclaimsResource, c.ns, klusterClaim), &v1alpha1.KlusterClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterClaim), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeKlusterClaims) UpdateStatus(ctx context.Context, klusterClaim *v1alpha1.KlusterClaim, opts v1.UpdateOptions) (*v1alpha1.KlusterClaim, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(klusterclaimsResource, "status", c.ns, klusterClaim), &v1alpha1.KlusterClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterClaim), err
+}
+
+// Delete takes name of the klusterClaim and deletes it. Returns an error if one occurs.
+func (c *FakeKlusterClaims) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(klusterclaimsResource, c.ns, name, opts), &v1alpha1.KlusterClaim{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeKlusterClaims) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(klusterclaimsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.KlusterClaimList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched klusterClaim.
+func (c *FakeKlusterClaims) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterClaim, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(klusterclaimsResource, c.ns, name, pt, data, subresources...), &v1alpha1.KlusterClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterClaim), err
+}
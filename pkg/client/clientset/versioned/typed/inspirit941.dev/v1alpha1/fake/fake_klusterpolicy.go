@@ -0,0 +1,130 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeKlusterPolicies implements KlusterPolicyInterface
+type FakeKlusterPolicies struct {
+	Fake *FakeInspirit941V1alpha1
+	ns   string
+}
+
+var klusterpoliciesResource = schema.GroupVersionResource{Group: "inspirit941.dev", Version: "v1alpha1", Resource: "klusterpolicies"}
+
+var klusterpoliciesKind = schema.GroupVersionKind{Group: "inspirit941.dev", Version: "v1alpha1", Kind: "KlusterPolicy"}
+
+// Get takes name of the klusterPolicy, and returns the corresponding klusterPolicy object, and an error if there is any.
+func (c *FakeKlusterPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.KlusterPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(klusterpoliciesResource, c.ns, name), &v1alpha1.KlusterPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterPolicy), err
+}
+
+// List takes label and field selectors, and returns the list of KlusterPolicies that match those selectors.
+func (c *FakeKlusterPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.KlusterPolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(klusterpoliciesResource, klusterpoliciesKind, c.ns, opts), &v1alpha1.KlusterPolicyList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.KlusterPolicyList{ListMeta: obj.(*v1alpha1.KlusterPolicyList).ListMeta}
+	for _, item := range obj.(*v1alpha1.KlusterPolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested klusterPolicies.
+func (c *FakeKlusterPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(klusterpoliciesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a klusterPolicy and creates it.  Returns the server's representation of the klusterPolicy, and an error, if there is any.
+func (c *FakeKlusterPolicies) Create(ctx context.Context, klusterPolicy *v1alpha1.KlusterPolicy, opts v1.CreateOptions) (result *v1alpha1.KlusterPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(klusterpoliciesResource, c.ns, klusterPolicy), &v1alpha1.KlusterPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterPolicy), err
+}
+
+// Update takes the representation of a klusterPolicy and updates it. Returns the server's representation of the klusterPolicy, and an error, if there is any.
+func (c *FakeKlusterPolicies) Update(ctx context.Context, klusterPolicy *v1alpha1.KlusterPolicy, opts v1.UpdateOptions) (result *v1alpha1.KlusterPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(klusterpoliciesResource, c.ns, klusterPolicy), &v1alpha1.KlusterPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterPolicy), err
+}
+
+// Delete takes name of the klusterPolicy and deletes it. Returns an error if one occurs.
+func (c *FakeKlusterPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(klusterpoliciesResource, c.ns, name, opts), &v1alpha1.KlusterPolicy{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeKlusterPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(klusterpoliciesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.KlusterPolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched klusterPolicy.
+func (c *FakeKlusterPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(klusterpoliciesResource, c.ns, name, pt, data, subresources...), &v1alpha1.KlusterPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterPolicy), err
+}
@@ -0,0 +1,142 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeKlusterRollouts implements KlusterRolloutInterface
+type FakeKlusterRollouts struct {
+	Fake *FakeInspirit941V1alpha1
+	ns   string
+}
+
+var klusterrolloutsResource = schema.GroupVersionResource{Group: "inspirit941.dev", Version: "v1alpha1", Resource: "klusterrollouts"}
+
+var klusterrolloutsKind = schema.GroupVersionKind{Group: "inspirit941.dev", Version: "v1alpha1", Kind: "KlusterRollout"}
+
+// Get takes name of the klusterRollout, and returns the corresponding klusterRollout object, and an error if there is any.
+func (c *FakeKlusterRollouts) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.KlusterRollout, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(klusterrolloutsResource, c.ns, name), &v1alpha1.KlusterRollout{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterRollout), err
+}
+
+// List takes label and field selectors, and returns the list of KlusterRollouts that match those selectors.
+func (c *FakeKlusterRollouts) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.KlusterRolloutList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(klusterrolloutsResource, klusterrolloutsKind, c.ns, opts), &v1alpha1.KlusterRolloutList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.KlusterRolloutList{ListMeta: obj.(*v1alpha1.KlusterRolloutList).ListMeta}
+	for _, item := range obj.(*v1alpha1.KlusterRolloutList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested klusterRollouts.
+func (c *FakeKlusterRollouts) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(klusterrolloutsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a klusterRollout and creates it.  Returns the server's representation of the klusterRollout, and an error, if there is any.
+func (c *FakeKlusterRollouts) Create(ctx context.Context, klusterRollout *v1alpha1.KlusterRollout, opts v1.CreateOptions) (result *v1alpha1.KlusterRollout, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(klusterrolloutsResource, c.ns, klusterRollout), &v1alpha1.KlusterRollout{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterRollout), err
+}
+
+// Update takes the representation of a klusterRollout and updates it. Returns the server's representation of the klusterRollout, and an error, if there is any.
+func (c *FakeKlusterRollouts) Update(ctx context.Context, klusterRollout *v1alpha1.KlusterRollout, opts v1.UpdateOptions) (result *v1alpha1.KlusterRollout, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(klusterrolloutsResource, c.ns, klusterRollout), &v1alpha1.KlusterRollout{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterRollout), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeKlusterRollouts) UpdateStatus(ctx context.Context, klusterRollout *v1alpha1.KlusterRollout, opts v1.UpdateOptions) (*v1alpha1.KlusterRollout, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(klusterrolloutsResource, "status", c.ns, klusterRollout), &v1alpha1.KlusterRollout{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterRollout), err
+}
+
+// Delete takes name of the klusterRollout and deletes it. Returns an error if one occurs.
+func (c *FakeKlusterRollouts) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(klusterrolloutsResource, c.ns, name, opts), &v1alpha1.KlusterRollout{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeKlusterRollouts) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(klusterrolloutsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.KlusterRolloutList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched klusterRollout.
+func (c *FakeKlusterRollouts) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterRollout, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(klusterrolloutsResource, c.ns, name, pt, data, subresources...), &v1alpha1.KlusterRollout{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterRollout), err
+}
@@ -0,0 +1,142 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeKlusterSets implements KlusterSetInterface
+type FakeKlusterSets struct {
+	Fake *FakeInspirit941V1alpha1
+	ns   string
+}
+
+var klustersetsResource = schema.GroupVersionResource{Group: "inspirit941.dev", Version: "v1alpha1", Resource: "klustersets"}
+
+var klustersetsKind = schema.GroupVersionKind{Group: "inspirit941.dev", Version: "v1alpha1", Kind: "KlusterSet"}
+
+// Get takes name of the klusterSet, and returns the corresponding klusterSet object, and an error if there is any.
+func (c *FakeKlusterSets) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.KlusterSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(klustersetsResource, c.ns, name), &v1alpha1.KlusterSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterSet), err
+}
+
+// List takes label and field selectors, and returns the list of KlusterSets that match those selectors.
+func (c *FakeKlusterSets) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.KlusterSetList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(klustersetsResource, klustersetsKind, c.ns, opts), &v1alpha1.KlusterSetList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.KlusterSetList{ListMeta: obj.(*v1alpha1.KlusterSetList).ListMeta}
+	for _, item := range obj.(*v1alpha1.KlusterSetList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested klusterSets.
+func (c *FakeKlusterSets) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(klustersetsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a klusterSet and creates it.  Returns the server's representation of the klusterSet, and an error, if there is any.
+func (c *FakeKlusterSets) Create(ctx context.Context, klusterSet *v1alpha1.KlusterSet, opts v1.CreateOptions) (result *v1alpha1.KlusterSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(klustersetsResource, c.ns, klusterSet), &v1alpha1.KlusterSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterSet), err
+}
+
+// Update takes the representation of a klusterSet and updates it. Returns the server's representation of the klusterSet, and an error, if there is any.
+func (c *FakeKlusterSets) Update(ctx context.Context, klusterSet *v1alpha1.KlusterSet, opts v1.UpdateOptions) (result *v1alpha1.KlusterSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(klustersetsResource, c.ns, klusterSet), &v1alpha1.KlusterSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterSet), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeKlusterSets) UpdateStatus(ctx context.Context, klusterSet *v1alpha1.KlusterSet, opts v1.UpdateOptions) (*v1alpha1.KlusterSet, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(klustersetsResource, "status", c.ns, klusterSet), &v1alpha1.KlusterSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterSet), err
+}
+
+// Delete takes name of the klusterSet and deletes it. Returns an error if one occurs.
+func (c *FakeKlusterSets) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(klustersetsResource, c.ns, name, opts), &v1alpha1.KlusterSet{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeKlusterSets) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(klustersetsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.KlusterSetList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched klusterSet.
+func (c *FakeKlusterSets) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(klustersetsResource, c.ns, name, pt, data, subresources...), &v1alpha1.KlusterSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterSet), err
+}
@@ -0,0 +1,130 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeKlusterTemplates implements KlusterTemplateInterface
+type FakeKlusterTemplates struct {
+	Fake *FakeInspirit941V1alpha1
+	ns   string
+}
+
+var klustertemplatesResource = schema.GroupVersionResource{Group: "inspirit941.dev", Version: "v1alpha1", Resource: "klustertemplates"}
+
+var klustertemplatesKind = schema.GroupVersionKind{Group: "inspirit941.dev", Version: "v1alpha1", Kind: "KlusterTemplate"}
+
+// Get takes name of the klusterTemplate, and returns the corresponding klusterTemplate object, and an error if there is any.
+func (c *FakeKlusterTemplates) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.KlusterTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(klustertemplatesResource, c.ns, name), &v1alpha1.KlusterTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterTemplate), err
+}
+
+// List takes label and field selectors, and returns the list of KlusterTemplates that match those selectors.
+func (c *FakeKlusterTemplates) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.KlusterTemplateList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(klustertemplatesResource, klustertemplatesKind, c.ns, opts), &v1alpha1.KlusterTemplateList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.KlusterTemplateList{ListMeta: obj.(*v1alpha1.KlusterTemplateList).ListMeta}
+	for _, item := range obj.(*v1alpha1.KlusterTemplateList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested klusterTemplates.
+func (c *FakeKlusterTemplates) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(klustertemplatesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a klusterTemplate and creates it.  Returns the server's representation of the klusterTemplate, and an error, if there is any.
+func (c *FakeKlusterTemplates) Create(ctx context.Context, klusterTemplate *v1alpha1.KlusterTemplate, opts v1.CreateOptions) (result *v1alpha1.KlusterTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(klustertemplatesResource, c.ns, klusterTemplate), &v1alpha1.KlusterTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterTemplate), err
+}
+
+// Update takes the representation of a klusterTemplate and updates it. Returns the server's representation of the klusterTemplate, and an error, if there is any.
+func (c *FakeKlusterTemplates) Update(ctx context.Context, klusterTemplate *v1alpha1.KlusterTemplate, opts v1.UpdateOptions) (result *v1alpha1.KlusterTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(klustertemplatesResource, c.ns, klusterTemplate), &v1alpha1.KlusterTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterTemplate), err
+}
+
+// Delete takes name of the klusterTemplate and deletes it. Returns an error if one occurs.
+func (c *FakeKlusterTemplates) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(klustertemplatesResource, c.ns, name, opts), &v1alpha1.KlusterTemplate{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeKlusterTemplates) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(klustertemplatesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.KlusterTemplateList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched klusterTemplate.
+func (c *FakeKlusterTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(klustertemplatesResource, c.ns, name, pt, data, subresources...), &v1alpha1.KlusterTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterTemplate), err
+}
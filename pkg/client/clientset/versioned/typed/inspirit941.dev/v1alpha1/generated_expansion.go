@@ -19,3 +19,17 @@ limitations under the License.
 package v1alpha1
 
 type KlusterExpansion interface{}
+
+type KlusterClaimExpansion interface{}
+
+type KlusterPolicyExpansion interface{}
+
+type KlusterPoolExpansion interface{}
+
+type KlusterQuotaExpansion interface{}
+
+type KlusterRolloutExpansion interface{}
+
+type KlusterSetExpansion interface{}
+
+type KlusterTemplateExpansion interface{}
@@ -29,6 +29,13 @@ import (
 type Inspirit941V1alpha1Interface interface {
 	RESTClient() rest.Interface
 	KlustersGetter
+	KlusterClaimsGetter
+	KlusterPoliciesGetter
+	KlusterPoolsGetter
+	KlusterQuotasGetter
+	KlusterRolloutsGetter
+	KlusterSetsGetter
+	KlusterTemplatesGetter
 }
 
 // Inspirit941V1alpha1Client is used to interact with features provided by the inspirit941.dev group.
@@ -40,6 +47,34 @@ func (c *Inspirit941V1alpha1Client) Klusters(namespace string) KlusterInterface
 	return newKlusters(c, namespace)
 }
 
+func (c *Inspirit941V1alpha1Client) KlusterClaims(namespace string) KlusterClaimInterface {
+	return newKlusterClaims(c, namespace)
+}
+
+func (c *Inspirit941V1alpha1Client) KlusterPolicies(namespace string) KlusterPolicyInterface {
+	return newKlusterPolicies(c, namespace)
+}
+
+func (c *Inspirit941V1alpha1Client) KlusterPools(namespace string) KlusterPoolInterface {
+	return newKlusterPools(c, namespace)
+}
+
+func (c *Inspirit941V1alpha1Client) KlusterQuotas(namespace string) KlusterQuotaInterface {
+	return newKlusterQuotas(c, namespace)
+}
+
+func (c *Inspirit941V1alpha1Client) KlusterRollouts(namespace string) KlusterRolloutInterface {
+	return newKlusterRollouts(c, namespace)
+}
+
+func (c *Inspirit941V1alpha1Client) KlusterSets(namespace string) KlusterSetInterface {
+	return newKlusterSets(c, namespace)
+}
+
+func (c *Inspirit941V1alpha1Client) KlusterTemplates(namespace string) KlusterTemplateInterface {
+	return newKlusterTemplates(c, namespace)
+}
+
 // NewForConfig creates a new Inspirit941V1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -0,0 +1,195 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	scheme "github.com/inspirit941/kluster/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// KlusterClaimsGetter has a method to return a KlusterClaimInterface.
+// A group's client should implement this interface.
+type KlusterClaimsGetter interface {
+	KlusterClaims(namespace string) KlusterClaimInterface
+}
+
+// KlusterClaimInterface has methods to work with KlusterClaim resources.
+type KlusterClaimInterface interface {
+	Create(ctx context.Context, klusterClaim *v1alpha1.KlusterClaim, opts v1.CreateOptions) (*v1alpha1.KlusterClaim, error)
+	Update(ctx context.Context, klusterClaim *v1alpha1.KlusterClaim, opts v1.UpdateOptions) (*v1alpha1.KlusterClaim, error)
+	UpdateStatus(ctx context.Context, klusterClaim *v1alpha1.KlusterClaim, opts v1.UpdateOptions) (*v1alpha1.KlusterClaim, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.KlusterClaim, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.KlusterClaimList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterClaim, err error)
+	KlusterClaimExpansion
+}
+
+// klusterClaims implements KlusterClaimInterface
+type klusterClaims struct {
+	client rest.Interface
+	ns     string
+}
+
+// newKlusterClaims returns a KlusterClaims
+func newKlusterClaims(c *Inspirit941V1alpha1Client, namespace string) *klusterClaims {
+	return &klusterClaims{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the klusterClaim, and returns the corresponding klusterClaim object, and an error if there is any.
+func (c *klusterClaims) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.KlusterClaim, err error) {
+	result = &v1alpha1.KlusterClaim{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("klusterclaims").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of KlusterClaims that match those selectors.
+func (c *klusterClaims) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.KlusterClaimList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.KlusterClaimList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("klusterclaims").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested klusterClaims.
+func (c *klusterClaims) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("klusterclaims").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a klusterClaim and creates it.  Returns the server's representation of the klusterClaim, and an error, if there is any.
+func (c *klusterClaims) Create(ctx context.Context, klusterClaim *v1alpha1.KlusterClaim, opts v1.CreateOptions) (result *v1alpha1.KlusterClaim, err error) {
+	result = &v1alpha1.KlusterClaim{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("klusterclaims").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterClaim).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a klusterClaim and updates it. Returns the server's representation of the klusterClaim, and an error, if there is any.
+func (c *klusterClaims) Update(ctx context.Context, klusterClaim *v1alpha1.KlusterClaim, opts v1.UpdateOptions) (result *v1alpha1.KlusterClaim, err error) {
+	result = &v1alpha1.KlusterClaim{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("klusterclaims").
+		Name(klusterClaim.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterClaim).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *klusterClaims) UpdateStatus(ctx context.Context, klusterClaim *v1alpha1.KlusterClaim, opts v1.UpdateOptions) (result *v1alpha1.KlusterClaim, err error) {
+	result = &v1alpha1.KlusterClaim{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("klusterclaims").
+		Name(klusterClaim.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterClaim).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the klusterClaim and deletes it. Returns an error if one occurs.
+func (c *klusterClaims) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("klusterclaims").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *klusterClaims) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("klusterclaims").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched klusterClaim.
+func (c *klusterClaims) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterClaim, err error) {
+	result = &v1alpha1.KlusterClaim{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("klusterclaims").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
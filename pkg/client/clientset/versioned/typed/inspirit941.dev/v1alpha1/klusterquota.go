@@ -0,0 +1,195 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	scheme "github.com/inspirit941/kluster/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// KlusterQuotasGetter has a method to return a KlusterQuotaInterface.
+// A group's client should implement this interface.
+type KlusterQuotasGetter interface {
+	KlusterQuotas(namespace string) KlusterQuotaInterface
+}
+
+// KlusterQuotaInterface has methods to work with KlusterQuota resources.
+type KlusterQuotaInterface interface {
+	Create(ctx context.Context, klusterQuota *v1alpha1.KlusterQuota, opts v1.CreateOptions) (*v1alpha1.KlusterQuota, error)
+	Update(ctx context.Context, klusterQuota *v1alpha1.KlusterQuota, opts v1.UpdateOptions) (*v1alpha1.KlusterQuota, error)
+	UpdateStatus(ctx context.Context, klusterQuota *v1alpha1.KlusterQuota, opts v1.UpdateOptions) (*v1alpha1.KlusterQuota, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.KlusterQuota, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.KlusterQuotaList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterQuota, err error)
+	KlusterQuotaExpansion
+}
+
+// klusterQuotas implements KlusterQuotaInterface
+type klusterQuotas struct {
+	client rest.Interface
+	ns     string
+}
+
+// newKlusterQuotas returns a KlusterQuotas
+func newKlusterQuotas(c *Inspirit941V1alpha1Client, namespace string) *klusterQuotas {
+	return &klusterQuotas{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the klusterQuota, and returns the corresponding klusterQuota object, and an error if there is any.
+func (c *klusterQuotas) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.KlusterQuota, err error) {
+	result = &v1alpha1.KlusterQuota{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("klusterquotas").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of KlusterQuotas that match those selectors.
+func (c *klusterQuotas) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.KlusterQuotaList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.KlusterQuotaList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("klusterquotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested klusterQuotas.
+func (c *klusterQuotas) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("klusterquotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a klusterQuota and creates it.  Returns the server's representation of the klusterQuota, and an error, if there is any.
+func (c *klusterQuotas) Create(ctx context.Context, klusterQuota *v1alpha1.KlusterQuota, opts v1.CreateOptions) (result *v1alpha1.KlusterQuota, err error) {
+	result = &v1alpha1.KlusterQuota{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("klusterquotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterQuota).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a klusterQuota and updates it. Returns the server's representation of the klusterQuota, and an error, if there is any.
+func (c *klusterQuotas) Update(ctx context.Context, klusterQuota *v1alpha1.KlusterQuota, opts v1.UpdateOptions) (result *v1alpha1.KlusterQuota, err error) {
+	result = &v1alpha1.KlusterQuota{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("klusterquotas").
+		Name(klusterQuota.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterQuota).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *klusterQuotas) UpdateStatus(ctx context.Context, klusterQuota *v1alpha1.KlusterQuota, opts v1.UpdateOptions) (result *v1alpha1.KlusterQuota, err error) {
+	result = &v1alpha1.KlusterQuota{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("klusterquotas").
+		Name(klusterQuota.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterQuota).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the klusterQuota and deletes it. Returns an error if one occurs.
+func (c *klusterQuotas) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("klusterquotas").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *klusterQuotas) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("klusterquotas").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched klusterQuota.
+func (c *klusterQuotas) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterQuota, err error) {
+	result = &v1alpha1.KlusterQuota{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("klusterquotas").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,195 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	scheme "github.com/inspirit941/kluster/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// KlusterRolloutsGetter has a method to return a KlusterRolloutInterface.
+// A group's client should implement this interface.
+type KlusterRolloutsGetter interface {
+	KlusterRollouts(namespace string) KlusterRolloutInterface
+}
+
+// KlusterRolloutInterface has methods to work with KlusterRollout resources.
+type KlusterRolloutInterface interface {
+	Create(ctx context.Context, klusterRollout *v1alpha1.KlusterRollout, opts v1.CreateOptions) (*v1alpha1.KlusterRollout, error)
+	Update(ctx context.Context, klusterRollout *v1alpha1.KlusterRollout, opts v1.UpdateOptions) (*v1alpha1.KlusterRollout, error)
+	UpdateStatus(ctx context.Context, klusterRollout *v1alpha1.KlusterRollout, opts v1.UpdateOptions) (*v1alpha1.KlusterRollout, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.KlusterRollout, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.KlusterRolloutList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterRollout, err error)
+	KlusterRolloutExpansion
+}
+
+// klusterRollouts implements KlusterRolloutInterface
+type klusterRollouts struct {
+	client rest.Interface
+	ns     string
+}
+
+// newKlusterRollouts returns a KlusterRollouts
+func newKlusterRollouts(c *Inspirit941V1alpha1Client, namespace string) *klusterRollouts {
+	return &klusterRollouts{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the klusterRollout, and returns the corresponding klusterRollout object, and an error if there is any.
+func (c *klusterRollouts) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.KlusterRollout, err error) {
+	result = &v1alpha1.KlusterRollout{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("klusterrollouts").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of KlusterRollouts that match those selectors.
+func (c *klusterRollouts) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.KlusterRolloutList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.KlusterRolloutList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("klusterrollouts").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested klusterRollouts.
+func (c *klusterRollouts) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("klusterrollouts").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a klusterRollout and creates it.  Returns the server's representation of the klusterRollout, and an error, if there is any.
+func (c *klusterRollouts) Create(ctx context.Context, klusterRollout *v1alpha1.KlusterRollout, opts v1.CreateOptions) (result *v1alpha1.KlusterRollout, err error) {
+	result = &v1alpha1.KlusterRollout{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("klusterrollouts").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterRollout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a klusterRollout and updates it. Returns the server's representation of the klusterRollout, and an error, if there is any.
+func (c *klusterRollouts) Update(ctx context.Context, klusterRollout *v1alpha1.KlusterRollout, opts v1.UpdateOptions) (result *v1alpha1.KlusterRollout, err error) {
+	result = &v1alpha1.KlusterRollout{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("klusterrollouts").
+		Name(klusterRollout.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterRollout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *klusterRollouts) UpdateStatus(ctx context.Context, klusterRollout *v1alpha1.KlusterRollout, opts v1.UpdateOptions) (result *v1alpha1.KlusterRollout, err error) {
+	result = &v1alpha1.KlusterRollout{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("klusterrollouts").
+		Name(klusterRollout.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterRollout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the klusterRollout and deletes it. Returns an error if one occurs.
+func (c *klusterRollouts) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("klusterrollouts").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *klusterRollouts) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("klusterrollouts").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched klusterRollout.
+func (c *klusterRollouts) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterRollout, err error) {
+	result = &v1alpha1.KlusterRollout{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("klusterrollouts").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	v1beta1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1beta1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -55,6 +56,24 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=inspirit941.dev, Version=v1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("klusters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Inspirit941().V1alpha1().Klusters().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("klusterclaims"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Inspirit941().V1alpha1().KlusterClaims().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("klusterpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Inspirit941().V1alpha1().KlusterPolicies().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("klusterpools"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Inspirit941().V1alpha1().KlusterPools().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("klusterquotas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Inspirit941().V1alpha1().KlusterQuotas().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("klusterrollouts"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Inspirit941().V1alpha1().KlusterRollouts().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("klustersets"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Inspirit941().V1alpha1().KlusterSets().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("klustertemplates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Inspirit941().V1alpha1().KlusterTemplates().Informer()}, nil
+
+		// Group=inspirit941.dev, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("klusters"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Inspirit941().V1beta1().Klusters().Informer()}, nil
 
 	}
 
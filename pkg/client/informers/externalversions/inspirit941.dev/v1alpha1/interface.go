@@ -26,6 +26,20 @@ import (
 type Interface interface {
 	// Klusters returns a KlusterInformer.
 	Klusters() KlusterInformer
+	// KlusterClaims returns a KlusterClaimInformer.
+	KlusterClaims() KlusterClaimInformer
+	// KlusterPolicies returns a KlusterPolicyInformer.
+	KlusterPolicies() KlusterPolicyInformer
+	// KlusterPools returns a KlusterPoolInformer.
+	KlusterPools() KlusterPoolInformer
+	// KlusterQuotas returns a KlusterQuotaInformer.
+	KlusterQuotas() KlusterQuotaInformer
+	// KlusterRollouts returns a KlusterRolloutInformer.
+	KlusterRollouts() KlusterRolloutInformer
+	// KlusterSets returns a KlusterSetInformer.
+	KlusterSets() KlusterSetInformer
+	// KlusterTemplates returns a KlusterTemplateInformer.
+	KlusterTemplates() KlusterTemplateInformer
 }
 
 type version struct {
@@ -43,3 +57,38 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) Klusters() KlusterInformer {
 	return &klusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// KlusterClaims returns a KlusterClaimInformer.
+func (v *version) KlusterClaims() KlusterClaimInformer {
+	return &klusterClaimInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// KlusterPolicies returns a KlusterPolicyInformer.
+func (v *version) KlusterPolicies() KlusterPolicyInformer {
+	return &klusterPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// KlusterPools returns a KlusterPoolInformer.
+func (v *version) KlusterPools() KlusterPoolInformer {
+	return &klusterPoolInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// KlusterQuotas returns a KlusterQuotaInformer.
+func (v *version) KlusterQuotas() KlusterQuotaInformer {
+	return &klusterQuotaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// KlusterRollouts returns a KlusterRolloutInformer.
+func (v *version) KlusterRollouts() KlusterRolloutInformer {
+	return &klusterRolloutInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// KlusterSets returns a KlusterSetInformer.
+func (v *version) KlusterSets() KlusterSetInformer {
+	return &klusterSetInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// KlusterTemplates returns a KlusterTemplateInformer.
+func (v *version) KlusterTemplates() KlusterTemplateInformer {
+	return &klusterTemplateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	time "time"
+
+	inspirit941devv1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	versioned "github.com/inspirit941/kluster/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/inspirit941/kluster/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "github.com/inspirit941/kluster/pkg/client/listers/inspirit941.dev/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// KlusterPolicyInformer provides access to a shared informer and lister for
+// KlusterPolicies.
+type KlusterPolicyInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.KlusterPolicyLister
+}
+
+type klusterPolicyInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewKlusterPolicyInformer constructs a new informer for KlusterPolicy type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewKlusterPolicyInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredKlusterPolicyInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredKlusterPolicyInformer constructs a new informer for KlusterPolicy type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredKlusterPolicyInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.Inspirit941V1alpha1().KlusterPolicies(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.Inspirit941V1alpha1().KlusterPolicies(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&inspirit941devv1alpha1.KlusterPolicy{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *klusterPolicyInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredKlusterPolicyInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *klusterPolicyInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&inspirit941devv1alpha1.KlusterPolicy{}, f.defaultInformer)
+}
+
+func (f *klusterPolicyInformer) Lister() v1alpha1.KlusterPolicyLister {
+	return v1alpha1.NewKlusterPolicyLister(f.Informer().GetIndexer())
+}
@@ -25,3 +25,59 @@ type KlusterListerExpansion interface{}
 // KlusterNamespaceListerExpansion allows custom methods to be added to
 // KlusterNamespaceLister.
 type KlusterNamespaceListerExpansion interface{}
+
+// KlusterClaimListerExpansion allows custom methods to be added to
+// KlusterClaimLister.
+type KlusterClaimListerExpansion interface{}
+
+// KlusterClaimNamespaceListerExpansion allows custom methods to be added to
+// KlusterClaimNamespaceLister.
+type KlusterClaimNamespaceListerExpansion interface{}
+
+// KlusterPolicyListerExpansion allows custom methods to be added to
+// KlusterPolicyLister.
+type KlusterPolicyListerExpansion interface{}
+
+// KlusterPolicyNamespaceListerExpansion allows custom methods to be added to
+// KlusterPolicyNamespaceLister.
+type KlusterPolicyNamespaceListerExpansion interface{}
+
+// KlusterPoolListerExpansion allows custom methods to be added to
+// KlusterPoolLister.
+type KlusterPoolListerExpansion interface{}
+
+// KlusterPoolNamespaceListerExpansion allows custom methods to be added to
+// KlusterPoolNamespaceLister.
+type KlusterPoolNamespaceListerExpansion interface{}
+
+// KlusterQuotaListerExpansion allows custom methods to be added to
+// KlusterQuotaLister.
+type KlusterQuotaListerExpansion interface{}
+
+// KlusterQuotaNamespaceListerExpansion allows custom methods to be added to
+// KlusterQuotaNamespaceLister.
+type KlusterQuotaNamespaceListerExpansion interface{}
+
+// KlusterRolloutListerExpansion allows custom methods to be added to
+// KlusterRolloutLister.
+type KlusterRolloutListerExpansion interface{}
+
+// KlusterRolloutNamespaceListerExpansion allows custom methods to be added to
+// KlusterRolloutNamespaceLister.
+type KlusterRolloutNamespaceListerExpansion interface{}
+
+// KlusterSetListerExpansion allows custom methods to be added to
+// KlusterSetLister.
+type KlusterSetListerExpansion interface{}
+
+// KlusterSetNamespaceListerExpansion allows custom methods to be added to
+// KlusterSetNamespaceLister.
+type KlusterSetNamespaceListerExpansion interface{}
+
+// KlusterTemplateListerExpansion allows custom methods to be added to
+// KlusterTemplateLister.
+type KlusterTemplateListerExpansion interface{}
+
+// KlusterTemplateNamespaceListerExpansion allows custom methods to be added to
+// KlusterTemplateNamespaceLister.
+type KlusterTemplateNamespaceListerExpansion interface{}
@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// KlusterClaimLister helps list KlusterClaims.
+// All objects returned here must be treated as read-only.
+type KlusterClaimLister interface {
+	// List lists all KlusterClaims in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterClaim, err error)
+	// KlusterClaims returns an object that can list and get KlusterClaims.
+	KlusterClaims(namespace string) KlusterClaimNamespaceLister
+	KlusterClaimListerExpansion
+}
+
+// klusterClaimLister implements the KlusterClaimLister interface.
+type klusterClaimLister struct {
+	indexer cache.Indexer
+}
+
+// NewKlusterClaimLister returns a new KlusterClaimLister.
+func NewKlusterClaimLister(indexer cache.Indexer) KlusterClaimLister {
+	return &klusterClaimLister{indexer: indexer}
+}
+
+// List lists all KlusterClaims in the indexer.
+func (s *klusterClaimLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterClaim, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterClaim))
+	})
+	return ret, err
+}
+
+// KlusterClaims returns an object that can list and get KlusterClaims.
+func (s *klusterClaimLister) KlusterClaims(namespace string) KlusterClaimNamespaceLister {
+	return klusterClaimNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// KlusterClaimNamespaceLister helps list and get KlusterClaims.
+// All objects returned here must be treated as read-only.
+type KlusterClaimNamespaceLister interface {
+	// List lists all KlusterClaims in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterClaim, err error)
+	// Get retrieves the KlusterClaim from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.KlusterClaim, error)
+	KlusterClaimNamespaceListerExpansion
+}
+
+// klusterClaimNamespaceLister implements the KlusterClaimNamespaceLister
+// interface.
+type klusterClaimNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all KlusterClaims in the indexer for a given namespace.
+func (s klusterClaimNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterClaim, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterClaim))
+	})
+	return ret, err
+}
+
+// Get retrieves the KlusterClaim from the indexer for a given namespace and name.
+func (s klusterClaimNamespaceLister) Get(name string) (*v1alpha1.KlusterClaim, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("klusterclaim"), name)
+	}
+	return obj.(*v1alpha1.KlusterClaim), nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// KlusterPolicyLister helps list KlusterPolicies.
+// All objects returned here must be treated as read-only.
+type KlusterPolicyLister interface {
+	// List lists all KlusterPolicies in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterPolicy, err error)
+	// KlusterPolicies returns an object that can list and get KlusterPolicies.
+	KlusterPolicies(namespace string) KlusterPolicyNamespaceLister
+	KlusterPolicyListerExpansion
+}
+
+// klusterPolicyLister implements the KlusterPolicyLister interface.
+type klusterPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewKlusterPolicyLister returns a new KlusterPolicyLister.
+func NewKlusterPolicyLister(indexer cache.Indexer) KlusterPolicyLister {
+	return &klusterPolicyLister{indexer: indexer}
+}
+
+// List lists all KlusterPolicies in the indexer.
+func (s *klusterPolicyLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterPolicy))
+	})
+	return ret, err
+}
+
+// KlusterPolicies returns an object that can list and get KlusterPolicies.
+func (s *klusterPolicyLister) KlusterPolicies(namespace string) KlusterPolicyNamespaceLister {
+	return klusterPolicyNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// KlusterPolicyNamespaceLister helps list and get KlusterPolicies.
+// All objects returned here must be treated as read-only.
+type KlusterPolicyNamespaceLister interface {
+	// List lists all KlusterPolicies in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterPolicy, err error)
+	// Get retrieves the KlusterPolicy from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.KlusterPolicy, error)
+	KlusterPolicyNamespaceListerExpansion
+}
+
+// klusterPolicyNamespaceLister implements the KlusterPolicyNamespaceLister
+// interface.
+type klusterPolicyNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all KlusterPolicies in the indexer for a given namespace.
+func (s klusterPolicyNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterPolicy, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the KlusterPolicy from the indexer for a given namespace and name.
+func (s klusterPolicyNamespaceLister) Get(name string) (*v1alpha1.KlusterPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("klusterpolicy"), name)
+	}
+	return obj.(*v1alpha1.KlusterPolicy), nil
+}
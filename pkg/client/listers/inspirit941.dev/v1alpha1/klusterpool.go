@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// KlusterPoolLister helps list KlusterPools.
+// All objects returned here must be treated as read-only.
+type KlusterPoolLister interface {
+	// List lists all KlusterPools in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterPool, err error)
+	// KlusterPools returns an object that can list and get KlusterPools.
+	KlusterPools(namespace string) KlusterPoolNamespaceLister
+	KlusterPoolListerExpansion
+}
+
+// klusterPoolLister implements the KlusterPoolLister interface.
+type klusterPoolLister struct {
+	indexer cache.Indexer
+}
+
+// NewKlusterPoolLister returns a new KlusterPoolLister.
+func NewKlusterPoolLister(indexer cache.Indexer) KlusterPoolLister {
+	return &klusterPoolLister{indexer: indexer}
+}
+
+// List lists all KlusterPools in the indexer.
+func (s *klusterPoolLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterPool, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterPool))
+	})
+	return ret, err
+}
+
+// KlusterPools returns an object that can list and get KlusterPools.
+func (s *klusterPoolLister) KlusterPools(namespace string) KlusterPoolNamespaceLister {
+	return klusterPoolNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// KlusterPoolNamespaceLister helps list and get KlusterPools.
+// All objects returned here must be treated as read-only.
+type KlusterPoolNamespaceLister interface {
+	// List lists all KlusterPools in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterPool, err error)
+	// Get retrieves the KlusterPool from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.KlusterPool, error)
+	KlusterPoolNamespaceListerExpansion
+}
+
+// klusterPoolNamespaceLister implements the KlusterPoolNamespaceLister
+// interface.
+type klusterPoolNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all KlusterPools in the indexer for a given namespace.
+func (s klusterPoolNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterPool, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterPool))
+	})
+	return ret, err
+}
+
+// Get retrieves the KlusterPool from the indexer for a given namespace and name.
+func (s klusterPoolNamespaceLister) Get(name string) (*v1alpha1.KlusterPool, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("klusterpool"), name)
+	}
+	return obj.(*v1alpha1.KlusterPool), nil
+}
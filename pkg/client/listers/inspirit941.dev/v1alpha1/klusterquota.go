@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// KlusterQuotaLister helps list KlusterQuotas.
+// All objects returned here must be treated as read-only.
+type KlusterQuotaLister interface {
+	// List lists all KlusterQuotas in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterQuota, err error)
+	// KlusterQuotas returns an object that can list and get KlusterQuotas.
+	KlusterQuotas(namespace string) KlusterQuotaNamespaceLister
+	KlusterQuotaListerExpansion
+}
+
+// klusterQuotaLister implements the KlusterQuotaLister interface.
+type klusterQuotaLister struct {
+	indexer cache.Indexer
+}
+
+// NewKlusterQuotaLister returns a new KlusterQuotaLister.
+func NewKlusterQuotaLister(indexer cache.Indexer) KlusterQuotaLister {
+	return &klusterQuotaLister{indexer: indexer}
+}
+
+// List lists all KlusterQuotas in the indexer.
+func (s *klusterQuotaLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterQuota, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterQuota))
+	})
+	return ret, err
+}
+
+// KlusterQuotas returns an object that can list and get KlusterQuotas.
+func (s *klusterQuotaLister) KlusterQuotas(namespace string) KlusterQuotaNamespaceLister {
+	return klusterQuotaNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// KlusterQuotaNamespaceLister helps list and get KlusterQuotas.
+// All objects returned here must be treated as read-only.
+type KlusterQuotaNamespaceLister interface {
+	// List lists all KlusterQuotas in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterQuota, err error)
+	// Get retrieves the KlusterQuota from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.KlusterQuota, error)
+	KlusterQuotaNamespaceListerExpansion
+}
+
+// klusterQuotaNamespaceLister implements the KlusterQuotaNamespaceLister
+// interface.
+type klusterQuotaNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all KlusterQuotas in the indexer for a given namespace.
+func (s klusterQuotaNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterQuota, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterQuota))
+	})
+	return ret, err
+}
+
+// Get retrieves the KlusterQuota from the indexer for a given namespace and name.
+func (s klusterQuotaNamespaceLister) Get(name string) (*v1alpha1.KlusterQuota, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("klusterquota"), name)
+	}
+	return obj.(*v1alpha1.KlusterQuota), nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// KlusterRolloutLister helps list KlusterRollouts.
+// All objects returned here must be treated as read-only.
+type KlusterRolloutLister interface {
+	// List lists all KlusterRollouts in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterRollout, err error)
+	// KlusterRollouts returns an object that can list and get KlusterRollouts.
+	KlusterRollouts(namespace string) KlusterRolloutNamespaceLister
+	KlusterRolloutListerExpansion
+}
+
+// klusterRolloutLister implements the KlusterRolloutLister interface.
+type klusterRolloutLister struct {
+	indexer cache.Indexer
+}
+
+// NewKlusterRolloutLister returns a new KlusterRolloutLister.
+func NewKlusterRolloutLister(indexer cache.Indexer) KlusterRolloutLister {
+	return &klusterRolloutLister{indexer: indexer}
+}
+
+// List lists all KlusterRollouts in the indexer.
+func (s *klusterRolloutLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterRollout, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterRollout))
+	})
+	return ret, err
+}
+
+// KlusterRollouts returns an object that can list and get KlusterRollouts.
+func (s *klusterRolloutLister) KlusterRollouts(namespace string) KlusterRolloutNamespaceLister {
+	return klusterRolloutNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// KlusterRolloutNamespaceLister helps list and get KlusterRollouts.
+// All objects returned here must be treated as read-only.
+type KlusterRolloutNamespaceLister interface {
+	// List lists all KlusterRollouts in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterRollout, err error)
+	// Get retrieves the KlusterRollout from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.KlusterRollout, error)
+	KlusterRolloutNamespaceListerExpansion
+}
+
+// klusterRolloutNamespaceLister implements the KlusterRolloutNamespaceLister
+// interface.
+type klusterRolloutNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all KlusterRollouts in the indexer for a given namespace.
+func (s klusterRolloutNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterRollout, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterRollout))
+	})
+	return ret, err
+}
+
+// Get retrieves the KlusterRollout from the indexer for a given namespace and name.
+func (s klusterRolloutNamespaceLister) Get(name string) (*v1alpha1.KlusterRollout, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("klusterrollout"), name)
+	}
+	return obj.(*v1alpha1.KlusterRollout), nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// KlusterSetLister helps list KlusterSets.
+// All objects returned here must be treated as read-only.
+type KlusterSetLister interface {
+	// List lists all KlusterSets in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterSet, err error)
+	// KlusterSets returns an object that can list and get KlusterSets.
+	KlusterSets(namespace string) KlusterSetNamespaceLister
+	KlusterSetListerExpansion
+}
+
+// klusterSetLister implements the KlusterSetLister interface.
+type klusterSetLister struct {
+	indexer cache.Indexer
+}
+
+// NewKlusterSetLister returns a new KlusterSetLister.
+func NewKlusterSetLister(indexer cache.Indexer) KlusterSetLister {
+	return &klusterSetLister{indexer: indexer}
+}
+
+// List lists all KlusterSets in the indexer.
+func (s *klusterSetLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterSet, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterSet))
+	})
+	return ret, err
+}
+
+// KlusterSets returns an object that can list and get KlusterSets.
+func (s *klusterSetLister) KlusterSets(namespace string) KlusterSetNamespaceLister {
+	return klusterSetNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// KlusterSetNamespaceLister helps list and get KlusterSets.
+// All objects returned here must be treated as read-only.
+type KlusterSetNamespaceLister interface {
+	// List lists all KlusterSets in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterSet, err error)
+	// Get retrieves the KlusterSet from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.KlusterSet, error)
+	KlusterSetNamespaceListerExpansion
+}
+
+// klusterSetNamespaceLister implements the KlusterSetNamespaceLister
+// interface.
+type klusterSetNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all KlusterSets in the indexer for a given namespace.
+func (s klusterSetNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterSet, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterSet))
+	})
+	return ret, err
+}
+
+// Get retrieves the KlusterSet from the indexer for a given namespace and name.
+func (s klusterSetNamespaceLister) Get(name string) (*v1alpha1.KlusterSet, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("klusterset"), name)
+	}
+	return obj.(*v1alpha1.KlusterSet), nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// KlusterTemplateLister helps list KlusterTemplates.
+// All objects returned here must be treated as read-only.
+type KlusterTemplateLister interface {
+	// List lists all KlusterTemplates in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterTemplate, err error)
+	// KlusterTemplates returns an object that can list and get KlusterTemplates.
+	KlusterTemplates(namespace string) KlusterTemplateNamespaceLister
+	KlusterTemplateListerExpansion
+}
+
+// klusterTemplateLister implements the KlusterTemplateLister interface.
+type klusterTemplateLister struct {
+	indexer cache.Indexer
+}
+
+// NewKlusterTemplateLister returns a new KlusterTemplateLister.
+func NewKlusterTemplateLister(indexer cache.Indexer) KlusterTemplateLister {
+	return &klusterTemplateLister{indexer: indexer}
+}
+
+// List lists all KlusterTemplates in the indexer.
+func (s *klusterTemplateLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterTemplate, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterTemplate))
+	})
+	return ret, err
+}
+
+// KlusterTemplates returns an object that can list and get KlusterTemplates.
+func (s *klusterTemplateLister) KlusterTemplates(namespace string) KlusterTemplateNamespaceLister {
+	return klusterTemplateNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// KlusterTemplateNamespaceLister helps list and get KlusterTemplates.
+// All objects returned here must be treated as read-only.
+type KlusterTemplateNamespaceLister interface {
+	// List lists all KlusterTemplates in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterTemplate, err error)
+	// Get retrieves the KlusterTemplate from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.KlusterTemplate, error)
+	KlusterTemplateNamespaceListerExpansion
+}
+
+// klusterTemplateNamespaceLister implements the KlusterTemplateNamespaceLister
+// interface.
+type klusterTemplateNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all KlusterTemplates in the indexer for a given namespace.
+func (s klusterTemplateNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterTemplate, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterTemplate))
+	})
+	return ret, err
+}
+
+// Get retrieves the KlusterTemplate from the indexer for a given namespace and name.
+func (s klusterTemplateNamespaceLister) Get(name string) (*v1alpha1.KlusterTemplate, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("klustertemplate"), name)
+	}
+	return obj.(*v1alpha1.KlusterTemplate), nil
+}
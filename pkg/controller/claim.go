@@ -0,0 +1,345 @@
+package controller
+
+import (
+	"context"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"sort"
+)
+
+// kubeconfig secret에서 kubeconfig가 들어가는 key
+const kubeconfigKey = "kubeconfig"
+
+var klusterClaimKind = v1alpha1.SchemeGroupVersion.WithKind("KlusterClaim")
+
+func (c *Controller) claimWorker() {
+	for c.processNextQueueItem(c.claimWq, "klusterClaim", c.syncClaim) {
+	}
+}
+
+// syncClaim: pool의 ready Kluster 하나를 claim에 바인딩하고 kubeconfig secret을 만든다.
+// spec.released가 설정되거나 claim이 삭제되면 releasePolicy에 따라 Kluster를 지우거나(Delete) pool에서 떼어내서 남겨둔다(Retain).
+func (c *Controller) syncClaim(ctx context.Context, ns, name string) error {
+	logger := klog.FromContext(ctx)
+	claim, err := c.cLister.KlusterClaims(ns).Get(name)
+	if apierrors.IsNotFound(err) {
+		logger.V(4).Info("KlusterClaim was deleted")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	claim = claim.DeepCopy()
+
+	if claim.DeletionTimestamp != nil {
+		if !sets.NewString(claim.Finalizers...).Has(v1alpha1.ClaimFinalizer) {
+			return nil
+		}
+		if err := c.releaseClaim(ctx, claim); err != nil {
+			return err
+		}
+		return c.updateClaimFinalizers(ctx, claim, func(finalizers sets.String) {
+			finalizers.Delete(v1alpha1.ClaimFinalizer)
+		})
+	}
+	if claim.Status.Phase == v1alpha1.ClaimPhaseReleased {
+		return nil
+	}
+	if claim.Spec.Released {
+		if err := c.releaseClaim(ctx, claim); err != nil {
+			return err
+		}
+		return c.mutateClaimStatus(ctx, claim, func(status *v1alpha1.KlusterClaimStatus) {
+			now := metav1.Now()
+			status.Phase = v1alpha1.ClaimPhaseReleased
+			status.KubeconfigSecret = ""
+			status.ReleasedAt = &now
+		})
+	}
+
+	// Kluster를 바인딩하기 전에 finalizer를 붙여서, claim이 삭제될 때 releasePolicy를 적용할 수 있도록 한다.
+	if err := c.updateClaimFinalizers(ctx, claim, func(finalizers sets.String) {
+		finalizers.Insert(v1alpha1.ClaimFinalizer)
+	}); err != nil {
+		return err
+	}
+
+	kluster, err := c.bindKluster(ctx, claim)
+	if err != nil || kluster == nil {
+		return err
+	}
+	logger = klog.LoggerWithValues(logger, "kluster", klog.KObj(kluster))
+	ctx = klog.NewContext(ctx, logger)
+
+	secretName, err := c.syncKubeconfigSecret(ctx, claim, kluster)
+	if err != nil {
+		logger.Error(err, "writing kubeconfig secret")
+		c.recorder.Event(claim, corev1.EventTypeWarning, "KubeconfigFailed", err.Error())
+		return err
+	}
+	wasBound := claim.Status.Phase == v1alpha1.ClaimPhaseBound
+	if err := c.mutateClaimStatus(ctx, claim, func(status *v1alpha1.KlusterClaimStatus) {
+		status.Phase = v1alpha1.ClaimPhaseBound
+		status.KlusterName = kluster.Name
+		status.KubeconfigSecret = secretName
+		if status.BoundAt == nil {
+			now := metav1.Now()
+			status.BoundAt = &now
+		}
+	}); err != nil {
+		return err
+	}
+	if !wasBound {
+		logger.Info("KlusterClaim bound")
+		c.recorder.Event(claim, corev1.EventTypeNormal, "Bound", "Kluster "+kluster.Name+" was bound, kubeconfig is in Secret "+secretName+".")
+	}
+	return nil
+}
+
+// bindKluster: claim에 바인딩된 Kluster를 리턴한다. 아직 없으면 pool의 ready Kluster 중 가장 오래된 것에 claim label을 붙인다.
+// 바인딩할 Kluster가 없으면 Pending으로 기록하고 nil을 리턴한다. pool의 Kluster가 ready가 되면 다시 처리됨.
+func (c *Controller) bindKluster(ctx context.Context, claim *v1alpha1.KlusterClaim) (*v1alpha1.Kluster, error) {
+	logger := klog.FromContext(ctx)
+	// status 업데이트 전에 실패했더라도 이미 label이 붙은 Kluster를 다시 찾을 수 있도록 label로 조회한다.
+	bound, err := c.kLister.Klusters(claim.Namespace).List(labels.SelectorFromSet(labels.Set{
+		v1alpha1.PoolLabel:  claim.Spec.PoolName,
+		v1alpha1.ClaimLabel: claim.Name,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if len(bound) > 0 {
+		return bound[0], nil
+	}
+	if claim.Status.Phase == v1alpha1.ClaimPhaseBound {
+		// 바인딩된 Kluster가 직접 삭제된 경우. 다른 Kluster를 바인딩하지 않고 claim을 놓아주도록 알린다.
+		logger.Info("bound Kluster no longer exists", "kluster", claim.Status.KlusterName)
+		c.recorder.Event(claim, corev1.EventTypeWarning, "KlusterLost", "Bound Kluster "+claim.Status.KlusterName+" no longer exists, release or delete the claim.")
+		return nil, nil
+	}
+
+	pool, err := c.pLister.KlusterPools(claim.Namespace).Get(claim.Spec.PoolName)
+	if apierrors.IsNotFound(err) {
+		logger.Info("KlusterPool not found", "pool", claim.Spec.PoolName)
+		c.recorder.Event(claim, corev1.EventTypeWarning, "PoolNotFound", "KlusterPool "+claim.Spec.PoolName+" was not found.")
+		return nil, c.setClaimPending(ctx, claim)
+	}
+	if err != nil {
+		return nil, err
+	}
+	klusters, err := c.kLister.Klusters(claim.Namespace).List(labels.SelectorFromSet(labels.Set{v1alpha1.PoolLabel: claim.Spec.PoolName}))
+	if err != nil {
+		return nil, err
+	}
+	var ready []*v1alpha1.Kluster
+	for _, kluster := range klusters {
+		// pool의 spec이 바뀐 뒤 아직 교체되지 않은 Kluster는 바인딩하지 않는다.
+		if isReady(kluster) && kluster.Labels[v1alpha1.ClaimLabel] == "" && poolKlusterUpToDate(pool, kluster) {
+			ready = append(ready, kluster)
+		}
+	}
+	if len(ready) == 0 {
+		logger.V(2).Info("no ready Kluster in the pool, waiting", "pool", claim.Spec.PoolName)
+		return nil, c.setClaimPending(ctx, claim)
+	}
+	sort.SliceStable(ready, func(i, j int) bool {
+		return ready[i].CreationTimestamp.Before(&ready[j].CreationTimestamp)
+	})
+
+	// pool이 삭제되어도 claim된 Kluster는 남도록 pool의 ownerReference를 지운다.
+	// lister의 resourceVersion으로 update하므로, 다른 claim이 먼저 바인딩했으면 conflict 에러로 다시 처리된다.
+	kluster := ready[0].DeepCopy()
+	kluster.Labels[v1alpha1.ClaimLabel] = claim.Name
+	kluster.OwnerReferences = withoutOwner(kluster.OwnerReferences, klusterPoolKind.Kind)
+	kluster, err = c.klient.Inspirit941V1alpha1().Klusters(kluster.Namespace).Update(ctx, kluster, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("bound Kluster to the claim", "kluster", klog.KObj(kluster))
+	return kluster, nil
+}
+
+func (c *Controller) setClaimPending(ctx context.Context, claim *v1alpha1.KlusterClaim) error {
+	return c.mutateClaimStatus(ctx, claim, func(status *v1alpha1.KlusterClaimStatus) {
+		status.Phase = v1alpha1.ClaimPhasePending
+	})
+}
+
+// syncKubeconfigSecret: 바인딩된 클러스터의 kubeconfig를 claim namespace의 secret에 기록하고 secret 이름을 리턴한다.
+// secret은 claim을 owner로 가지므로 claim이 삭제되면 같이 지워진다.
+func (c *Controller) syncKubeconfigSecret(ctx context.Context, claim *v1alpha1.KlusterClaim, kluster *v1alpha1.Kluster) (string, error) {
+	// token secret이 template에 있을 수 있으므로 template과 합친 spec으로 호출한다.
+	resolved, _, err := c.resolveSpec(kluster)
+	if err != nil {
+		return "", err
+	}
+	kubeconfig, err := c.do.Kubeconfig(ctx, resolved, kluster.Status.KlusterID)
+	if err != nil {
+		return "", err
+	}
+
+	name := kubeconfigSecretName(claim)
+	secrets := c.client.CoreV1().Secrets(claim.Namespace)
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       claim.Namespace,
+				Labels:          map[string]string{v1alpha1.ClaimLabel: claim.Name},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(claim, klusterClaimKind)},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{kubeconfigKey: kubeconfig},
+		}, metav1.CreateOptions{})
+		return name, err
+	}
+	if err != nil {
+		return "", err
+	}
+	// 다른 용도로 이미 있는 secret을 덮어쓰지 않는다.
+	if !metav1.IsControlledBy(secret, claim) {
+		return "", apierrors.NewAlreadyExists(corev1.Resource("secrets"), name)
+	}
+	if equality.Semantic.DeepEqual(secret.Data[kubeconfigKey], kubeconfig) {
+		return name, nil
+	}
+	secret.Data = map[string][]byte{kubeconfigKey: kubeconfig}
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	return name, err
+}
+
+func kubeconfigSecretName(claim *v1alpha1.KlusterClaim) string {
+	if claim.Spec.KubeconfigSecretName != "" {
+		return claim.Spec.KubeconfigSecretName
+	}
+	return claim.Name + "-kubeconfig"
+}
+
+// releaseClaim: releasePolicy가 Delete면 바인딩된 Kluster를 지우고(DigitalOcean 클러스터는 Kluster의 deletionPolicy로 정리됨),
+// Retain이면 pool / claim label을 지워서 pool과 관계없는 Kluster로 남긴다. 그 뒤 kubeconfig secret을 지운다.
+func (c *Controller) releaseClaim(ctx context.Context, claim *v1alpha1.KlusterClaim) error {
+	logger := klog.FromContext(ctx)
+	klusters, err := c.kLister.Klusters(claim.Namespace).List(labels.SelectorFromSet(labels.Set{
+		v1alpha1.PoolLabel:  claim.Spec.PoolName,
+		v1alpha1.ClaimLabel: claim.Name,
+	}))
+	if err != nil {
+		return err
+	}
+	for _, kluster := range klusters {
+		if kluster.DeletionTimestamp != nil {
+			continue
+		}
+		switch claim.Spec.ReleasePolicy {
+		case v1alpha1.ReleasePolicyRetain:
+			k := kluster.DeepCopy()
+			delete(k.Labels, v1alpha1.PoolLabel)
+			delete(k.Labels, v1alpha1.ClaimLabel)
+			if _, err := c.klient.Inspirit941V1alpha1().Klusters(k.Namespace).Update(ctx, k, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+			logger.Info("released Kluster from the claim", "kluster", klog.KObj(kluster))
+			c.recorder.Event(claim, corev1.EventTypeNormal, "Released", "Kluster "+kluster.Name+" was released and is no longer part of KlusterPool "+claim.Spec.PoolName+".")
+		default:
+			// dry-run에서 Kluster를 지우면 되돌릴 수 없으므로 삭제하지 않는다.
+			if c.opts.DryRun {
+				logger.Info("dry-run: released Kluster was not deleted", "kluster", klog.KObj(kluster))
+				c.recorder.Event(claim, corev1.EventTypeNormal, "DryRun", "Would delete released Kluster "+kluster.Name+".")
+				continue
+			}
+			err := c.klient.Inspirit941V1alpha1().Klusters(kluster.Namespace).Delete(ctx, kluster.Name, metav1.DeleteOptions{
+				Preconditions: &metav1.Preconditions{UID: &kluster.UID},
+			})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			logger.Info("deleted released Kluster", "kluster", klog.KObj(kluster))
+			c.recorder.Event(claim, corev1.EventTypeNormal, "Released", "Kluster "+kluster.Name+" was deleted on release.")
+		}
+	}
+
+	err = c.client.CoreV1().Secrets(claim.Namespace).Delete(ctx, kubeconfigSecretName(claim), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func withoutOwner(refs []metav1.OwnerReference, kind string) []metav1.OwnerReference {
+	var out []metav1.OwnerReference
+	for _, ref := range refs {
+		if ref.APIVersion == v1alpha1.SchemeGroupVersion.String() && ref.Kind == kind {
+			continue
+		}
+		out = append(out, ref)
+	}
+	return out
+}
+
+// 최신 KlusterClaim을 조회해서 finalizer 목록을 바꾼 뒤, 바뀐 내용이 있을 때만 업데이트한다.
+func (c *Controller) updateClaimFinalizers(ctx context.Context, claim *v1alpha1.KlusterClaim, mutate func(finalizers sets.String)) error {
+	cl, err := c.klient.Inspirit941V1alpha1().KlusterClaims(claim.Namespace).Get(ctx, claim.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	finalizers := sets.NewString(cl.Finalizers...)
+	mutate(finalizers)
+	if finalizers.Equal(sets.NewString(cl.Finalizers...)) {
+		return nil
+	}
+	cl.Finalizers = finalizers.List()
+	_, err = c.klient.Inspirit941V1alpha1().KlusterClaims(cl.Namespace).Update(ctx, cl, metav1.UpdateOptions{})
+	return err
+}
+
+// 최신 KlusterClaim을 조회해서 mutate를 적용한 뒤, 바뀐 내용이 있을 때만 status subresource를 업데이트한다.
+func (c *Controller) mutateClaimStatus(ctx context.Context, claim *v1alpha1.KlusterClaim, mutate func(status *v1alpha1.KlusterClaimStatus)) error {
+	cl, err := c.klient.Inspirit941V1alpha1().KlusterClaims(claim.Namespace).Get(ctx, claim.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	status := cl.Status.DeepCopy()
+	mutate(&cl.Status)
+	if equality.Semantic.DeepEqual(status, &cl.Status) {
+		return nil
+	}
+	_, err = c.klient.Inspirit941V1alpha1().KlusterClaims(cl.Namespace).UpdateStatus(ctx, cl, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *Controller) handleClaim(obj interface{}) {
+	c.enqueueClaim(obj)
+}
+
+// status 업데이트는 generation이 바뀌지 않으므로 무시하고, 삭제가 시작된 경우는 release를 위해 처리한다.
+func (c *Controller) handleClaimUpdate(oldObj, newObj interface{}) {
+	old, ok := oldObj.(*v1alpha1.KlusterClaim)
+	if !ok {
+		return
+	}
+	claim, ok := newObj.(*v1alpha1.KlusterClaim)
+	if !ok {
+		return
+	}
+	if old.Generation == claim.Generation && old.ResourceVersion != claim.ResourceVersion && claim.DeletionTimestamp == nil {
+		return
+	}
+	c.enqueueClaim(claim)
+}
+
+func (c *Controller) enqueueClaim(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.ErrorS(err, "getting key of KlusterClaim")
+		return
+	}
+	c.claimWq.Add(key)
+}
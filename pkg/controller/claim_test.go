@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"context"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+	"time"
+)
+
+func newClaim(name string) *v1alpha1.KlusterClaim {
+	return &v1alpha1.KlusterClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name)},
+		Spec:       v1alpha1.KlusterClaimSpec{PoolName: "preview"},
+	}
+}
+
+// boundClaim: preview-0이 바인딩된 claim
+func boundClaim(name string) *v1alpha1.KlusterClaim {
+	claim := newClaim(name)
+	claim.Finalizers = []string{v1alpha1.ClaimFinalizer}
+	claim.Status = v1alpha1.KlusterClaimStatus{Phase: v1alpha1.ClaimPhaseBound, KlusterName: "preview-0", KubeconfigSecret: name + "-kubeconfig"}
+	return claim
+}
+
+func getClaim(t *testing.T, c *Controller, name string) *v1alpha1.KlusterClaim {
+	t.Helper()
+	claim, err := c.klient.Inspirit941V1alpha1().KlusterClaims("default").Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return claim
+}
+
+func kubeconfigSecret(claim *v1alpha1.KlusterClaim) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            claim.Name + "-kubeconfig",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(claim, klusterClaimKind)},
+		},
+		Data: map[string][]byte{kubeconfigKey: []byte("old")},
+	}
+}
+
+func TestSyncClaimBinds(t *testing.T) {
+	pool := newPool(2)
+	claim := newClaim("claim")
+	// 가장 오래된 ready Kluster를 바인딩한다.
+	c, provider, recorder := newTestController(t, Options{}, pool, claim,
+		poolKluster(pool, "preview-0", time.Hour, true),
+		poolKluster(pool, "preview-1", 2*time.Hour, true),
+		poolKluster(pool, "preview-2", 3*time.Hour, false),
+	)
+	provider.kubeconfig = []byte("apiVersion: v1\nkind: Config\n")
+
+	if err := c.syncClaim(context.Background(), "default", "claim"); err != nil {
+		t.Fatal(err)
+	}
+	kluster := getKluster(t, c, "preview-1")
+	// pool이 지워져도 남도록 pool의 ownerReference를 지운다.
+	if kluster.Labels[v1alpha1.ClaimLabel] != "claim" || len(kluster.OwnerReferences) != 0 {
+		t.Errorf("labels = %v, owners = %v, want the claim label and no owner", kluster.Labels, kluster.OwnerReferences)
+	}
+	if !provider.called("Kubeconfig cluster-preview-1") {
+		t.Errorf("calls = %v, want Kubeconfig of the bound cluster", provider.calls)
+	}
+	secret, err := c.client.CoreV1().Secrets("default").Get(context.Background(), "claim-kubeconfig", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data[kubeconfigKey]) != string(provider.kubeconfig) || !metav1.IsControlledBy(secret, claim) {
+		t.Errorf("secret = %+v, want the kubeconfig owned by the claim", secret)
+	}
+	got := getClaim(t, c, "claim")
+	if got.Status.Phase != v1alpha1.ClaimPhaseBound || got.Status.KlusterName != "preview-1" || got.Status.KubeconfigSecret != "claim-kubeconfig" || got.Status.BoundAt == nil {
+		t.Errorf("status = %+v", got.Status)
+	}
+	if !equalStrings(got.Finalizers, []string{v1alpha1.ClaimFinalizer}) {
+		t.Errorf("finalizers = %v", got.Finalizers)
+	}
+	if !hasEvent(recorder, "Bound") {
+		t.Error("no Bound event")
+	}
+}
+
+func TestSyncClaimPending(t *testing.T) {
+	pool := newPool(1)
+	changed := newPool(1)
+	changed.Spec.KlusterSpec.Region = "ams3"
+	tests := []struct {
+		name      string
+		objects   []runtime.Object
+		wantEvent string
+	}{
+		{name: "pool not found", wantEvent: "PoolNotFound"},
+		{name: "no ready Kluster", objects: []runtime.Object{pool, poolKluster(pool, "preview-0", time.Hour, false)}},
+		{name: "ready Kluster claimed", objects: []runtime.Object{pool, claimed(poolKluster(pool, "preview-0", time.Hour, true), "other")}},
+		// pool의 spec이 바뀐 뒤 아직 교체되지 않은 Kluster는 바인딩하지 않는다.
+		{name: "ready Kluster outdated", objects: []runtime.Object{changed, poolKluster(pool, "preview-0", time.Hour, true)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, recorder := newTestController(t, Options{}, append(tt.objects, newClaim("claim"))...)
+
+			if err := c.syncClaim(context.Background(), "default", "claim"); err != nil {
+				t.Fatal(err)
+			}
+			if phase := getClaim(t, c, "claim").Status.Phase; phase != v1alpha1.ClaimPhasePending {
+				t.Errorf("phase = %q, want Pending", phase)
+			}
+			if tt.wantEvent != "" && !hasEvent(recorder, tt.wantEvent) {
+				t.Errorf("no %s event", tt.wantEvent)
+			}
+		})
+	}
+}
+
+// 이미 있는, claim이 만들지 않은 secret은 덮어쓰지 않는다.
+func TestSyncClaimKeepsForeignSecret(t *testing.T) {
+	pool := newPool(1)
+	secret := kubeconfigSecret(newClaim("claim"))
+	secret.OwnerReferences = nil
+	c, _, recorder := newTestController(t, Options{}, pool, newClaim("claim"), poolKluster(pool, "preview-0", time.Hour, true), secret)
+
+	if err := c.syncClaim(context.Background(), "default", "claim"); !apierrors.IsAlreadyExists(err) {
+		t.Fatalf("syncClaim() = %v, want AlreadyExists", err)
+	}
+	got, err := c.client.CoreV1().Secrets("default").Get(context.Background(), secret.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got.Data[kubeconfigKey]) != "old" {
+		t.Errorf("secret was overwritten: %q", got.Data[kubeconfigKey])
+	}
+	if !hasEvent(recorder, "KubeconfigFailed") {
+		t.Error("no KubeconfigFailed event")
+	}
+}
+
+func TestSyncClaimReleases(t *testing.T) {
+	pool := newPool(1)
+	tests := []struct {
+		name          string
+		releasePolicy v1alpha1.ReleasePolicy
+		deleting      bool
+		opts          Options
+		wantDeleted   bool
+	}{
+		{name: "delete", releasePolicy: v1alpha1.ReleasePolicyDelete, wantDeleted: true},
+		{name: "retain", releasePolicy: v1alpha1.ReleasePolicyRetain},
+		// dry-run에서는 Kluster를 지우지 않는다.
+		{name: "delete in dry-run", releasePolicy: v1alpha1.ReleasePolicyDelete, opts: Options{DryRun: true}},
+		{name: "claim deleted", releasePolicy: v1alpha1.ReleasePolicyDelete, deleting: true, wantDeleted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claim := boundClaim("claim")
+			claim.Spec.ReleasePolicy = tt.releasePolicy
+			if tt.deleting {
+				now := metav1.Now()
+				claim.DeletionTimestamp = &now
+			} else {
+				claim.Spec.Released = true
+			}
+			c, _, recorder := newTestController(t, tt.opts, pool, claim, kubeconfigSecret(claim),
+				claimed(poolKluster(pool, "preview-0", time.Hour, true), "claim"))
+
+			if err := c.syncClaim(context.Background(), "default", "claim"); err != nil {
+				t.Fatal(err)
+			}
+			kluster, err := c.klient.Inspirit941V1alpha1().Klusters("default").Get(context.Background(), "preview-0", metav1.GetOptions{})
+			if deleted := apierrors.IsNotFound(err); deleted != tt.wantDeleted {
+				t.Fatalf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			// Retain이면 pool과 관계없는 Kluster로 남는다.
+			if tt.releasePolicy == v1alpha1.ReleasePolicyRetain && len(kluster.Labels) != 0 {
+				t.Errorf("labels = %v, want none", kluster.Labels)
+			}
+			if _, err := c.client.CoreV1().Secrets("default").Get(context.Background(), "claim-kubeconfig", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+				t.Errorf("kubeconfig secret was not deleted: %v", err)
+			}
+			got := getClaim(t, c, "claim")
+			if tt.deleting {
+				if len(got.Finalizers) != 0 {
+					t.Errorf("finalizers = %v, want none", got.Finalizers)
+				}
+			} else if got.Status.Phase != v1alpha1.ClaimPhaseReleased || got.Status.KubeconfigSecret != "" || got.Status.ReleasedAt == nil {
+				t.Errorf("status = %+v", got.Status)
+			}
+			wantEvent := "Released"
+			if tt.opts.DryRun {
+				wantEvent = "DryRun"
+			}
+			if !hasEvent(recorder, wantEvent) {
+				t.Errorf("no %s event", wantEvent)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"time"
+)
+
+// syncExpiry: spec.ttl로 만료 시각을 계산해서 status.expiresAt에 기록하고, 경고 / 만료 시각에 다시 처리되도록 key를 예약한다.
+// 만료 시각이 지났으면 true를 리턴한다. ttl이 없으면 expiresAt과 Expiring condition을 지운다.
+func (c *Controller) syncExpiry(ctx context.Context, kluster *v1alpha1.Kluster) (bool, error) {
+	if kluster.Spec.TTL == nil {
+		return false, c.mutateStatus(ctx, kluster, func(status *v1alpha1.KlusterStatus) {
+			status.ExpiresAt = nil
+			meta.RemoveStatusCondition(&status.Conditions, v1alpha1.ConditionExpiring)
+		})
+	}
+	// 0 이하의 ttl은 validation이 InvalidSpec으로 기록한다. 잘못 적은 값으로 바로 삭제하지 않도록 만료로 보지 않는다.
+	if kluster.Spec.TTL.Duration <= 0 {
+		return false, nil
+	}
+
+	// status에는 초 단위로 기록되므로 비교할 때 값이 바뀌지 않도록 맞춘다.
+	expiresAt := metav1.NewTime(kluster.CreationTimestamp.Add(kluster.Spec.TTL.Duration).Truncate(time.Second))
+	remaining := time.Until(expiresAt.Time)
+	expiring := remaining <= c.opts.TTLWarning
+	wasExpiring := meta.IsStatusConditionTrue(kluster.Status.Conditions, v1alpha1.ConditionExpiring)
+
+	condition := metav1.Condition{
+		Type:    v1alpha1.ConditionExpiring,
+		Status:  metav1.ConditionFalse,
+		Reason:  "TTLNotReached",
+		Message: "Kluster expires at " + expiresAt.UTC().Format(time.RFC3339),
+	}
+	if expiring {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "TTLExpiring"
+	}
+	if err := c.mutateStatus(ctx, kluster, func(status *v1alpha1.KlusterStatus) {
+		status.ExpiresAt = &expiresAt
+		condition.ObservedGeneration = kluster.Generation
+		meta.SetStatusCondition(&status.Conditions, condition)
+	}); err != nil {
+		return false, err
+	}
+	kluster.Status.ExpiresAt = &expiresAt
+	if expiring && !wasExpiring && remaining > 0 {
+		klog.FromContext(ctx).Info("Kluster is about to expire", "expiresAt", expiresAt, "remaining", remaining.Round(time.Second))
+		c.recorder.Eventf(kluster, corev1.EventTypeWarning, "ExpiringSoon", "Kluster expires at %s (in %s) and will be deleted.", expiresAt.UTC().Format(time.RFC3339), remaining.Round(time.Second))
+	}
+	if remaining <= 0 {
+		return true, nil
+	}
+
+	// 경고 시각 전이면 경고 시각에, 이후면 만료 시각에 다시 처리한다. resync를 기다리지 않아도 제때 삭제되도록.
+	next := remaining
+	if !expiring {
+		next = remaining - c.opts.TTLWarning
+	}
+	key, err := cache.MetaNamespaceKeyFunc(kluster)
+	if err != nil {
+		return false, err
+	}
+	c.wq.AddAfter(key, next)
+	return false, nil
+}
+
+// expiredWithoutTemplate: template을 찾을 수 없을 때 만료 여부를 확인한다. template의 ttl은 알 수 없으므로
+// Kluster에 ttl이 있으면 그 값으로 계산하고, 없으면 template이 있을 때 기록해둔 status.expiresAt을 사용한다.
+func (c *Controller) expiredWithoutTemplate(ctx context.Context, kluster *v1alpha1.Kluster) (bool, error) {
+	if kluster.Spec.TTL != nil {
+		return c.syncExpiry(ctx, kluster)
+	}
+	if kluster.Status.ExpiresAt == nil {
+		return false, nil
+	}
+	remaining := time.Until(kluster.Status.ExpiresAt.Time)
+	if remaining <= 0 {
+		return true, nil
+	}
+	key, err := cache.MetaNamespaceKeyFunc(kluster)
+	if err != nil {
+		return false, err
+	}
+	c.wq.AddAfter(key, remaining)
+	return false, nil
+}
+
+// expire: 만료된 Kluster를 삭제한다. DigitalOcean 클러스터는 finalizer가 deletionPolicy에 따라 정리한다.
+func (c *Controller) expire(ctx context.Context, kluster *v1alpha1.Kluster) error {
+	logger := klog.FromContext(ctx)
+	message := "Kluster expired at " + kluster.Status.ExpiresAt.UTC().Format(time.RFC3339)
+	if kluster.Spec.TTL != nil {
+		message += " (ttl " + kluster.Spec.TTL.Duration.String() + ")"
+	}
+	// dry-run에서 Kluster를 지우면 되돌릴 수 없으므로 삭제하지 않는다.
+	if c.opts.DryRun {
+		logger.Info("dry-run: expired Kluster was not deleted")
+		c.recorder.Event(kluster, corev1.EventTypeNormal, "DryRun", message+", would delete the Kluster.")
+		return nil
+	}
+	logger.Info("deleting expired Kluster", "expiresAt", kluster.Status.ExpiresAt)
+	// 같은 이름으로 다시 만들어진 Kluster를 지우지 않도록 UID를 precondition으로 건다.
+	err := c.klient.Inspirit941V1alpha1().Klusters(kluster.Namespace).Delete(ctx, kluster.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &kluster.UID},
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		logger.Error(err, "deleting expired Kluster")
+		return err
+	}
+	c.recorder.Event(kluster, corev1.EventTypeWarning, "Expired", message+", the Kluster was deleted.")
+	return nil
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+// expiringKluster: age 전에 만들어졌고 ttl이 지나면 만료되는 Kluster
+func expiringKluster(name string, age, ttl time.Duration) *v1alpha1.Kluster {
+	kluster := newKluster(name)
+	kluster.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+	kluster.Spec.TTL = &metav1.Duration{Duration: ttl}
+	return kluster
+}
+
+func TestSyncExpiry(t *testing.T) {
+	tests := []struct {
+		name          string
+		kluster       *v1alpha1.Kluster
+		wantExpired   bool
+		wantExpiresAt bool
+		wantCondition metav1.ConditionStatus
+		wantEvent     bool
+	}{
+		{name: "no ttl", kluster: newKluster("kluster")},
+		{name: "before warning", kluster: expiringKluster("kluster", time.Hour, 3*time.Hour), wantExpiresAt: true, wantCondition: metav1.ConditionFalse},
+		{name: "within warning", kluster: expiringKluster("kluster", time.Hour, 90*time.Minute), wantExpiresAt: true, wantCondition: metav1.ConditionTrue, wantEvent: true},
+		// 만료 시각이 지난 뒤에는 경고하지 않고 바로 만료된다.
+		{name: "expired", kluster: expiringKluster("kluster", 2*time.Hour, time.Hour), wantExpired: true, wantExpiresAt: true, wantCondition: metav1.ConditionTrue},
+		// 잘못된 ttl은 validation이 기록하고, 만료로 보지 않는다.
+		{name: "zero ttl", kluster: expiringKluster("kluster", time.Hour, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, recorder := newTestController(t, Options{TTLWarning: time.Hour}, tt.kluster)
+
+			expired, err := c.syncExpiry(context.Background(), tt.kluster.DeepCopy())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expired != tt.wantExpired {
+				t.Errorf("expired = %v, want %v", expired, tt.wantExpired)
+			}
+			status := getKluster(t, c, "kluster").Status
+			if got := status.ExpiresAt != nil; got != tt.wantExpiresAt {
+				t.Errorf("expiresAt = %v, want set %v", status.ExpiresAt, tt.wantExpiresAt)
+			}
+			if tt.wantExpiresAt && !status.ExpiresAt.Time.Equal(tt.kluster.CreationTimestamp.Add(tt.kluster.Spec.TTL.Duration).Truncate(time.Second)) {
+				t.Errorf("expiresAt = %v, want creation time + ttl", status.ExpiresAt)
+			}
+			condition := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionExpiring)
+			switch {
+			case tt.wantCondition == "" && condition != nil:
+				t.Errorf("Expiring condition = %+v, want none", condition)
+			case tt.wantCondition != "" && (condition == nil || condition.Status != tt.wantCondition):
+				t.Errorf("Expiring condition = %+v, want %s", condition, tt.wantCondition)
+			}
+			if got := hasEvent(recorder, "ExpiringSoon"); got != tt.wantEvent {
+				t.Errorf("ExpiringSoon event = %v, want %v", got, tt.wantEvent)
+			}
+		})
+	}
+}
+
+// ttl을 지운 Kluster는 status의 만료 시각과 condition도 지운다.
+func TestSyncExpiryRemovesTTL(t *testing.T) {
+	kluster := newKluster("kluster")
+	expiresAt := metav1.Now()
+	kluster.Status.ExpiresAt = &expiresAt
+	kluster.Status.Conditions = []metav1.Condition{{Type: v1alpha1.ConditionExpiring, Status: metav1.ConditionTrue, Reason: "TTLExpiring"}}
+	c, _, _ := newTestController(t, Options{}, kluster)
+
+	if expired, err := c.syncExpiry(context.Background(), kluster.DeepCopy()); err != nil || expired {
+		t.Fatalf("syncExpiry() = %v, %v", expired, err)
+	}
+	if status := getKluster(t, c, "kluster").Status; status.ExpiresAt != nil || len(status.Conditions) != 0 {
+		t.Errorf("expiresAt = %v, conditions = %+v, want neither", status.ExpiresAt, status.Conditions)
+	}
+}
+
+func TestReconcileExpired(t *testing.T) {
+	invalid := expiringKluster("kluster", 2*time.Hour, time.Hour)
+	invalid.Spec.Region = "New York"
+	withoutTemplate := newKluster("kluster")
+	withoutTemplate.Spec.Template = "missing"
+	expiresAt := metav1.NewTime(time.Now().Add(-time.Minute))
+	withoutTemplate.Status.ExpiresAt = &expiresAt
+
+	tests := []struct {
+		name        string
+		kluster     *v1alpha1.Kluster
+		opts        Options
+		wantDeleted bool
+		wantEvent   string
+	}{
+		{name: "expired", kluster: expiringKluster("kluster", 2*time.Hour, time.Hour), wantDeleted: true, wantEvent: "Expired"},
+		// 만료는 spec 검증이나 policy보다 먼저 확인하므로, 잘못된 spec을 가진 Kluster도 만료된다.
+		{name: "invalid spec", kluster: invalid, wantDeleted: true, wantEvent: "Expired"},
+		// template이 지워져도 기록해둔 만료 시각으로 만료된다.
+		{name: "template not found", kluster: withoutTemplate, wantDeleted: true, wantEvent: "Expired"},
+		// pause 중이거나 dry-run이면 지우지 않는다.
+		{name: "paused", kluster: paused(expiringKluster("kluster", 2*time.Hour, time.Hour)), wantEvent: "ReconciliationPaused"},
+		{name: "dry-run", kluster: expiringKluster("kluster", 2*time.Hour, time.Hour), opts: Options{DryRun: true}, wantEvent: "DryRun"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, provider, recorder := newTestController(t, tt.opts, tt.kluster)
+
+			if err := c.reconcile(context.Background(), tt.kluster.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+			_, err := c.klient.Inspirit941V1alpha1().Klusters("default").Get(context.Background(), "kluster", metav1.GetOptions{})
+			if deleted := apierrors.IsNotFound(err); deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if tt.wantDeleted && provider.called("Create") {
+				t.Errorf("calls = %v, want no Create for an expired Kluster", provider.calls)
+			}
+			if !hasEvent(recorder, tt.wantEvent) {
+				t.Errorf("no %s event", tt.wantEvent)
+			}
+		})
+	}
+}
@@ -2,17 +2,26 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1/validation"
 	klientset "github.com/inspirit941/kluster/pkg/client/clientset/versioned"
 	klusterscheme "github.com/inspirit941/kluster/pkg/client/clientset/versioned/scheme"
 	informer "github.com/inspirit941/kluster/pkg/client/informers/externalversions/inspirit941.dev/v1alpha1"
 	klister "github.com/inspirit941/kluster/pkg/client/listers/inspirit941.dev/v1alpha1"
 	"github.com/inspirit941/kluster/pkg/digitalocean"
-	"github.com/kanisterio/kanister/pkg/poll"
+	"github.com/inspirit941/kluster/pkg/policy"
+	"github.com/inspirit941/kluster/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -20,10 +29,17 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"log"
+	"k8s.io/klog/v2"
+	"strings"
+	"sync"
 	"time"
 )
 
+var tracer = tracing.Tracer("pkg/controller")
+
+// 생성 중인 클러스터가 running이 되었는지 다시 확인하는 주기
+const clusterPollInterval = 30 * time.Second
+
 // required Field to run a Custom controller.
 type Controller struct {
 	// clientset for custom resource 'kluster'
@@ -36,18 +52,65 @@ type Controller struct {
 	klusterSynced cache.InformerSynced
 	// lister
 	kLister klister.KlusterLister
+	// spec.template으로 template을 참조하는 Kluster를 찾기 위한 index
+	klusterIndexer cache.Indexer
+	// KlusterTemplate lister
+	templateSynced cache.InformerSynced
+	tLister        klister.KlusterTemplateLister
+	// KlusterPool / KlusterClaim lister. spec.poolName으로 pool을 참조하는 claim을 찾기 위한 index
+	poolSynced   cache.InformerSynced
+	pLister      klister.KlusterPoolLister
+	claimSynced  cache.InformerSynced
+	cLister      klister.KlusterClaimLister
+	claimIndexer cache.Indexer
+	// KlusterSet / KlusterRollout lister
+	setSynced     cache.InformerSynced
+	sLister       klister.KlusterSetLister
+	rolloutSynced cache.InformerSynced
+	rLister       klister.KlusterRolloutLister
+	// KlusterQuota lister
+	quotaSynced cache.InformerSynced
+	qLister     klister.KlusterQuotaLister
 	// queue. object의 Create / delete 작업을 순차적으로 수행하기.
 	wq workqueue.RateLimitingInterface
+	// KlusterPool / KlusterClaim / KlusterSet / KlusterRollout / KlusterQuota queue. Kluster와 같은 "<namespace>/<name>" key를 쓰므로 queue를 나눈다.
+	poolWq    workqueue.RateLimitingInterface
+	claimWq   workqueue.RateLimitingInterface
+	setWq     workqueue.RateLimitingInterface
+	rolloutWq workqueue.RateLimitingInterface
+	quotaWq   workqueue.RateLimitingInterface
 	// Event Recorder
 	recorder record.EventRecorder
+	// namespace의 KlusterPolicy 검사. admission webhook과 같은 규칙을 사용한다.
+	policies *policy.Checker
+	// DigitalOcean API를 호출하는 provider layer. dry-run이면 클러스터를 변경하는 호출은 실행하지 않는다.
+	do provider
+
+	opts Options
+	// orphan으로 처음 발견된 시각(cluster ID 기준). sweeper goroutine에서만 사용한다.
+	orphans map[string]time.Time
+	// namespace마다 quota 검사를 한 번에 하나씩 하기 위한 lock. (quotaLock 참고)
+	quotaMu    sync.Mutex
+	quotaLocks map[string]*sync.Mutex
 }
 
-func NewController(client kubernetes.Interface, klient klientset.Interface, klusterInformer informer.KlusterInformer) *Controller {
+// Options: controller 설정
+type Options struct {
+	// mutating DigitalOcean 호출을 실행하지 않고 로그 / 이벤트로만 남긴다.
+	DryRun bool
+	// 이 controller가 돌고 있는 management cluster의 ID. 생성한 클러스터의 owner tag에 들어간다.
+	ManagementClusterID string
+	// spec.ttl로 정한 만료 시각보다 이만큼 먼저 Expiring condition과 경고 이벤트를 남긴다.
+	TTLWarning time.Duration
+	Orphans    OrphanOptions
+}
+
+func NewController(client kubernetes.Interface, klient klientset.Interface, informers informer.Interface, opts Options) *Controller {
 	// 이벤트를 생성할 때 "어떤 컴포넌트가 이벤트를 생성했는지"를 추가해줘야 함.
 	// -> Controller / Operator의 type을 code-generator가 Event code를 생성할 때 같이 넣어주는 것.
 	// Custom Resource를 code generate할 때 만들어진 scheme 패키지를 아래와 같이 사용한다.
 	runtime.Must(klusterscheme.AddToScheme(scheme.Scheme)) // operator type을 k8s standard scheme에 추가함.
-	log.Println("creating event broadcaster...")
+	klog.Info("creating event broadcaster...")
 	eventBroadCaster := record.NewBroadcaster()
 	eventBroadCaster.StartStructuredLogging(0) // 로깅관련 세팅 추가
 	eventBroadCaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
@@ -55,14 +118,44 @@ func NewController(client kubernetes.Interface, klient klientset.Interface, klus
 	}) // event interface 추가
 	recorder := eventBroadCaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "Kluster"})
 
+	klusterInformer := informers.Klusters()
+	templateInformer := informers.KlusterTemplates()
+	poolInformer := informers.KlusterPools()
+	claimInformer := informers.KlusterClaims()
+	setInformer := informers.KlusterSets()
+	rolloutInformer := informers.KlusterRollouts()
+	policyInformer := informers.KlusterPolicies()
+	quotaInformer := informers.KlusterQuotas()
 	c := &Controller{
-		client:        client,
-		klient:        klient,
-		klusterSynced: klusterInformer.Informer().HasSynced,
-		kLister:       klusterInformer.Lister(),
-		wq:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "kluster"),
-		recorder:      recorder,
+		client:         client,
+		klient:         klient,
+		klusterSynced:  klusterInformer.Informer().HasSynced,
+		kLister:        klusterInformer.Lister(),
+		templateSynced: templateInformer.Informer().HasSynced,
+		tLister:        templateInformer.Lister(),
+		poolSynced:     poolInformer.Informer().HasSynced,
+		pLister:        poolInformer.Lister(),
+		claimSynced:    claimInformer.Informer().HasSynced,
+		cLister:        claimInformer.Lister(),
+		setSynced:      setInformer.Informer().HasSynced,
+		sLister:        setInformer.Lister(),
+		rolloutSynced:  rolloutInformer.Informer().HasSynced,
+		rLister:        rolloutInformer.Lister(),
+		quotaSynced:    quotaInformer.Informer().HasSynced,
+		qLister:        quotaInformer.Lister(),
+		wq:             workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "kluster"),
+		poolWq:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "klusterPool"),
+		claimWq:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "klusterClaim"),
+		setWq:          workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "klusterSet"),
+		rolloutWq:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "klusterRollout"),
+		quotaWq:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "klusterQuota"),
+		recorder:       recorder,
+		policies:       policy.NewChecker(informers),
+		do:             digitalocean.NewClient(client, recorder, opts.DryRun, opts.ManagementClusterID),
+		opts:           opts,
+		orphans:        map[string]time.Time{},
 	}
+	registerMetrics()
 
 	// register functions.
 	// resource에 특정 이벤트가 들어올 때 실행될 함수를 정의하는 영역.
@@ -72,9 +165,88 @@ func NewController(client kubernetes.Interface, klient klientset.Interface, klus
 	klusterInformer.Informer().AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc:    c.handleAdd, // 리소스가 생성될 때
+			UpdateFunc: c.handleUpdate,
 			DeleteFunc: c.handleDel,
 		},
 	)
+	runtime.Must(klusterInformer.Informer().AddIndexers(cache.Indexers{templateIndex: indexByTemplate}))
+	c.klusterIndexer = klusterInformer.Informer().GetIndexer()
+	templateInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleTemplateAdd,
+			UpdateFunc: c.handleTemplateUpdate,
+			DeleteFunc: c.handleTemplateDel,
+		},
+	)
+
+	// pool의 Kluster는 status(ready 여부)가 바뀔 때도 pool / claim을 다시 처리해야 하므로 handleUpdate와 따로 등록한다.
+	klusterInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handlePoolKluster,
+			UpdateFunc: c.handlePoolKlusterUpdate,
+			DeleteFunc: c.handlePoolKluster,
+		},
+	)
+	poolInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handlePoolAdd,
+			UpdateFunc: c.handlePoolUpdate,
+		},
+	)
+	runtime.Must(claimInformer.Informer().AddIndexers(cache.Indexers{poolIndex: indexByPool}))
+	c.claimIndexer = claimInformer.Informer().GetIndexer()
+	claimInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleClaim,
+			UpdateFunc: c.handleClaimUpdate,
+			DeleteFunc: c.handleClaim,
+		},
+	)
+	klusterInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleSetKluster,
+			UpdateFunc: c.handleSetKlusterUpdate,
+			DeleteFunc: c.handleSetKluster,
+		},
+	)
+	setInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleSetAdd,
+			UpdateFunc: c.handleSetUpdate,
+		},
+	)
+	klusterInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: c.handleRolloutKlusterUpdate,
+		},
+	)
+	rolloutInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleRolloutAdd,
+			UpdateFunc: c.handleRolloutUpdate,
+		},
+	)
+	policyInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handlePolicy,
+			UpdateFunc: c.handlePolicyUpdate,
+			DeleteFunc: c.handlePolicy,
+		},
+	)
+	klusterInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleQuotaKluster,
+			UpdateFunc: c.handleQuotaKlusterUpdate,
+			DeleteFunc: c.handleQuotaKluster,
+		},
+	)
+	quotaInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleQuota,
+			UpdateFunc: c.handleQuotaUpdate,
+			DeleteFunc: c.handleQuota,
+		},
+	)
 
 	return c
 }
@@ -82,13 +254,25 @@ func NewController(client kubernetes.Interface, klient klientset.Interface, klus
 // workqueue로부터 값을 consume받아 처리하는 goroutine
 func (c *Controller) Run(ch chan struct{}) error {
 	// check if local cache has been initialized at least once.
-	if ok := cache.WaitForCacheSync(ch, c.klusterSynced); !ok {
+	if ok := cache.WaitForCacheSync(ch, c.klusterSynced, c.templateSynced, c.poolSynced, c.claimSynced, c.setSynced, c.rolloutSynced, c.quotaSynced, c.policies.HasSynced); !ok {
 		// 캐시가 싱크되지 않음
-		log.Println("cache was not synced")
+		klog.Info("cache was not synced")
 	}
 	// goroutine consumes from workqueue
 	go wait.Until(c.worker, time.Second, ch) // 채널이 closed되기 전까지 run 'f' every period.
+	go wait.Until(c.poolWorker, time.Second, ch)
+	go wait.Until(c.claimWorker, time.Second, ch)
+	go wait.Until(c.setWorker, time.Second, ch)
+	go wait.Until(c.rolloutWorker, time.Second, ch)
+	go wait.Until(c.quotaWorker, time.Second, ch)
+	if c.opts.Orphans.Interval > 0 {
+		go wait.Until(c.sweepOrphans, c.opts.Orphans.Interval, ch)
+	}
 	<-ch
+	// queue를 닫아야 Get에서 기다리고 있는 worker가 끝난다.
+	for _, q := range []workqueue.RateLimitingInterface{c.wq, c.poolWq, c.claimWq, c.setWq, c.rolloutWq, c.quotaWq} {
+		q.ShutDown()
+	}
 	return nil
 }
 
@@ -107,109 +291,613 @@ func (c *Controller) processNextItem() bool {
 		return false
 	}
 
-	// 함수가 동작 끝나면 workqueue에서 제거.
-	defer c.wq.Forget(item)
+	// 처리가 끝나면 Done을 호출해야 같은 key가 다시 queue에서 나올 수 있다.
+	defer c.wq.Done(item)
 
-	// get namespace / name from cache
-	key, err := cache.MetaNamespaceKeyFunc(item)
-	if err != nil {
-		log.Printf("error %s calling Namespace key func on cache for item", err.Error())
-		return false
+	// queue에는 object가 아니라 "<namespace>/<name>" key가 들어있다.
+	key, ok := item.(string)
+	if !ok {
+		klog.InfoS("unexpected item in workqueue", "item", item)
+		c.wq.Forget(item)
+		return true
 	}
-
 	ns, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
-		log.Printf("error during splitting key into namespace / name: %s", err.Error())
-		return false
+		klog.ErrorS(err, "splitting key into namespace / name", "key", key)
+		c.wq.Forget(item)
+		return true
 	}
 
+	// reconcile 한 번에 해당하는 로그는 전부 같은 kluster / reconcileID / attempt 값을 갖는다.
+	// 로그 스택에서 특정 클러스터의 history만 필터링할 수 있도록 logger에 값을 붙여서 context로 넘긴다.
+	reconcileID := uuid.NewUUID()
+	attempt := c.wq.NumRequeues(item) + 1
+	logger := klog.LoggerWithValues(klog.Background(),
+		"kluster", klog.KRef(ns, name),
+		"reconcileID", reconcileID,
+		"attempt", attempt,
+	)
+	ctx := klog.NewContext(context.Background(), logger)
+
+	// reconcile 전체를 하나의 span으로 감싼다. secret 조회 / DigitalOcean API 호출은 이 span의 child로 기록됨.
+	ctx, span := tracer.Start(ctx, "kluster.reconcile", trace.WithAttributes(
+		attribute.String("k8s.namespace.name", ns),
+		attribute.String("kluster.name", name),
+		attribute.String("kluster.reconcile_id", string(reconcileID)),
+		attribute.Int("kluster.attempt", attempt),
+	))
+	defer span.End()
+
 	// ns, name 확인했으니 lister로 exact Object 조회
 	kluster, err := c.kLister.Klusters(ns).Get(name)
 	// 클러스터에서 이미 삭제된 경우
+	// DigitalOcean 클러스터 정리는 finalizer가 있는 동안(deletionTimestamp가 설정된 상태) finalize에서 끝났다.
 	if apierrors.IsNotFound(err) {
-		log.Printf("handle delete event for kluster '%s'", name)
-		// todo: digitalocean api에서 클러스터 삭제 요청.
+		logger.V(4).Info("Kluster was deleted")
+		c.wq.Forget(item)
 		return true
 	}
 	if err != nil {
-		log.Printf("error during get Kluster resouces from lister: %s", err.Error())
-		return false
+		logger.Error(err, "getting Kluster resource from lister")
+		tracing.RecordError(span, err)
+		c.wq.AddRateLimited(item)
+		return true
+	}
+	span.SetAttributes(tracing.KlusterUID(kluster.UID))
+
+	if kluster.DeletionTimestamp != nil {
+		err = c.finalize(ctx, kluster)
+	} else {
+		err = c.reconcile(ctx, kluster)
+	}
+	if err != nil {
+		tracing.RecordError(span, err)
+		// 실패한 key는 backoff 후 다시 처리한다. attempt 값은 NumRequeues로 계산됨.
+		c.wq.AddRateLimited(item)
+		return true
 	}
+	c.wq.Forget(item)
+	return true
+}
 
-	log.Printf("Kluster spec from Resource : %+v", kluster.Spec)
+// reconcile: DigitalOcean에 클러스터를 생성하고, running 상태가 될 때까지 기다린 뒤 status를 업데이트한다.
+// 이미 생성된 클러스터는 생성 이후 바뀐 옵션만 DigitalOcean에 반영한다.
+func (c *Controller) reconcile(ctx context.Context, kluster *v1alpha1.Kluster) error {
+	logger := klog.FromContext(ctx)
 
-	// digital ocean api 호출
-	clusterID, err := digitalocean.Create(c.client, kluster.Spec)
+	// mutating webhook이 꺼져 있거나 webhook 배포 전에 만들어진 object일 수 있으므로 controller에서도 기본값을 채운다.
+	// template을 참조하면 template과 합친 spec에 기본값을 채운다.
+	resolved, template, err := c.resolveSpec(kluster)
+	if apierrors.IsNotFound(err) {
+		// template이 만들어지면 template 이벤트로 다시 처리되므로 에러를 리턴하지 않는다.
+		logger.Info("KlusterTemplate not found", "template", kluster.Spec.Template)
+		if expired, err := c.expiredWithoutTemplate(ctx, kluster); err != nil || (expired && !isPaused(kluster)) {
+			if err != nil {
+				return err
+			}
+			return c.expire(ctx, kluster)
+		}
+		c.recorder.Event(kluster, corev1.EventTypeWarning, "TemplateNotFound", "KlusterTemplate "+kluster.Spec.Template+" was not found.")
+		return c.setCondition(ctx, kluster, metav1.Condition{
+			Type:    v1alpha1.ConditionTemplateResolved,
+			Status:  metav1.ConditionFalse,
+			Reason:  "TemplateNotFound",
+			Message: "KlusterTemplate " + kluster.Spec.Template + " was not found",
+		})
+	}
 	if err != nil {
-		// do something (retry or return)
+		return err
 	}
-	// 성공적으로 클러스터가 생성될 경우 이벤트 생성
-	c.recorder.Event(kluster, corev1.EventTypeNormal, "ClusterCreation", "Digital Ocean Creation API was called to create the cluster.")
+	kluster = resolved
+	if template != nil {
+		if err := c.setCondition(ctx, kluster, metav1.Condition{
+			Type:    v1alpha1.ConditionTemplateResolved,
+			Status:  metav1.ConditionTrue,
+			Reason:  "TemplateMerged",
+			Message: templateMessage(template),
+		}); err != nil {
+			return err
+		}
+	} else if err := c.mutateStatus(ctx, kluster, func(status *v1alpha1.KlusterStatus) {
+		meta.RemoveStatusCondition(&status.Conditions, v1alpha1.ConditionTemplateResolved)
+	}); err != nil {
+		return err
+	}
+	logger.V(4).Info("Kluster spec from Resource", "spec", kluster.Spec)
 
-	log.Printf("cluster created; cluster id: %s", clusterID)
-	err = c.updateStatus(clusterID, "creating", kluster)
+	// ttl이 있으면 status에 만료 시각을 기록하고, 만료 시각에 다시 처리되도록 예약한다.
+	// spec 검증이나 policy에 걸린 Kluster도 만료되어야 하므로 그 전에 처리한다. pause 중에는 만료되어도 지우지 않고, pause가 풀린 뒤에 삭제한다.
+	expired, err := c.syncExpiry(ctx, kluster)
 	if err != nil {
-		log.Printf("error: %s,  during update status of the cluster '%s'\n", err.Error(), kluster.Name)
+		return err
+	}
+	if expired && !isPaused(kluster) {
+		return c.expire(ctx, kluster)
 	}
 
-	// query DigitalOcean API to make sure cluster's status is Running.
-	// kubectl create -f 명령어로 실행해도 알아서 백그라운드로 동작함.
-	err = c.waitForCluster(kluster.Spec, clusterID)
+	// 잘못된 spec으로 DigitalOcean API를 호출하지 않도록 먼저 검증한다.
+	// webhook을 거치지 않은 object일 수 있으므로 webhook과 같은 validation 패키지를 사용.
+	if errs := validation.ValidateKluster(kluster); len(errs) > 0 {
+		logger.Info("invalid Kluster spec", "errors", errs.ToAggregate().Error())
+		c.recorder.Event(kluster, corev1.EventTypeWarning, "InvalidSpec", errs.ToAggregate().Error())
+		// spec이 수정되기 전에는 retry해도 결과가 같으므로 에러를 리턴하지 않는다.
+		return c.setCondition(ctx, kluster, metav1.Condition{
+			Type:    v1alpha1.ConditionInvalidSpec,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ValidationFailed",
+			Message: errs.ToAggregate().Error(),
+		})
+	}
+	if err := c.setCondition(ctx, kluster, metav1.Condition{
+		Type:   v1alpha1.ConditionInvalidSpec,
+		Status: metav1.ConditionFalse,
+		Reason: "ValidationSucceeded",
+	}); err != nil {
+		return err
+	}
+	var templateGeneration int64
+	if template != nil {
+		templateGeneration = template.Generation
+	}
+	// policy를 어기면 spec이나 policy가 수정될 때까지 DigitalOcean 클러스터를 만들거나 변경하지 않고, 이미 있는 클러스터는 status만 갱신한다.
+	violated, err := c.checkPolicy(ctx, kluster, templateGeneration)
 	if err != nil {
-		log.Printf("error %s, waiting for cluster to be running", err.Error())
+		return err
+	}
+	if violated {
+		if kluster.Status.KlusterID == "" {
+			return nil
+		}
+		return c.refreshStatus(ctx, kluster, kluster.Status.KlusterID)
 	}
 
-	// status 변경. production의 경우 retry 로직이 추가되어야 함.
-	err = c.updateStatus(clusterID, "running", kluster)
+	// DigitalOcean 클러스터를 만들거나 adopt하기 전에 finalizer를 붙여서, Kluster가 삭제될 때 deletionPolicy를 적용할 수 있도록 한다.
+	if err := c.updateFinalizers(ctx, kluster, func(finalizers sets.String) {
+		finalizers.Insert(v1alpha1.KlusterFinalizer)
+	}); err != nil {
+		return err
+	}
+
+	// status에 cluster ID가 있으면 이미 생성 요청을 보낸 클러스터이므로 다시 생성하지 않는다.
+	clusterID := kluster.Status.KlusterID
+
+	// pause 상태에서는 DigitalOcean 클러스터를 변경하지 않고 status만 갱신한다.
+	paused := isPaused(kluster)
+	if err := c.setPausedCondition(ctx, kluster, paused); err != nil {
+		return err
+	}
+	if paused {
+		if clusterID == "" {
+			logger.Info("Kluster is paused, skipping cluster creation")
+			return nil
+		}
+		logger.V(2).Info("Kluster is paused, only refreshing status")
+		if _, err := c.detectDrift(ctx, kluster, clusterID); err != nil {
+			return err
+		}
+		return c.refreshStatus(ctx, kluster, clusterID)
+	}
+	// 예산을 넘으면 클러스터를 만들지 않고, 이미 있는 클러스터는 spec을 반영하지 않고 status만 갱신한다.
+	cost, exceeded, err := c.checkQuota(ctx, kluster)
 	if err != nil {
-		log.Printf("error %s, updating cluster status after waiting for cluster")
+		return err
 	}
-	c.recorder.Event(kluster, corev1.EventTypeNormal, "ClusterCreationCompleted", "Digital Ocean Creation API was completed.")
-	return true
-}
+	if exceeded {
+		if clusterID == "" {
+			return nil
+		}
+		return c.refreshStatus(ctx, kluster, clusterID)
+	}
+	if id := kluster.Annotations[v1alpha1.AdoptClusterIDAnnotation]; clusterID == "" && id != "" {
+		adopted, err := c.adopt(ctx, kluster, id)
+		if err != nil || !adopted {
+			return err
+		}
+		clusterID = id
+	} else if clusterID == "" {
+		// digital ocean api 호출
+		id, err := c.do.Create(ctx, kluster)
+		// dry-run이면 생성되지 않았으므로 이후 단계(대기, update, status 갱신)도 진행하지 않는다.
+		if errors.Is(err, digitalocean.ErrDryRun) {
+			logger.Info("dry-run: cluster was not created")
+			return nil
+		}
+		if err != nil {
+			logger.Error(err, "calling DigitalOcean create API")
+			c.recorder.Event(kluster, corev1.EventTypeWarning, "ClusterCreationFailed", err.Error())
+			return err
+		}
+		clusterID = id
+		// 성공적으로 클러스터가 생성될 경우 이벤트 생성
+		c.recorder.Event(kluster, corev1.EventTypeNormal, "ClusterCreation", "Digital Ocean Creation API was called to create the cluster.")
+		logger.Info("cluster created", "clusterID", clusterID)
+		err = c.updateStatus(ctx, clusterID, "creating", kluster)
+		if err != nil {
+			logger.Error(err, "updating status of the cluster")
+		}
+		// 생성을 기다리는 동안 다른 Kluster의 quota 검사에 포함되도록 비용을 바로 기록한다.
+		if err := c.mutateStatus(ctx, kluster, func(status *v1alpha1.KlusterStatus) {
+			status.EstimatedMonthlyCost = cost
+			status.AppliedNodes = int32(validation.KlusterNodes(&kluster.Spec))
+		}); err != nil {
+			logger.Error(err, "recording estimated monthly cost")
+		}
+	}
+	logger = klog.LoggerWithValues(logger, "clusterID", clusterID)
+	ctx = klog.NewContext(ctx, logger)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("digitalocean.cluster_id", clusterID))
 
-func (c *Controller) waitForCluster(spec v1alpha1.KlusterSpec, clusterId string) error {
-	// context에 timeout 붙여서 '특정 시간을 초과하면 더 이상 poll하지 않도록' 설정
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	defer cancel()
+	// 생성 직후에만 running이 될 때까지 기다린다. 이후의 progress는 DigitalOcean 상태(degraded, upgrading 등)를 그대로 기록함.
+	// 생성에는 수 분이 걸리므로 worker를 붙잡고 poll하지 않고, clusterPollInterval 뒤에 다시 처리해서 상태를 확인한다.
+	// 그 동안 같은 worker가 다른 Kluster(i.e. KlusterPool이 한 번에 만든 Kluster들)를 처리할 수 있다.
+	if progress := kluster.Status.Progress; progress == "" || progress == "creating" {
+		state, err := c.do.ClusterState(ctx, kluster, clusterID)
+		if err != nil {
+			logger.Error(err, "getting cluster state")
+			return err
+		}
+		if state != "running" {
+			logger.V(2).Info("cluster is not running yet, checking again later", "state", state, "after", clusterPollInterval)
+			key, err := cache.MetaNamespaceKeyFunc(kluster)
+			if err != nil {
+				return err
+			}
+			c.wq.AddAfter(key, clusterPollInterval)
+			return nil
+		}
 
-	// poll.Wait() 파라미터로 들어간 func가 true를 리턴할 때까지 주기적으로 실행함.
-	return poll.Wait(ctx, func(ctx context.Context) (bool, error) {
-		state, err := digitalocean.ClusterState(c.client, spec, clusterId)
+		// status 변경. production의 경우 retry 로직이 추가되어야 함.
+		err = c.updateStatus(ctx, clusterID, "running", kluster)
 		if err != nil {
-			return false, err
+			logger.Error(err, "updating cluster status after waiting for cluster")
+			return err
 		}
-		if state == "running" {
-			return true, nil
+		c.recorder.Event(kluster, corev1.EventTypeNormal, "ClusterCreationCompleted", "Digital Ocean Creation API was completed.")
+	}
+
+	// node pool schedule이 정한 count를 반영한 spec으로 drift 비교와 update를 한다.
+	kluster, scheduled, err := c.applySchedules(ctx, kluster)
+	if err != nil {
+		return err
+	}
+
+	// resync마다 콘솔 등에서 직접 바뀐 값이 있는지 확인해서 Drifted condition에 기록한다.
+	drifted, err := c.detectDrift(ctx, kluster, clusterID)
+	if err != nil {
+		return err
+	}
+
+	// Report 정책이면 spec / template이 바뀌었거나 schedule이 실행됐을 때만 반영하고, resync에서 발견된 drift는 기록만 한다.
+	specChanged := kluster.Generation != kluster.Status.ObservedGeneration || templateGeneration != kluster.Status.ObservedTemplateGeneration
+	if drifted && kluster.Spec.DriftPolicy == v1alpha1.DriftPolicyReport && !specChanged && !scheduled {
+		logger.V(2).Info("drift policy is Report, not correcting the cluster")
+		return c.refreshStatus(ctx, kluster, clusterID)
+	}
+
+	// 생성 이후에 바뀐 클러스터 옵션(version, tags, HA, upgrade, maintenance policy, registry)을 반영한다.
+	// registry 연동은 create API로 설정할 수 없어서 생성 직후에도 여기서 켜진다.
+	updated, err := c.do.Update(ctx, kluster, clusterID)
+	if err != nil {
+		logger.Error(err, "calling DigitalOcean update API")
+		c.recorder.Event(kluster, corev1.EventTypeWarning, "ClusterUpdateFailed", err.Error())
+		return err
+	}
+	if updated {
+		c.recorder.Event(kluster, corev1.EventTypeNormal, "ClusterUpdated", "Digital Ocean cluster options were updated to match the spec.")
+	}
+	if err := c.mutateStatus(ctx, kluster, func(status *v1alpha1.KlusterStatus) {
+		status.ObservedGeneration = kluster.Generation
+		status.ObservedTemplateGeneration = templateGeneration
+		status.EstimatedMonthlyCost = cost
+		status.AppliedNodes = int32(validation.KlusterNodes(&kluster.Spec))
+	}); err != nil {
+		return err
+	}
+
+	return c.refreshStatus(ctx, kluster, clusterID)
+}
+
+// refreshStatus: DigitalOcean 클러스터의 실제 상태(endpoint, IP, version, autoscaler가 바꾼 노드 수 등)를 status에 기록한다.
+func (c *Controller) refreshStatus(ctx context.Context, kluster *v1alpha1.Kluster, clusterID string) error {
+	observed, err := c.do.ObservedStatus(ctx, kluster, clusterID)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "getting cluster status")
+		return err
+	}
+	return c.mutateStatus(ctx, kluster, func(status *v1alpha1.KlusterStatus) {
+		status.Progress = observed.Progress
+		status.Message = observed.Message
+		status.Version = observed.Version
+		status.Endpoint = observed.Endpoint
+		status.IPv4 = observed.IPv4
+		status.VPCUUID = observed.VPCUUID
+		status.ClusterSubnet = observed.ClusterSubnet
+		status.ServiceSubnet = observed.ServiceSubnet
+		status.CreatedAt = observed.CreatedAt
+		status.UpdatedAt = observed.UpdatedAt
+		status.Nodes = observed.Nodes
+		status.NodePools = observed.NodePools
+		// scale subresource의 status replicas / selector는 primary node pool 기준.
+		status.Replicas = 0
+		for _, pool := range observed.NodePools {
+			if pool.Name == kluster.Spec.PrimaryNodePool {
+				status.Replicas = int32(pool.Count)
+			}
 		}
-		return false, nil
+		status.Selector = digitalocean.NodePoolLabel + "=" + kluster.Spec.PrimaryNodePool
 	})
 }
 
+// detectDrift: 실제 DigitalOcean 클러스터와 spec의 차이를 Drifted condition에 기록하고, update로 되돌릴 수 있는 차이가 있으면 true를 리턴한다.
+// name / region처럼 되돌릴 수 없는 차이만 남으면 reason을 CannotCorrect로 두어서 Correcting에 머물지 않도록 한다.
+// drift 내용이 바뀔 때만 Warning 이벤트를 남겨서 resync마다 같은 이벤트가 쌓이지 않도록 한다.
+func (c *Controller) detectDrift(ctx context.Context, kluster *v1alpha1.Kluster, clusterID string) (bool, error) {
+	logger := klog.FromContext(ctx)
+	drift, err := c.do.Drift(ctx, kluster, clusterID)
+	if err != nil {
+		logger.Error(err, "comparing spec with the cluster")
+		return false, err
+	}
+	condition := metav1.Condition{
+		Type:    v1alpha1.ConditionDrifted,
+		Status:  metav1.ConditionFalse,
+		Reason:  "InSync",
+		Message: "Digital Ocean cluster matches the spec",
+	}
+	if all := drift.All(); len(all) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Message = strings.Join(all, "; ")
+		switch {
+		case len(drift.Correctable) == 0:
+			condition.Reason = "CannotCorrect"
+		case kluster.Spec.DriftPolicy == v1alpha1.DriftPolicyReport:
+			condition.Reason = "DriftDetected"
+		default:
+			condition.Reason = "Correcting"
+		}
+		if previous := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionDrifted); previous == nil || previous.Message != condition.Message {
+			logger.Info("cluster drifted from spec", "drift", all, "driftPolicy", kluster.Spec.DriftPolicy)
+			c.recorder.Event(kluster, corev1.EventTypeWarning, "ClusterDrifted", condition.Message)
+		}
+	}
+	return len(drift.Correctable) > 0, c.setCondition(ctx, kluster, condition)
+}
+
+// finalize: 삭제 중인 Kluster의 deletionPolicy에 따라 DigitalOcean 클러스터를 삭제하거나(Delete) owner tag만 지우고 남겨둔 뒤(Retain),
+// finalizer를 제거해서 object가 실제로 삭제되도록 한다.
+func (c *Controller) finalize(ctx context.Context, kluster *v1alpha1.Kluster) error {
+	if !sets.NewString(kluster.Finalizers...).Has(v1alpha1.KlusterFinalizer) {
+		return nil
+	}
+	logger := klog.FromContext(ctx)
+	// deletionPolicy 등이 template에 있을 수 있으므로, template이 없으면 잘못된 정책으로 정리하지 않도록 삭제를 미룬다.
+	resolved, _, err := c.resolveSpec(kluster)
+	if apierrors.IsNotFound(err) {
+		logger.Info("KlusterTemplate not found, postponing cluster cleanup", "template", kluster.Spec.Template)
+		c.recorder.Event(kluster, corev1.EventTypeWarning, "TemplateNotFound", "KlusterTemplate "+kluster.Spec.Template+" was not found, cleanup of the Digital Ocean cluster is postponed until it is recreated.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	kluster = resolved
+
+	// pause가 풀리면 annotation 변경 이벤트로 다시 처리되므로, 그때까지 finalizer를 유지해서 삭제를 미룬다.
+	paused := isPaused(kluster)
+	if err := c.setPausedCondition(ctx, kluster, paused); err != nil {
+		return err
+	}
+	if paused {
+		logger.Info("Kluster is paused, postponing cluster cleanup")
+		return nil
+	}
+
+	// cluster ID가 없으면 생성 요청 전에 삭제된 것이므로 정리할 클러스터가 없다.
+	if clusterID := kluster.Status.KlusterID; clusterID != "" {
+		logger = klog.LoggerWithValues(logger, "clusterID", clusterID, "deletionPolicy", kluster.Spec.DeletionPolicy)
+		ctx = klog.NewContext(ctx, logger)
+		switch kluster.Spec.DeletionPolicy {
+		case v1alpha1.DeletionPolicyRetain:
+			err := c.do.Release(ctx, kluster, clusterID)
+			// dry-run이면 finalizer를 유지한다. 실제로 정리되지 않은 클러스터를 가진 Kluster가 지워지지 않도록.
+			if errors.Is(err, digitalocean.ErrDryRun) {
+				logger.Info("dry-run: cluster was not released, keeping the finalizer")
+				return nil
+			}
+			if err != nil {
+				logger.Error(err, "removing owner tags from retained cluster")
+				c.recorder.Event(kluster, corev1.EventTypeWarning, "ClusterRetainFailed", err.Error())
+				return err
+			}
+			// Kluster가 사라진 뒤에도 cluster ID를 찾을 수 있도록 이벤트에 남긴다.
+			logger.Info("cluster retained")
+			c.recorder.Eventf(kluster, corev1.EventTypeNormal, "ClusterRetained", "Digital Ocean cluster %s (%s) was retained and is no longer managed by this Kluster.", kluster.Spec.Name, clusterID)
+		default:
+			err := c.do.Delete(ctx, kluster, clusterID)
+			if errors.Is(err, digitalocean.ErrDryRun) {
+				logger.Info("dry-run: cluster was not deleted, keeping the finalizer")
+				return nil
+			}
+			if err != nil {
+				logger.Error(err, "calling DigitalOcean delete API")
+				c.recorder.Event(kluster, corev1.EventTypeWarning, "ClusterDeletionFailed", err.Error())
+				return err
+			}
+			logger.Info("cluster deleted")
+			c.recorder.Eventf(kluster, corev1.EventTypeNormal, "ClusterDeleted", "Digital Ocean cluster %s (%s) was deleted.", kluster.Spec.Name, clusterID)
+		}
+	}
+
+	return c.updateFinalizers(ctx, kluster, func(finalizers sets.String) {
+		finalizers.Delete(v1alpha1.KlusterFinalizer)
+	})
+}
+
+func isPaused(kluster *v1alpha1.Kluster) bool {
+	return kluster.Annotations[v1alpha1.PausedAnnotation] == "true"
+}
+
+// Paused condition을 설정하고, pause가 시작되거나 끝날 때 이벤트를 남긴다.
+func (c *Controller) setPausedCondition(ctx context.Context, kluster *v1alpha1.Kluster, paused bool) error {
+	wasPaused := meta.IsStatusConditionTrue(kluster.Status.Conditions, v1alpha1.ConditionPaused)
+	condition := metav1.Condition{
+		Type:    v1alpha1.ConditionPaused,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Reconciling",
+		Message: "changes to the Digital Ocean cluster are reconciled",
+	}
+	if paused {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "PausedByAnnotation"
+		condition.Message = v1alpha1.PausedAnnotation + " annotation is set, changes to the Digital Ocean cluster are not reconciled"
+	}
+	if err := c.setCondition(ctx, kluster, condition); err != nil {
+		return err
+	}
+	switch {
+	case paused && !wasPaused:
+		klog.FromContext(ctx).Info("reconciliation paused")
+		c.recorder.Event(kluster, corev1.EventTypeNormal, "ReconciliationPaused", "Changes to the Digital Ocean cluster are paused by the "+v1alpha1.PausedAnnotation+" annotation.")
+	case !paused && wasPaused:
+		klog.FromContext(ctx).Info("reconciliation resumed")
+		c.recorder.Event(kluster, corev1.EventTypeNormal, "ReconciliationResumed", "Changes to the Digital Ocean cluster are reconciled again.")
+	}
+	return nil
+}
+
+// 최신 Kluster를 조회해서 finalizer 목록을 바꾼 뒤, 바뀐 내용이 있을 때만 업데이트한다.
+func (c *Controller) updateFinalizers(ctx context.Context, kluster *v1alpha1.Kluster, mutate func(finalizers sets.String)) error {
+	k, err := c.klient.Inspirit941V1alpha1().Klusters(kluster.Namespace).Get(ctx, kluster.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	finalizers := sets.NewString(k.Finalizers...)
+	mutate(finalizers)
+	if finalizers.Equal(sets.NewString(k.Finalizers...)) {
+		return nil
+	}
+	k.Finalizers = finalizers.List()
+	_, err = c.klient.Inspirit941V1alpha1().Klusters(k.Namespace).Update(ctx, k, metav1.UpdateOptions{})
+	return err
+}
+
+// adopt: 이미 있는 DigitalOcean 클러스터를 새로 만들지 않고 이 Kluster가 관리하도록 status에 cluster ID를 기록한다.
+// adopt 직후의 update 단계에서 node pool 삭제나 resize가 일어나지 않도록, spec이 클러스터와 완전히 일치할 때만 adopt한다.
+// 일치하지 않으면 Adopted condition에 차이를 기록하고, spec이 수정될 때까지 기다린다.
+func (c *Controller) adopt(ctx context.Context, kluster *v1alpha1.Kluster, id string) (bool, error) {
+	logger := klog.FromContext(ctx)
+	clusterDrift, err := c.do.Drift(ctx, kluster, id)
+	if err != nil {
+		logger.Error(err, "comparing spec with the cluster to adopt", "clusterID", id)
+		c.recorder.Event(kluster, corev1.EventTypeWarning, "ClusterAdoptionFailed", err.Error())
+		return false, err
+	}
+	if drift := clusterDrift.All(); len(drift) > 0 {
+		message := strings.Join(drift, "; ")
+		logger.Info("spec does not match the cluster to adopt", "clusterID", id, "drift", drift)
+		c.recorder.Event(kluster, corev1.EventTypeWarning, "ClusterAdoptionFailed", message)
+		return false, c.setCondition(ctx, kluster, metav1.Condition{
+			Type:    v1alpha1.ConditionAdopted,
+			Status:  metav1.ConditionFalse,
+			Reason:  "SpecMismatch",
+			Message: message,
+		})
+	}
+
+	logger.Info("adopting existing cluster", "clusterID", id)
+	if err := c.setCondition(ctx, kluster, metav1.Condition{
+		Type:    v1alpha1.ConditionAdopted,
+		Status:  metav1.ConditionTrue,
+		Reason:  "SpecMatched",
+		Message: "existing DigitalOcean cluster " + id + " is managed by this Kluster",
+	}); err != nil {
+		return false, err
+	}
+	// creating이 아니므로 생성 완료를 기다리는 단계는 건너뛴다. progress는 reconcile 마지막에 DigitalOcean 상태로 덮어써짐.
+	kluster.Status.Progress = "adopted"
+	if err := c.updateStatus(ctx, id, kluster.Status.Progress, kluster); err != nil {
+		return false, err
+	}
+	c.recorder.Event(kluster, corev1.EventTypeNormal, "ClusterAdopted", "Existing Digital Ocean cluster "+id+" was adopted.")
+	return true, nil
+}
+
 // subresource인 Status를 업데이트하는 로직
-func (c *Controller) updateStatus(id, progress string, kluster *v1alpha1.Kluster) error {
+func (c *Controller) updateStatus(ctx context.Context, id, progress string, kluster *v1alpha1.Kluster) error {
+	klog.FromContext(ctx).V(2).Info("updating kluster status", "progress", progress)
+	return c.mutateStatus(ctx, kluster, func(status *v1alpha1.KlusterStatus) {
+		status.KlusterID = id
+		status.Progress = progress
+	})
+}
+
+// condition 하나를 추가하거나 갱신한다. status / reason / message가 같으면 lastTransitionTime은 유지됨.
+func (c *Controller) setCondition(ctx context.Context, kluster *v1alpha1.Kluster, condition metav1.Condition) error {
+	return c.mutateStatus(ctx, kluster, func(status *v1alpha1.KlusterStatus) {
+		condition.ObservedGeneration = kluster.Generation
+		meta.SetStatusCondition(&status.Conditions, condition)
+	})
+}
+
+// 최신 Kluster를 조회해서 mutate를 적용한 뒤, 바뀐 내용이 있을 때만 status subresource를 업데이트한다.
+func (c *Controller) mutateStatus(ctx context.Context, kluster *v1alpha1.Kluster, mutate func(status *v1alpha1.KlusterStatus)) error {
 	// update를 실행할 때, kluster struct가 이미 modified된 상태면 에러가 발생함
 	// i.e. error Operation cannot be fulfilled on kluster.inspirit941.dev "<cr name>" : the object has been modified; please apply your changes to the latest version and try again..
 	// 따라서 latest kluster struct를 받을 수 있도록 수정. (get the latest version of kluster)
-	k, err := c.klient.Inspirit941V1alpha1().Klusters(kluster.Namespace).Get(context.Background(), kluster.Name, metav1.GetOptions{})
+	k, err := c.klient.Inspirit941V1alpha1().Klusters(kluster.Namespace).Get(ctx, kluster.Name, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
 
-	k.Status.KlusterID = id
-	k.Status.Progress = progress
+	status := k.Status.DeepCopy()
+	mutate(&k.Status)
+	if equality.Semantic.DeepEqual(status, &k.Status) {
+		return nil
+	}
 	// subresource 정의한 다음 code-generate하면 새로 생성되는 메소드.
-	_, err = c.klient.Inspirit941V1alpha1().Klusters(kluster.Namespace).UpdateStatus(context.Background(), kluster, metav1.UpdateOptions{})
+	_, err = c.klient.Inspirit941V1alpha1().Klusters(kluster.Namespace).UpdateStatus(ctx, k, metav1.UpdateOptions{})
 	return err
 }
 
 // 리소스 생성 이벤트가 들어올 때.
 func (c *Controller) handleAdd(obj interface{}) {
-	log.Println("handleAdd was called")
-	c.wq.Add(obj)
+	klog.V(4).InfoS("handleAdd was called")
+	c.enqueue(obj)
+}
+
+// spec이 바뀐 경우, resync인 경우, 삭제가 시작된 경우, pause 여부나 adopt할 cluster ID annotation이 바뀐 경우에만 처리한다.
+// status 업데이트는 generation이 바뀌지 않으므로 무시하고, resync(resourceVersion이 같음)는 node pool status 갱신을 위해 처리한다.
+func (c *Controller) handleUpdate(oldObj, newObj interface{}) {
+	old, ok := oldObj.(*v1alpha1.Kluster)
+	if !ok {
+		return
+	}
+	kluster, ok := newObj.(*v1alpha1.Kluster)
+	if !ok {
+		return
+	}
+	// 삭제가 시작되면(deletionTimestamp 설정) finalize를 위해 처리하고, pause / adopt annotation이 바뀌면 바로 반영한다.
+	if old.Generation == kluster.Generation && old.ResourceVersion != kluster.ResourceVersion && kluster.DeletionTimestamp == nil &&
+		isPaused(old) == isPaused(kluster) &&
+		old.Annotations[v1alpha1.AdoptClusterIDAnnotation] == kluster.Annotations[v1alpha1.AdoptClusterIDAnnotation] {
+		return
+	}
+	klog.V(4).InfoS("handleUpdate was called", "kluster", klog.KObj(kluster), "generation", kluster.Generation)
+	c.enqueue(kluster)
 }
 
 func (c *Controller) handleDel(obj interface{}) {
-	log.Println("handleDel was called")
-	c.wq.Add(obj)
+	klog.V(4).InfoS("handleDel was called")
+	c.enqueue(obj)
+}
+
+// object 대신 key를 queue에 넣어야 같은 Kluster에 대한 이벤트가 여러 번 들어와도 한 번만 처리된다.
+func (c *Controller) enqueue(obj interface{}) {
+	// 삭제 이벤트는 DeletedFinalStateUnknown으로 감싸져 올 수 있다.
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.ErrorS(err, "getting key of Kluster")
+		return
+	}
+	c.wq.Add(key)
 }
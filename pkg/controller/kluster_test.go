@@ -0,0 +1,774 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-logr/logr/funcr"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/client/clientset/versioned/fake"
+	"github.com/inspirit941/kluster/pkg/client/informers/externalversions"
+	"github.com/inspirit941/kluster/pkg/digitalocean"
+	"github.com/inspirit941/kluster/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeProvider: DigitalOcean API를 호출하지 않고 정해둔 값을 리턴한다. 호출 내역은 "<method> <cluster ID>"로 기록한다.
+type fakeProvider struct {
+	mu    sync.Mutex
+	calls []string
+
+	createID   string
+	createErr  error
+	state      string
+	updated    bool
+	drift      digitalocean.ClusterDrift
+	observed   v1alpha1.KlusterStatus
+	cost       float64
+	costErr    error
+	kubeconfig []byte
+	managed    []digitalocean.ManagedCluster
+	deleteErr  error
+}
+
+func (p *fakeProvider) record(call string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, call)
+}
+
+// called: method(와 cluster ID)로 시작하는 호출이 있었는지
+func (p *fakeProvider) called(call string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, c := range p.calls {
+		if c == call || strings.HasPrefix(c, call+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *fakeProvider) Create(_ context.Context, k *v1alpha1.Kluster) (string, error) {
+	p.record("Create " + k.Name)
+	return p.createID, p.createErr
+}
+
+func (p *fakeProvider) ClusterState(_ context.Context, _ *v1alpha1.Kluster, id string) (string, error) {
+	p.record("ClusterState " + id)
+	return p.state, nil
+}
+
+func (p *fakeProvider) Update(_ context.Context, _ *v1alpha1.Kluster, id string) (bool, error) {
+	p.record("Update " + id)
+	return p.updated, nil
+}
+
+func (p *fakeProvider) ObservedStatus(_ context.Context, _ *v1alpha1.Kluster, id string) (*v1alpha1.KlusterStatus, error) {
+	p.record("ObservedStatus " + id)
+	observed := p.observed
+	return &observed, nil
+}
+
+func (p *fakeProvider) Drift(_ context.Context, _ *v1alpha1.Kluster, id string) (digitalocean.ClusterDrift, error) {
+	p.record("Drift " + id)
+	return p.drift, nil
+}
+
+func (p *fakeProvider) Release(_ context.Context, _ *v1alpha1.Kluster, id string) error {
+	p.record("Release " + id)
+	return p.deleteErr
+}
+
+func (p *fakeProvider) Delete(_ context.Context, _ *v1alpha1.Kluster, id string) error {
+	p.record("Delete " + id)
+	return p.deleteErr
+}
+
+func (p *fakeProvider) Kubeconfig(_ context.Context, _ *v1alpha1.Kluster, id string) ([]byte, error) {
+	p.record("Kubeconfig " + id)
+	return p.kubeconfig, nil
+}
+
+func (p *fakeProvider) MonthlyCost(_ context.Context, k *v1alpha1.Kluster) (float64, error) {
+	p.record("MonthlyCost " + k.Name)
+	return p.cost, p.costErr
+}
+
+func (p *fakeProvider) ManagedClusters(_ context.Context, tokenSecret digitalocean.TokenSecret) ([]digitalocean.ManagedCluster, error) {
+	p.record("ManagedClusters " + tokenSecret.Ref)
+	return p.managed, nil
+}
+
+func (p *fakeProvider) DeleteOrphan(_ context.Context, _ digitalocean.TokenSecret, _ runtime.Object, id string) error {
+	p.record("DeleteOrphan " + id)
+	return p.deleteErr
+}
+
+// newTestController: objects를 fake clientset과 informer cache에 넣은 controller를 만든다.
+// informer는 시작하지 않으므로 lister는 objects만 보고, status 업데이트 등은 fake clientset에만 반영된다.
+func newTestController(t *testing.T, opts Options, objects ...runtime.Object) (*Controller, *fakeProvider, *record.FakeRecorder) {
+	t.Helper()
+	var klusterObjects, kubeObjects []runtime.Object
+	for _, obj := range objects {
+		if isKlusterObject(obj) {
+			klusterObjects = append(klusterObjects, obj)
+		} else {
+			kubeObjects = append(kubeObjects, obj)
+		}
+	}
+	klient := fake.NewSimpleClientset(klusterObjects...)
+	factory := externalversions.NewSharedInformerFactory(klient, 0)
+	informers := factory.Inspirit941().V1alpha1()
+	c := NewController(k8sfake.NewSimpleClientset(kubeObjects...), klient, informers, opts)
+	for _, obj := range klusterObjects {
+		var err error
+		switch obj := obj.(type) {
+		case *v1alpha1.Kluster:
+			err = informers.Klusters().Informer().GetIndexer().Add(obj)
+		case *v1alpha1.KlusterTemplate:
+			err = informers.KlusterTemplates().Informer().GetIndexer().Add(obj)
+		case *v1alpha1.KlusterPool:
+			err = informers.KlusterPools().Informer().GetIndexer().Add(obj)
+		case *v1alpha1.KlusterClaim:
+			err = informers.KlusterClaims().Informer().GetIndexer().Add(obj)
+		case *v1alpha1.KlusterSet:
+			err = informers.KlusterSets().Informer().GetIndexer().Add(obj)
+		case *v1alpha1.KlusterRollout:
+			err = informers.KlusterRollouts().Informer().GetIndexer().Add(obj)
+		case *v1alpha1.KlusterPolicy:
+			err = informers.KlusterPolicies().Informer().GetIndexer().Add(obj)
+		case *v1alpha1.KlusterQuota:
+			err = informers.KlusterQuotas().Informer().GetIndexer().Add(obj)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	provider := &fakeProvider{createID: "cluster-1", state: "running"}
+	recorder := record.NewFakeRecorder(100)
+	c.do = provider
+	c.recorder = recorder
+	t.Cleanup(func() {
+		for _, q := range []interface{ ShutDown() }{c.wq, c.poolWq, c.claimWq, c.setWq, c.rolloutWq, c.quotaWq} {
+			q.ShutDown()
+		}
+	})
+	return c, provider, recorder
+}
+
+func isKlusterObject(obj runtime.Object) bool {
+	switch obj.(type) {
+	case *v1alpha1.Kluster, *v1alpha1.KlusterTemplate, *v1alpha1.KlusterPool, *v1alpha1.KlusterClaim,
+		*v1alpha1.KlusterSet, *v1alpha1.KlusterRollout, *v1alpha1.KlusterPolicy, *v1alpha1.KlusterQuota:
+		return true
+	}
+	return false
+}
+
+func newKluster(name string) *v1alpha1.Kluster {
+	return &v1alpha1.Kluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name), Generation: 1},
+	}
+}
+
+// getKluster: fake clientset에 반영된 Kluster
+func getKluster(t *testing.T, c *Controller, name string) *v1alpha1.Kluster {
+	t.Helper()
+	kluster, err := c.klient.Inspirit941V1alpha1().Klusters("default").Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kluster
+}
+
+// events: 지금까지 기록된 이벤트. "<type> <reason> <message>" 형식.
+func events(recorder *record.FakeRecorder) []string {
+	var got []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			got = append(got, event)
+		default:
+			return got
+		}
+	}
+}
+
+func hasEvent(recorder *record.FakeRecorder, reason string) bool {
+	for _, event := range events(recorder) {
+		if strings.Contains(event, " "+reason+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// captureLogs: klog 출력을 JSON 한 줄씩 파싱해서 모은다. V(4)까지 기록한다.
+func captureLogs(t *testing.T) func() []map[string]interface{} {
+	var mu sync.Mutex
+	var lines []map[string]interface{}
+	logger := funcr.NewJSON(func(obj string) {
+		line := map[string]interface{}{}
+		// funcr은 앞쪽 필드가 omitempty로 빠진 struct 값(i.e. spec)을 올바른 JSON으로 출력하지 못한다. 그런 줄은 건너뛴다.
+		if err := json.Unmarshal([]byte(obj), &line); err != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, line)
+	}, funcr.Options{Verbosity: 4})
+	klog.SetLoggerWithOptions(logger, klog.ContextualLogger(true))
+	t.Cleanup(klog.ClearLogger)
+	return func() []map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]map[string]interface{}{}, lines...)
+	}
+}
+
+func TestProcessNextItemLogsKlusterValues(t *testing.T) {
+	c, _, _ := newTestController(t, Options{}, newKluster("kluster"))
+	logs := captureLogs(t)
+
+	c.wq.Add("default/kluster")
+	if !c.processNextItem() {
+		t.Fatal("processNextItem() = false")
+	}
+
+	var reconcileIDs []string
+	var clusterLogged bool
+	for _, line := range logs() {
+		// reconcile 중의 로그만 본다. NewController 등 queue 밖의 로그에는 kluster 값이 없다.
+		id, ok := line["reconcileID"].(string)
+		if !ok {
+			continue
+		}
+		reconcileIDs = append(reconcileIDs, id)
+		if kluster, _ := line["kluster"].(map[string]interface{}); kluster["name"] != "kluster" || kluster["namespace"] != "default" {
+			t.Errorf("log %v has kluster %v, want default/kluster", line["msg"], line["kluster"])
+		}
+		if line["attempt"] != float64(1) {
+			t.Errorf("log %v has attempt %v, want 1", line["msg"], line["attempt"])
+		}
+		// 생성 이후의 로그에는 cluster ID가 붙는다.
+		if line["msg"] == "updating kluster status" && line["progress"] == "running" {
+			clusterLogged = true
+			if line["clusterID"] != "cluster-1" {
+				t.Errorf("log %v has clusterID %v, want cluster-1", line["msg"], line["clusterID"])
+			}
+		}
+	}
+	if len(reconcileIDs) == 0 {
+		t.Fatal("no log line with reconcileID")
+	}
+	for _, id := range reconcileIDs {
+		if id != reconcileIDs[0] {
+			t.Errorf("reconcileID changed within one reconcile: %q and %q", reconcileIDs[0], id)
+		}
+	}
+	if !clusterLogged {
+		t.Error("no status update log after the cluster became running")
+	}
+}
+
+func TestProcessNextItemRequeuesOnError(t *testing.T) {
+	c, provider, _ := newTestController(t, Options{}, newKluster("kluster"))
+	provider.createErr = context.DeadlineExceeded
+	logs := captureLogs(t)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		c.wq.Add("default/kluster")
+		c.processNextItem()
+		if got := c.wq.NumRequeues("default/kluster"); got != attempt {
+			t.Fatalf("NumRequeues = %d after attempt %d", got, attempt)
+		}
+	}
+	// 두 번째 시도의 로그에는 attempt 2가 붙는다.
+	var attempts []float64
+	for _, line := range logs() {
+		if line["msg"] == "calling DigitalOcean create API" {
+			attempts = append(attempts, line["attempt"].(float64))
+		}
+	}
+	if len(attempts) != 2 || attempts[0] != 1 || attempts[1] != 2 {
+		t.Errorf("attempts of the create error logs = %v, want [1 2]", attempts)
+	}
+}
+
+var (
+	spanRecorder     = tracetest.NewSpanRecorder()
+	spanRecorderOnce sync.Once
+)
+
+// recordSpans: global TracerProvider를 span recorder로 바꾸고, 지금까지 끝난 span 수를 리턴한다.
+// otel은 처음 설정된 provider에만 위임하므로 한 번만 설정하고, 테스트는 이후에 끝난 span만 본다.
+func recordSpans() int {
+	spanRecorderOnce.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	})
+	return len(spanRecorder.Ended())
+}
+
+func TestProcessNextItemSpan(t *testing.T) {
+	c, provider, _ := newTestController(t, Options{}, newKluster("kluster"))
+	provider.createErr = errors.New("boom")
+	start := recordSpans()
+
+	c.wq.Add("default/kluster")
+	c.processNextItem()
+
+	var reconcile sdktrace.ReadOnlySpan
+	for _, span := range spanRecorder.Ended()[start:] {
+		if span.Name() == "kluster.reconcile" {
+			reconcile = span
+		}
+	}
+	if reconcile == nil {
+		t.Fatal("no kluster.reconcile span")
+	}
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range reconcile.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	for key, want := range map[attribute.Key]string{
+		"k8s.namespace.name":  "default",
+		"kluster.name":        "kluster",
+		tracing.KlusterUIDKey: "uid-kluster",
+	} {
+		if got := attributes[key].AsString(); got != want {
+			t.Errorf("attribute %s = %q, want %q", key, got, want)
+		}
+	}
+	if got := attributes["kluster.attempt"].AsInt64(); got != 1 {
+		t.Errorf("attribute kluster.attempt = %d, want 1", got)
+	}
+	if got := reconcile.Status(); got.Code != codes.Error || got.Description != "boom" {
+		t.Errorf("status of the reconcile span = %+v, want Error boom", got)
+	}
+}
+
+// runningKluster: 이미 running인 DigitalOcean 클러스터 cluster-1을 가진 Kluster
+func runningKluster(name string) *v1alpha1.Kluster {
+	kluster := newKluster(name)
+	kluster.Status.KlusterID = "cluster-1"
+	kluster.Status.Progress = "running"
+	return kluster
+}
+
+func TestReconcileRefreshesStatus(t *testing.T) {
+	c, provider, _ := newTestController(t, Options{}, runningKluster("kluster"))
+	createdAt := metav1.Now()
+	provider.observed = v1alpha1.KlusterStatus{
+		Progress:      "degraded",
+		Message:       "node pool is unhealthy",
+		Version:       "1.25.4-do.0",
+		Endpoint:      "https://cluster-1.k8s.ondigitalocean.com",
+		IPv4:          "10.0.0.1",
+		VPCUUID:       "vpc-1",
+		ClusterSubnet: "10.244.0.0/16",
+		ServiceSubnet: "10.245.0.0/16",
+		CreatedAt:     &createdAt,
+		Nodes:         5,
+		NodePools: []v1alpha1.NodePoolStatus{
+			{Name: "default-pool", ID: "pool-id-0", Count: 2},
+			{Name: "batch", ID: "pool-id-1", Count: 3},
+		},
+	}
+
+	if err := c.reconcile(context.Background(), runningKluster("kluster")); err != nil {
+		t.Fatal(err)
+	}
+	if provider.called("Create") {
+		t.Error("Create was called for a Kluster with a cluster ID")
+	}
+	status := getKluster(t, c, "kluster").Status
+	if status.KlusterID != "cluster-1" || status.Progress != "degraded" || status.Message != "node pool is unhealthy" ||
+		status.Version != "1.25.4-do.0" || status.Endpoint != provider.observed.Endpoint || status.IPv4 != "10.0.0.1" ||
+		status.VPCUUID != "vpc-1" || status.ClusterSubnet != "10.244.0.0/16" || status.ServiceSubnet != "10.245.0.0/16" ||
+		status.CreatedAt == nil || status.Nodes != 5 || len(status.NodePools) != 2 {
+		t.Errorf("status = %+v", status)
+	}
+	// scale subresource의 replicas / selector는 primary node pool(default-pool) 기준.
+	if status.Replicas != 2 || status.Selector != digitalocean.NodePoolLabel+"=default-pool" {
+		t.Errorf("replicas = %d, selector = %q", status.Replicas, status.Selector)
+	}
+	if status.ObservedGeneration != 1 || status.AppliedNodes != 3 {
+		t.Errorf("observedGeneration = %d, appliedNodes = %d, want 1, 3", status.ObservedGeneration, status.AppliedNodes)
+	}
+}
+
+// adopt할 DigitalOcean 클러스터 ID. annotation 값은 UUID여야 한다.
+const adoptID = "9b2a7c4e-1f3d-4e8a-b6c5-0d7e8f9a1b2c"
+
+func adoptingKluster(name, id string) *v1alpha1.Kluster {
+	kluster := newKluster(name)
+	kluster.Annotations = map[string]string{v1alpha1.AdoptClusterIDAnnotation: id}
+	return kluster
+}
+
+func TestReconcileAdoptsCluster(t *testing.T) {
+	c, provider, recorder := newTestController(t, Options{}, adoptingKluster("kluster", adoptID))
+
+	if err := c.reconcile(context.Background(), adoptingKluster("kluster", adoptID)); err != nil {
+		t.Fatal(err)
+	}
+	if provider.called("Create") || !provider.called("Update "+adoptID) {
+		t.Errorf("calls = %v, want Update of the adopted cluster without Create", provider.calls)
+	}
+	kluster := getKluster(t, c, "kluster")
+	if kluster.Status.KlusterID != adoptID {
+		t.Errorf("klusterID = %q, want %q", kluster.Status.KlusterID, adoptID)
+	}
+	if condition := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionAdopted); condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != "SpecMatched" {
+		t.Errorf("Adopted condition = %+v", condition)
+	}
+	if !hasEvent(recorder, "ClusterAdopted") {
+		t.Error("no ClusterAdopted event")
+	}
+}
+
+func TestReconcileDoesNotAdoptMismatchedCluster(t *testing.T) {
+	c, provider, recorder := newTestController(t, Options{}, adoptingKluster("kluster", adoptID))
+	provider.drift = digitalocean.ClusterDrift{
+		Correctable:   []string{"version: spec latest, actual 1.25.4-do.0"},
+		Uncorrectable: []string{"region: spec nyc1, actual sfo3"},
+	}
+
+	if err := c.reconcile(context.Background(), adoptingKluster("kluster", adoptID)); err != nil {
+		t.Fatal(err)
+	}
+	// spec이 수정될 때까지 클러스터를 만들거나 변경하지 않는다.
+	if provider.called("Create") || provider.called("Update") {
+		t.Errorf("calls = %v, want neither Create nor Update", provider.calls)
+	}
+	kluster := getKluster(t, c, "kluster")
+	if kluster.Status.KlusterID != "" {
+		t.Errorf("klusterID = %q, want none", kluster.Status.KlusterID)
+	}
+	condition := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionAdopted)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "SpecMismatch" ||
+		condition.Message != "version: spec latest, actual 1.25.4-do.0; region: spec nyc1, actual sfo3" {
+		t.Errorf("Adopted condition = %+v", condition)
+	}
+	if !hasEvent(recorder, "ClusterAdoptionFailed") {
+		t.Error("no ClusterAdoptionFailed event")
+	}
+}
+
+func TestHandleUpdate(t *testing.T) {
+	withAnnotations := func(annotations map[string]string) *v1alpha1.Kluster {
+		kluster := newKluster("kluster")
+		kluster.Annotations = annotations
+		return kluster
+	}
+	tests := []struct {
+		name        string
+		old         *v1alpha1.Kluster
+		update      func(k *v1alpha1.Kluster)
+		wantEnqueue bool
+	}{
+		{name: "resync", old: newKluster("kluster"), wantEnqueue: true},
+		{name: "status only", old: newKluster("kluster"), update: func(k *v1alpha1.Kluster) { k.ResourceVersion = "2" }},
+		{name: "spec", old: newKluster("kluster"), update: func(k *v1alpha1.Kluster) { k.ResourceVersion, k.Generation = "2", 2 }, wantEnqueue: true},
+		{name: "deleting", old: newKluster("kluster"), update: func(k *v1alpha1.Kluster) {
+			now := metav1.Now()
+			k.ResourceVersion, k.DeletionTimestamp = "2", &now
+		}, wantEnqueue: true},
+		{name: "paused", old: newKluster("kluster"), update: func(k *v1alpha1.Kluster) {
+			k.ResourceVersion, k.Annotations = "2", map[string]string{v1alpha1.PausedAnnotation: "true"}
+		}, wantEnqueue: true},
+		{name: "adopt cluster ID", old: newKluster("kluster"), update: func(k *v1alpha1.Kluster) {
+			k.ResourceVersion, k.Annotations = "2", map[string]string{v1alpha1.AdoptClusterIDAnnotation: adoptID}
+		}, wantEnqueue: true},
+		{name: "other annotation", old: withAnnotations(map[string]string{v1alpha1.AdoptClusterIDAnnotation: adoptID}), update: func(k *v1alpha1.Kluster) {
+			k.ResourceVersion, k.Annotations = "2", map[string]string{v1alpha1.AdoptClusterIDAnnotation: adoptID, "note": "x"}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, _ := newTestController(t, Options{})
+			updated := tt.old.DeepCopy()
+			if tt.update != nil {
+				tt.update(updated)
+			}
+			c.handleUpdate(tt.old, updated)
+			if got := c.wq.Len() == 1; got != tt.wantEnqueue {
+				t.Errorf("enqueued = %v, want %v", got, tt.wantEnqueue)
+			}
+		})
+	}
+}
+
+// deletingKluster: 삭제가 시작된, cluster-1을 가진 Kluster
+func deletingKluster(name string) *v1alpha1.Kluster {
+	kluster := runningKluster(name)
+	now := metav1.Now()
+	kluster.DeletionTimestamp = &now
+	kluster.Finalizers = []string{v1alpha1.KlusterFinalizer}
+	return kluster
+}
+
+func TestFinalize(t *testing.T) {
+	retainTemplate := &v1alpha1.KlusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "retain", Namespace: "default"},
+		Spec:       v1alpha1.KlusterTemplateSpec{DeletionPolicy: v1alpha1.DeletionPolicyRetain},
+	}
+	tests := []struct {
+		name          string
+		kluster       func(k *v1alpha1.Kluster)
+		objects       []runtime.Object
+		deleteErr     error
+		wantErr       bool
+		wantCalls     []string
+		wantFinalizer bool
+		wantEvent     string
+	}{
+		{
+			name:      "delete",
+			wantCalls: []string{"Delete cluster-1"},
+			wantEvent: "ClusterDeleted",
+		},
+		{
+			name:      "retain",
+			kluster:   func(k *v1alpha1.Kluster) { k.Spec.DeletionPolicy = v1alpha1.DeletionPolicyRetain },
+			wantCalls: []string{"Release cluster-1"},
+			wantEvent: "ClusterRetained",
+		},
+		{
+			// deletionPolicy는 template에서 올 수 있다.
+			name:      "retain from template",
+			kluster:   func(k *v1alpha1.Kluster) { k.Spec.Template = "retain" },
+			objects:   []runtime.Object{retainTemplate},
+			wantCalls: []string{"Release cluster-1"},
+			wantEvent: "ClusterRetained",
+		},
+		{
+			name:          "template not found",
+			kluster:       func(k *v1alpha1.Kluster) { k.Spec.Template = "retain" },
+			wantFinalizer: true,
+			wantEvent:     "TemplateNotFound",
+		},
+		{
+			name:          "delete failed",
+			deleteErr:     errors.New("boom"),
+			wantErr:       true,
+			wantCalls:     []string{"Delete cluster-1"},
+			wantFinalizer: true,
+			wantEvent:     "ClusterDeletionFailed",
+		},
+		{
+			name:          "retain failed",
+			kluster:       func(k *v1alpha1.Kluster) { k.Spec.DeletionPolicy = v1alpha1.DeletionPolicyRetain },
+			deleteErr:     errors.New("boom"),
+			wantErr:       true,
+			wantCalls:     []string{"Release cluster-1"},
+			wantFinalizer: true,
+			wantEvent:     "ClusterRetainFailed",
+		},
+		{
+			// 생성 요청 전에 삭제된 Kluster는 정리할 클러스터가 없다.
+			name:    "no cluster ID",
+			kluster: func(k *v1alpha1.Kluster) { k.Status.KlusterID = "" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kluster := deletingKluster("kluster")
+			if tt.kluster != nil {
+				tt.kluster(kluster)
+			}
+			c, provider, recorder := newTestController(t, Options{}, append(tt.objects, kluster)...)
+			provider.deleteErr = tt.deleteErr
+
+			err := c.finalize(context.Background(), kluster)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("finalize() = %v, want error %v", err, tt.wantErr)
+			}
+			if !equalStrings(provider.calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", provider.calls, tt.wantCalls)
+			}
+			if got := len(getKluster(t, c, "kluster").Finalizers) > 0; got != tt.wantFinalizer {
+				t.Errorf("finalizer kept = %v, want %v", got, tt.wantFinalizer)
+			}
+			if tt.wantEvent != "" && !hasEvent(recorder, tt.wantEvent) {
+				t.Errorf("no %s event", tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestFinalizeWithoutFinalizer(t *testing.T) {
+	kluster := deletingKluster("kluster")
+	kluster.Finalizers = nil
+	c, provider, _ := newTestController(t, Options{}, kluster)
+
+	if err := c.finalize(context.Background(), kluster); err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.calls) != 0 {
+		t.Errorf("calls = %v, want none", provider.calls)
+	}
+}
+
+func equalStrings(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// 클러스터를 만들기 전에 finalizer를 붙여서, 삭제될 때 deletionPolicy를 적용할 수 있도록 한다.
+func TestReconcileAddsFinalizer(t *testing.T) {
+	c, _, _ := newTestController(t, Options{}, newKluster("kluster"))
+
+	if err := c.reconcile(context.Background(), newKluster("kluster")); err != nil {
+		t.Fatal(err)
+	}
+	if finalizers := getKluster(t, c, "kluster").Finalizers; !equalStrings(finalizers, []string{v1alpha1.KlusterFinalizer}) {
+		t.Errorf("finalizers = %v, want [%s]", finalizers, v1alpha1.KlusterFinalizer)
+	}
+}
+
+func paused(kluster *v1alpha1.Kluster) *v1alpha1.Kluster {
+	if kluster.Annotations == nil {
+		kluster.Annotations = map[string]string{}
+	}
+	kluster.Annotations[v1alpha1.PausedAnnotation] = "true"
+	return kluster
+}
+
+func TestReconcilePaused(t *testing.T) {
+	tests := []struct {
+		name      string
+		kluster   *v1alpha1.Kluster
+		wantCalls []string
+	}{
+		{name: "not created", kluster: paused(newKluster("kluster"))},
+		// pause 중에도 status는 갱신하지만 클러스터를 변경하는 호출은 하지 않는다.
+		{name: "running", kluster: paused(runningKluster("kluster")), wantCalls: []string{"Drift cluster-1", "ObservedStatus cluster-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, provider, recorder := newTestController(t, Options{}, tt.kluster)
+			provider.observed.Progress = "running"
+			provider.observed.Version = "1.25.4-do.0"
+
+			if err := c.reconcile(context.Background(), tt.kluster.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+			if !equalStrings(provider.calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", provider.calls, tt.wantCalls)
+			}
+			kluster := getKluster(t, c, "kluster")
+			if condition := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionPaused); condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != "PausedByAnnotation" {
+				t.Errorf("Paused condition = %+v", condition)
+			}
+			if tt.wantCalls != nil && kluster.Status.Version != "1.25.4-do.0" {
+				t.Errorf("version = %q, status was not refreshed", kluster.Status.Version)
+			}
+			if !hasEvent(recorder, "ReconciliationPaused") {
+				t.Error("no ReconciliationPaused event")
+			}
+		})
+	}
+}
+
+func TestReconcileResumed(t *testing.T) {
+	kluster := runningKluster("kluster")
+	kluster.Status.Conditions = []metav1.Condition{{Type: v1alpha1.ConditionPaused, Status: metav1.ConditionTrue, Reason: "PausedByAnnotation"}}
+	c, provider, recorder := newTestController(t, Options{}, kluster)
+
+	if err := c.reconcile(context.Background(), kluster.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+	if !provider.called("Update cluster-1") {
+		t.Errorf("calls = %v, want Update after resuming", provider.calls)
+	}
+	if condition := meta.FindStatusCondition(getKluster(t, c, "kluster").Status.Conditions, v1alpha1.ConditionPaused); condition == nil || condition.Status != metav1.ConditionFalse {
+		t.Errorf("Paused condition = %+v", condition)
+	}
+	if !hasEvent(recorder, "ReconciliationResumed") {
+		t.Error("no ReconciliationResumed event")
+	}
+}
+
+// pause 중에는 삭제도 미루고 finalizer를 유지한다.
+func TestFinalizePaused(t *testing.T) {
+	kluster := paused(deletingKluster("kluster"))
+	c, provider, _ := newTestController(t, Options{}, kluster)
+
+	if err := c.finalize(context.Background(), kluster.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.calls) != 0 {
+		t.Errorf("calls = %v, want none", provider.calls)
+	}
+	if finalizers := getKluster(t, c, "kluster").Finalizers; len(finalizers) == 0 {
+		t.Error("finalizer was removed while paused")
+	}
+}
+
+func TestReconcileDryRun(t *testing.T) {
+	quota := &v1alpha1.KlusterQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "budget", Namespace: "default"},
+		Spec:       v1alpha1.KlusterQuotaSpec{MonthlyBudget: "100"},
+	}
+	c, provider, recorder := newTestController(t, Options{DryRun: true}, newKluster("kluster"), quota)
+	provider.createErr = digitalocean.ErrDryRun
+	provider.cost = 54
+
+	if err := c.reconcile(context.Background(), newKluster("kluster")); err != nil {
+		t.Fatalf("reconcile() = %v, want nil for a dry-run create", err)
+	}
+	// 생성되지 않았으므로 생성 이후 단계는 진행하지 않고, 예산도 예약하지 않는다.
+	if !equalStrings(provider.calls, []string{"MonthlyCost kluster", "Create kluster"}) {
+		t.Errorf("calls = %v", provider.calls)
+	}
+	if status := getKluster(t, c, "kluster").Status; status.KlusterID != "" || status.EstimatedMonthlyCost != "" {
+		t.Errorf("klusterID = %q, estimatedMonthlyCost = %q, want neither", status.KlusterID, status.EstimatedMonthlyCost)
+	}
+	for _, event := range events(recorder) {
+		if strings.Contains(event, " ClusterCreation") {
+			t.Errorf("unexpected event %q", event)
+		}
+	}
+}
+
+// dry-run에서는 클러스터가 정리되지 않았으므로 finalizer를 유지한다.
+func TestFinalizeDryRun(t *testing.T) {
+	for _, deletionPolicy := range []v1alpha1.DeletionPolicy{v1alpha1.DeletionPolicyDelete, v1alpha1.DeletionPolicyRetain} {
+		t.Run(string(deletionPolicy), func(t *testing.T) {
+			kluster := deletingKluster("kluster")
+			kluster.Spec.DeletionPolicy = deletionPolicy
+			c, provider, _ := newTestController(t, Options{DryRun: true}, kluster)
+			provider.deleteErr = digitalocean.ErrDryRun
+
+			if err := c.finalize(context.Background(), kluster.DeepCopy()); err != nil {
+				t.Fatalf("finalize() = %v, want nil", err)
+			}
+			if finalizers := getKluster(t, c, "kluster").Finalizers; len(finalizers) == 0 {
+				t.Error("finalizer was removed in dry-run")
+			}
+		})
+	}
+}
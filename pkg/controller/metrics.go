@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"sync"
+)
+
+// orphan sweeper metric. main.go의 --metrics-addr에서 /metrics로 노출된다.
+var (
+	orphanedClusters = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Subsystem:      "kluster",
+		Name:           "orphaned_clusters",
+		Help:           "Number of DigitalOcean clusters tagged by this management cluster without a matching Kluster, found by the last sweep.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"reason"})
+	orphanSweepErrors = metrics.NewCounter(&metrics.CounterOpts{
+		Subsystem:      "kluster",
+		Name:           "orphan_sweep_errors_total",
+		Help:           "Number of DigitalOcean accounts the orphan sweeper failed to list clusters from.",
+		StabilityLevel: metrics.ALPHA,
+	})
+	orphanDeletions = metrics.NewCounterVec(&metrics.CounterOpts{
+		Subsystem:      "kluster",
+		Name:           "orphaned_cluster_deletions_total",
+		Help:           "Number of orphaned DigitalOcean cluster deletions by result (success, error, dry_run).",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"result"})
+)
+
+var registerOnce sync.Once
+
+func registerMetrics() {
+	registerOnce.Do(func() {
+		legacyregistry.MustRegister(orphanedClusters, orphanSweepErrors, orphanDeletions)
+	})
+}
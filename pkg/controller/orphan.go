@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/digitalocean"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OrphanOptions: orphan sweeper 설정
+type OrphanOptions struct {
+	// sweep 주기. 0이면 sweeper를 돌리지 않는다.
+	Interval time.Duration
+	// Kluster가 참조하지 않는 DigitalOcean 계정도 sweep하기 위한 token secret 목록("<namespace>/<name>"). token은 "token" key에서 읽는다.
+	TokenSecrets []string
+	// true면 grace period가 지난 orphan 클러스터를 삭제한다. false면 metric / 이벤트로만 알린다.
+	Delete      bool
+	GracePeriod time.Duration
+}
+
+const (
+	// owner tag의 Kluster가 없음
+	orphanReasonKlusterNotFound = "KlusterNotFound"
+	// owner Kluster는 있지만 status에 다른 cluster ID가 기록되어 있음. 생성 후 cluster ID를 기록하지 못해서 중복 생성된 클러스터.
+	orphanReasonDuplicate = "Duplicate"
+)
+
+var orphanReasons = []string{orphanReasonKlusterNotFound, orphanReasonDuplicate}
+
+// sweepOrphans: owner tag가 붙은 DigitalOcean 클러스터 중 대응하는 Kluster가 없는 클러스터를 찾아서 metric / 이벤트로 알리고,
+// 삭제가 켜져 있으면 처음 발견된 뒤 grace period가 지난 클러스터를 삭제한다.
+// sweep 대상 계정은 Kluster들이 참조하는 token secret과 OrphanOptions.TokenSecrets.
+func (c *Controller) sweepOrphans() {
+	logger := klog.LoggerWithName(klog.Background(), "orphan-sweeper")
+	ctx := klog.NewContext(context.Background(), logger)
+	ctx, span := tracer.Start(ctx, "kluster.sweepOrphans")
+	defer span.End()
+
+	klusters, err := c.kLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "listing Klusters")
+		return
+	}
+	owners := map[types.UID]*v1alpha1.Kluster{}
+	// 같은 secret이라도 key가 다르면 다른 계정일 수 있으므로 (secret, key) 단위로 sweep한다.
+	secrets := map[digitalocean.TokenSecret]bool{}
+	// Kluster가 같은 secret을 참조하면 한 번만 sweep하도록 Kluster와 같은 기본 key로 채운다.
+	for _, ref := range c.opts.Orphans.TokenSecrets {
+		secrets[digitalocean.TokenSecret{Ref: ref, Key: v1alpha1.DefaultTokenSecretKey}] = true
+	}
+	for _, kluster := range klusters {
+		owners[kluster.UID] = kluster
+		// webhook을 거치지 않았거나 template을 참조하는 object는 tokenSecret이 비어 있을 수 있다.
+		resolved, _, err := c.resolveSpec(kluster)
+		if err != nil {
+			logger.V(2).Info("skipping token secret of Kluster", "kluster", klog.KObj(kluster), "err", err)
+			continue
+		}
+		secrets[digitalocean.KlusterTokenSecret(resolved)] = true
+	}
+
+	failed := false
+	found := sets.NewString()
+	counts := map[string]int{}
+	for _, secret := range sortedTokenSecrets(secrets) {
+		clusters, err := c.do.ManagedClusters(ctx, secret)
+		if err != nil {
+			logger.Error(err, "listing managed clusters", "tokenSecret", secret.Ref, "key", secret.Key)
+			orphanSweepErrors.Inc()
+			failed = true
+			continue
+		}
+		for _, cluster := range clusters {
+			// 여러 secret이 같은 계정의 token일 수 있다.
+			if found.Has(cluster.ID) {
+				continue
+			}
+			reason, owner := orphanReason(cluster, owners)
+			if reason == "" {
+				continue
+			}
+			found.Insert(cluster.ID)
+			counts[reason]++
+			c.handleOrphan(ctx, secret, cluster, reason, owner)
+		}
+	}
+
+	// 조회에 실패한 계정의 orphan까지 지우지 않도록, 모든 계정을 조회한 경우에만 더 이상 orphan이 아닌 클러스터를 잊는다.
+	if !failed {
+		for id := range c.orphans {
+			if !found.Has(id) {
+				delete(c.orphans, id)
+			}
+		}
+	}
+	for _, reason := range orphanReasons {
+		orphanedClusters.WithLabelValues(reason).Set(float64(counts[reason]))
+	}
+	span.SetAttributes(attribute.Int("kluster.orphaned_clusters", found.Len()))
+	logger.V(2).Info("orphan sweep finished", "accounts", len(secrets), "orphans", found.Len())
+}
+
+// orphan이면 사유와, 있으면 owner Kluster를 리턴한다. 생성 중이라 status에 cluster ID가 아직 없는 Kluster의 클러스터는 orphan이 아니다.
+func orphanReason(cluster digitalocean.ManagedCluster, owners map[types.UID]*v1alpha1.Kluster) (string, *v1alpha1.Kluster) {
+	owner, ok := owners[cluster.UID]
+	if !ok {
+		return orphanReasonKlusterNotFound, nil
+	}
+	if id := owner.Status.KlusterID; id != "" && id != cluster.ID {
+		return orphanReasonDuplicate, owner
+	}
+	return "", nil
+}
+
+// 계정을 항상 같은 순서로 sweep하도록 정렬한다.
+func sortedTokenSecrets(secrets map[digitalocean.TokenSecret]bool) []digitalocean.TokenSecret {
+	out := make([]digitalocean.TokenSecret, 0, len(secrets))
+	for secret := range secrets {
+		out = append(out, secret)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Ref != out[j].Ref {
+			return out[i].Ref < out[j].Ref
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+func (c *Controller) handleOrphan(ctx context.Context, secret digitalocean.TokenSecret, cluster digitalocean.ManagedCluster, reason string, owner *v1alpha1.Kluster) {
+	logger := klog.FromContext(ctx).WithValues(
+		"clusterID", cluster.ID,
+		"cluster", cluster.Name,
+		"owner", klog.KRef(cluster.Namespace, cluster.KlusterName),
+		"ownerUID", cluster.UID,
+		"reason", reason,
+	)
+	// owner Kluster가 있으면 Kluster에, 없으면 클러스터가 속한 계정의 token secret에 이벤트를 남긴다.
+	var target runtime.Object = secretReference(secret.Ref)
+	if owner != nil {
+		target = owner
+	}
+	description := fmt.Sprintf("Digital Ocean cluster %s (%s, region %s, owner %s/%s uid %s)", cluster.Name, cluster.ID, cluster.Region, cluster.Namespace, cluster.KlusterName, cluster.UID)
+
+	firstSeen, ok := c.orphans[cluster.ID]
+	if !ok {
+		firstSeen = time.Now()
+		c.orphans[cluster.ID] = firstSeen
+		logger.Info("found orphaned cluster")
+		c.recorder.Eventf(target, corev1.EventTypeWarning, "OrphanedCluster", "%s is orphaned: %s.", description, reason)
+	}
+	if !c.opts.Orphans.Delete {
+		return
+	}
+	if remaining := c.opts.Orphans.GracePeriod - time.Since(firstSeen); remaining > 0 {
+		logger.V(2).Info("waiting for grace period before deleting orphaned cluster", "remaining", remaining.Round(time.Second))
+		return
+	}
+
+	err := c.do.DeleteOrphan(ctx, secret, target, cluster.ID)
+	switch {
+	case errors.Is(err, digitalocean.ErrDryRun):
+		logger.Info("dry-run: orphaned cluster was not deleted")
+		orphanDeletions.WithLabelValues("dry_run").Inc()
+	case err != nil:
+		logger.Error(err, "deleting orphaned cluster")
+		orphanDeletions.WithLabelValues("error").Inc()
+		c.recorder.Eventf(target, corev1.EventTypeWarning, "OrphanDeletionFailed", "Deleting %s failed: %v", description, err)
+	default:
+		logger.Info("deleted orphaned cluster")
+		orphanDeletions.WithLabelValues("success").Inc()
+		c.recorder.Eventf(target, corev1.EventTypeNormal, "OrphanDeleted", "%s was deleted after being orphaned for %s.", description, time.Since(firstSeen).Round(time.Second))
+		delete(c.orphans, cluster.ID)
+	}
+}
+
+// "<namespace>/<name>" 형태의 token secret을 이벤트 대상으로 쓰기 위한 reference
+func secretReference(secret string) *corev1.ObjectReference {
+	namespace, name, _ := strings.Cut(secret, "/")
+	return &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Namespace:  namespace,
+		Name:       name,
+	}
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"github.com/inspirit941/kluster/pkg/digitalocean"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sweep 대상: owner가 없는 클러스터, owner의 status와 ID가 다른 중복 클러스터, owner가 관리하는 클러스터
+func managedClusters() []digitalocean.ManagedCluster {
+	return []digitalocean.ManagedCluster{
+		{ID: "orphan", Name: "gone", Namespace: "default", KlusterName: "gone", UID: "uid-gone"},
+		{ID: "duplicate", Name: "kluster", Namespace: "default", KlusterName: "kluster", UID: "uid-kluster"},
+		{ID: "cluster-1", Name: "kluster", Namespace: "default", KlusterName: "kluster", UID: "uid-kluster"},
+	}
+}
+
+func TestSweepOrphans(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        OrphanOptions
+		wantDeleted []string
+		wantEvents  []string
+		wantTracked int
+	}{
+		{
+			name:        "report only",
+			wantEvents:  []string{"Warning OrphanedCluster", "Warning OrphanedCluster"},
+			wantTracked: 2,
+		},
+		{
+			name:        "delete",
+			opts:        OrphanOptions{Delete: true},
+			wantDeleted: []string{"DeleteOrphan orphan", "DeleteOrphan duplicate"},
+			wantEvents:  []string{"Warning OrphanedCluster", "Normal OrphanDeleted", "Warning OrphanedCluster", "Normal OrphanDeleted"},
+		},
+		{
+			// grace period가 지나기 전에는 지우지 않는다.
+			name:        "grace period",
+			opts:        OrphanOptions{Delete: true, GracePeriod: time.Hour},
+			wantEvents:  []string{"Warning OrphanedCluster", "Warning OrphanedCluster"},
+			wantTracked: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, provider, recorder := newTestController(t, Options{Orphans: tt.opts}, runningKluster("kluster"))
+			provider.managed = managedClusters()
+
+			c.sweepOrphans()
+
+			var deleted []string
+			for _, call := range provider.calls {
+				if strings.HasPrefix(call, "DeleteOrphan ") {
+					deleted = append(deleted, call)
+				}
+			}
+			if !equalStrings(deleted, tt.wantDeleted) {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			var got []string
+			for _, event := range events(recorder) {
+				fields := strings.Fields(event)
+				got = append(got, fields[0]+" "+fields[1])
+			}
+			if !equalStrings(got, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", got, tt.wantEvents)
+			}
+			if len(c.orphans) != tt.wantTracked {
+				t.Errorf("tracked orphans = %v, want %d", c.orphans, tt.wantTracked)
+			}
+		})
+	}
+}
+
+// 이미 알린 orphan은 다시 알리지 않고, 더 이상 orphan이 아닌 클러스터는 잊는다.
+func TestSweepOrphansAgain(t *testing.T) {
+	c, provider, recorder := newTestController(t, Options{}, runningKluster("kluster"))
+	provider.managed = managedClusters()
+	c.sweepOrphans()
+	events(recorder)
+
+	provider.managed = managedClusters()[1:]
+	c.sweepOrphans()
+	if got := events(recorder); len(got) != 0 {
+		t.Errorf("events = %q, want none", got)
+	}
+	if _, ok := c.orphans["orphan"]; ok || len(c.orphans) != 1 {
+		t.Errorf("tracked orphans = %v, want only duplicate", c.orphans)
+	}
+}
+
+// Kluster가 참조하는 token secret과 설정된 token secret을 한 번씩, 정렬된 순서로 sweep한다.
+func TestSweepOrphansTokenSecrets(t *testing.T) {
+	opts := Options{Orphans: OrphanOptions{TokenSecrets: []string{"ops/dotoken", "default/dosecret"}}}
+	c, provider, _ := newTestController(t, opts, newKluster("kluster"), newKluster("other"))
+
+	c.sweepOrphans()
+	want := []string{"ManagedClusters default/dosecret", "ManagedClusters ops/dotoken"}
+	if !equalStrings(provider.calls, want) {
+		t.Errorf("calls = %v, want %v", provider.calls, want)
+	}
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1/validation"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// checkPolicy: template과 합친 kluster spec이 namespace의 KlusterPolicy를 지키는지 확인해서 PolicyViolation condition에 기록한다.
+// webhook을 거치지 않았거나 policy가 나중에 만들어진 경우를 위해 controller에서도 확인한다. 어기면 true를 리턴함.
+// 이미 있는 클러스터는 지금 반영된 spec의 노드 수보다 늘리지 않는 한 namespace 노드 수 한도로 막지 않는다.
+func (c *Controller) checkPolicy(ctx context.Context, kluster *v1alpha1.Kluster, templateGeneration int64) (bool, error) {
+	errs, err := c.policies.Check(kluster, &kluster.Spec, appliedNodes(kluster, templateGeneration))
+	if err != nil {
+		return false, err
+	}
+	condition := metav1.Condition{
+		Type:    v1alpha1.ConditionPolicyViolation,
+		Status:  metav1.ConditionFalse,
+		Reason:  "PolicySatisfied",
+		Message: "Kluster satisfies the KlusterPolicies of the namespace",
+	}
+	if len(errs) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "PolicyViolated"
+		condition.Message = errs.ToAggregate().Error()
+		// resync마다 같은 이벤트가 쌓이지 않도록 내용이 바뀔 때만 남긴다.
+		if previous := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionPolicyViolation); previous == nil || previous.Message != condition.Message {
+			klog.FromContext(ctx).Info("Kluster violates KlusterPolicy", "errors", condition.Message)
+			c.recorder.Event(kluster, corev1.EventTypeWarning, "PolicyViolation", condition.Message)
+		}
+	}
+	return len(errs) > 0, c.setCondition(ctx, kluster, condition)
+}
+
+// appliedNodes: DigitalOcean 클러스터에 반영된 spec의 노드 수. policy.Checker가 spec을 세는 방식(autoscale pool은 maxNodes,
+// schedule이 있으면 가장 큰 count)과 같아야 하므로 실제 노드 수가 아니라 validation.KlusterNodes로 센다.
+// spec이 이미 반영된 상태면 지금 spec으로, 아직 반영 전이면 반영할 때 기록해둔 status.appliedNodes를 사용한다.
+func appliedNodes(kluster *v1alpha1.Kluster, templateGeneration int64) int {
+	if kluster.Status.KlusterID == "" {
+		return 0
+	}
+	if kluster.Generation == kluster.Status.ObservedGeneration && templateGeneration == kluster.Status.ObservedTemplateGeneration {
+		return validation.KlusterNodes(&kluster.Spec)
+	}
+	return int(kluster.Status.AppliedNodes)
+}
+
+// policy가 생성 / 변경 / 삭제되면 namespace의 모든 Kluster를 다시 검사한다.
+func (c *Controller) handlePolicy(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	policy, ok := obj.(*v1alpha1.KlusterPolicy)
+	if !ok {
+		return
+	}
+	klusters, err := c.kLister.Klusters(policy.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "listing Klusters of KlusterPolicy namespace", "policy", klog.KObj(policy))
+		return
+	}
+	klog.V(4).InfoS("KlusterPolicy changed, enqueueing Klusters", "policy", klog.KObj(policy), "klusters", len(klusters))
+	for _, kluster := range klusters {
+		c.enqueue(kluster)
+	}
+}
+
+func (c *Controller) handlePolicyUpdate(oldObj, newObj interface{}) {
+	old, ok := oldObj.(*v1alpha1.KlusterPolicy)
+	if !ok {
+		return
+	}
+	policy, ok := newObj.(*v1alpha1.KlusterPolicy)
+	if !ok || old.Generation == policy.Generation {
+		return
+	}
+	c.handlePolicy(policy)
+}
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"context"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"testing"
+)
+
+// regionPolicy: Kluster의 기본 region을 허용하지 않는 KlusterPolicy
+func regionPolicy() *v1alpha1.KlusterPolicy {
+	return &v1alpha1.KlusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "regions", Namespace: "default", Generation: 1},
+		Spec:       v1alpha1.KlusterPolicySpec{AllowedRegions: []string{"nowhere"}},
+	}
+}
+
+func TestReconcilePolicyViolation(t *testing.T) {
+	c, provider, recorder := newTestController(t, Options{}, newKluster("kluster"), regionPolicy())
+
+	if err := c.reconcile(context.Background(), newKluster("kluster")); err != nil {
+		t.Fatal(err)
+	}
+	if provider.called("Create") {
+		t.Error("Create was called for a Kluster violating KlusterPolicy")
+	}
+	kluster := getKluster(t, c, "kluster")
+	condition := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionPolicyViolation)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != "PolicyViolated" {
+		t.Errorf("PolicyViolation condition = %+v", condition)
+	}
+	if !hasEvent(recorder, "PolicyViolation") {
+		t.Error("no PolicyViolation event")
+	}
+
+	// 같은 위반으로 다시 reconcile하면 이벤트를 또 남기지 않는다.
+	if err := c.reconcile(context.Background(), kluster); err != nil {
+		t.Fatal(err)
+	}
+	if hasEvent(recorder, "PolicyViolation") {
+		t.Error("PolicyViolation event was recorded again for the same violation")
+	}
+}
+
+// 이미 있는 클러스터는 policy를 어겨도 status는 갱신하지만 spec을 반영하지 않는다.
+func TestReconcilePolicyViolationRefreshesStatus(t *testing.T) {
+	c, provider, _ := newTestController(t, Options{}, runningKluster("kluster"), regionPolicy())
+	provider.observed = v1alpha1.KlusterStatus{Progress: "running", Nodes: 3}
+
+	if err := c.reconcile(context.Background(), runningKluster("kluster")); err != nil {
+		t.Fatal(err)
+	}
+	if !provider.called("ObservedStatus cluster-1") {
+		t.Error("status was not refreshed")
+	}
+	if provider.called("Update") || provider.called("Create") {
+		t.Errorf("calls = %v, want no Create / Update", provider.calls)
+	}
+	status := getKluster(t, c, "kluster").Status
+	if status.Nodes != 3 {
+		t.Errorf("nodes = %d, want 3", status.Nodes)
+	}
+	if condition := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionPolicyViolation); condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Errorf("PolicyViolation condition = %+v", condition)
+	}
+}
+
+func TestReconcilePolicyMaxNodes(t *testing.T) {
+	policy := &v1alpha1.KlusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "nodes", Namespace: "default", Generation: 1},
+		Spec:       v1alpha1.KlusterPolicySpec{MaxNodesPerNamespace: pointer.Int32(3)},
+	}
+	tests := []struct {
+		name       string
+		kluster    func() *v1alpha1.Kluster
+		wantUpdate bool
+	}{
+		// spec이 반영된 클러스터는 노드를 늘리지 않았으므로 한도 안이다.
+		{name: "applied", kluster: func() *v1alpha1.Kluster {
+			kluster := runningKluster("kluster")
+			kluster.Status.ObservedGeneration = 1
+			return kluster
+		}, wantUpdate: true},
+		// 반영된 3개에서 4개로 늘리면 한도를 넘는다.
+		{name: "scale up", kluster: func() *v1alpha1.Kluster {
+			kluster := runningKluster("kluster")
+			kluster.Generation, kluster.Status.ObservedGeneration, kluster.Status.AppliedNodes = 2, 1, 3
+			kluster.Spec.NodePools = []v1alpha1.NodePool{{Name: "default-pool", Size: "s-2vcpu-2gb", Count: 4}}
+			return kluster
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, provider, _ := newTestController(t, Options{}, tt.kluster(), policy)
+
+			if err := c.reconcile(context.Background(), tt.kluster()); err != nil {
+				t.Fatal(err)
+			}
+			if got := provider.called("Update cluster-1"); got != tt.wantUpdate {
+				t.Errorf("Update called = %v, want %v", got, tt.wantUpdate)
+			}
+		})
+	}
+}
+
+func TestAppliedNodes(t *testing.T) {
+	kluster := func(id string, generation, observedGeneration int64, appliedNodes int32) *v1alpha1.Kluster {
+		k := newKluster("kluster")
+		k.Generation = generation
+		k.Status.KlusterID = id
+		k.Status.ObservedGeneration = observedGeneration
+		k.Status.ObservedTemplateGeneration = 1
+		k.Status.AppliedNodes = appliedNodes
+		k.Spec.NodePools = []v1alpha1.NodePool{{Name: "default-pool", Size: "s-2vcpu-2gb", Count: 3}}
+		return k
+	}
+	tests := []struct {
+		name               string
+		kluster            *v1alpha1.Kluster
+		templateGeneration int64
+		want               int
+	}{
+		{name: "no cluster", kluster: kluster("", 1, 1, 5), templateGeneration: 1, want: 0},
+		// 반영된 spec이면 지금 spec(3개 노드)으로 센다.
+		{name: "applied", kluster: kluster("cluster-1", 1, 1, 5), templateGeneration: 1, want: 3},
+		{name: "spec changed", kluster: kluster("cluster-1", 2, 1, 5), templateGeneration: 1, want: 5},
+		{name: "template changed", kluster: kluster("cluster-1", 1, 1, 5), templateGeneration: 2, want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := appliedNodes(tt.kluster, tt.templateGeneration); got != tt.want {
+				t.Errorf("appliedNodes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlePolicy(t *testing.T) {
+	other := newKluster("other")
+	other.Namespace = "other"
+	c, _, _ := newTestController(t, Options{}, newKluster("a"), newKluster("b"), other)
+
+	c.handlePolicy(regionPolicy())
+	if got := c.wq.Len(); got != 2 {
+		t.Errorf("enqueued = %d, want 2 Klusters of the policy namespace", got)
+	}
+
+	// generation이 같은 update(status / metadata 변경)는 무시한다.
+	c, _, _ = newTestController(t, Options{}, newKluster("a"))
+	c.handlePolicyUpdate(regionPolicy(), regionPolicy())
+	if got := c.wq.Len(); got != 0 {
+		t.Errorf("enqueued = %d after an update without spec change", got)
+	}
+}
@@ -0,0 +1,341 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	klusterscheme "github.com/inspirit941/kluster/pkg/client/clientset/versioned/scheme"
+	"github.com/inspirit941/kluster/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sort"
+)
+
+// KlusterClaim informer의 index. "<namespace>/<pool name>"으로 pool을 참조하는 claim을 찾는다.
+const poolIndex = "pool"
+
+func indexByPool(obj interface{}) ([]string, error) {
+	claim, ok := obj.(*v1alpha1.KlusterClaim)
+	if !ok {
+		return nil, nil
+	}
+	return []string{claim.Namespace + "/" + claim.Spec.PoolName}, nil
+}
+
+var klusterPoolKind = v1alpha1.SchemeGroupVersion.WithKind("KlusterPool")
+
+func (c *Controller) poolWorker() {
+	for c.processNextQueueItem(c.poolWq, "klusterPool", c.syncPool) {
+	}
+}
+
+// processNextQueueItem: pool / claim queue에서 key를 하나 꺼내서 sync를 실행한다.
+// Kluster queue와 같이 reconcile마다 reconcileID / attempt가 붙은 logger와 span을 만들고, 실패한 key는 backoff 후 다시 처리한다.
+func (c *Controller) processNextQueueItem(wq workqueue.RateLimitingInterface, kind string, sync func(ctx context.Context, ns, name string) error) bool {
+	item, shutDown := wq.Get()
+	if shutDown {
+		return false
+	}
+	defer wq.Done(item)
+
+	key, ok := item.(string)
+	if !ok {
+		klog.InfoS("unexpected item in workqueue", "item", item)
+		wq.Forget(item)
+		return true
+	}
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.ErrorS(err, "splitting key into namespace / name", "key", key)
+		wq.Forget(item)
+		return true
+	}
+
+	reconcileID := uuid.NewUUID()
+	attempt := wq.NumRequeues(item) + 1
+	logger := klog.LoggerWithValues(klog.Background(),
+		kind, klog.KRef(ns, name),
+		"reconcileID", reconcileID,
+		"attempt", attempt,
+	)
+	ctx := klog.NewContext(context.Background(), logger)
+	ctx, span := tracer.Start(ctx, kind+".reconcile", trace.WithAttributes(
+		attribute.String("k8s.namespace.name", ns),
+		attribute.String(kind+".name", name),
+		attribute.String("kluster.reconcile_id", string(reconcileID)),
+		attribute.Int("kluster.attempt", attempt),
+	))
+	defer span.End()
+
+	if err := sync(ctx, ns, name); err != nil {
+		logger.Error(err, "reconciling "+kind)
+		tracing.RecordError(span, err)
+		wq.AddRateLimited(item)
+		return true
+	}
+	wq.Forget(item)
+	return true
+}
+
+// syncPool: pool에 claim되지 않은 Kluster가 spec.size개 있도록 부족한 만큼 만들고 남는 만큼 지운 뒤 status를 갱신한다.
+// claim된 Kluster는 개수에 포함하지 않으므로, claim이 바인딩되면 그만큼 새로 만들어서 채운다.
+// pool의 spec이 바뀌면 이전 spec으로 만든 claim되지 않은 Kluster는 지우고 새 spec으로 다시 만든다.
+func (c *Controller) syncPool(ctx context.Context, ns, name string) error {
+	logger := klog.FromContext(ctx)
+	pool, err := c.pLister.KlusterPools(ns).Get(name)
+	// 삭제된 pool의 Kluster는 ownerReference로 garbage collector가 지운다. claim된 Kluster는 ownerReference가 없으므로 남는다.
+	if apierrors.IsNotFound(err) {
+		logger.V(4).Info("KlusterPool was deleted")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if pool.DeletionTimestamp != nil {
+		return nil
+	}
+
+	klusters, err := c.kLister.Klusters(ns).List(labels.SelectorFromSet(labels.Set{v1alpha1.PoolLabel: pool.Name}))
+	if err != nil {
+		return err
+	}
+	var ready, provisioning []*v1alpha1.Kluster
+	var claimed int32
+	for _, kluster := range klusters {
+		switch {
+		case kluster.Labels[v1alpha1.ClaimLabel] != "":
+			claimed++
+		case kluster.DeletionTimestamp != nil:
+			// 삭제 중인 Kluster는 곧 사라지므로 세지 않는다.
+		case !poolKlusterUpToDate(pool, kluster):
+			if err := c.deletePoolKluster(ctx, pool, kluster, "its spec differs from the pool spec"); err != nil {
+				return err
+			}
+		case isReady(kluster):
+			ready = append(ready, kluster)
+		default:
+			provisioning = append(provisioning, kluster)
+		}
+	}
+
+	size := int(pool.Spec.Size)
+	readyCount, provisioningCount := len(ready), len(provisioning)
+	if missing := size - readyCount - provisioningCount; missing > 0 {
+		if err := c.createPoolKlusters(ctx, pool, missing); err != nil {
+			return err
+		}
+		provisioningCount += missing
+	} else if extra := readyCount + provisioningCount - size; extra > 0 {
+		// 아직 준비되지 않은 Kluster부터, 같은 상태면 최근에 만든 것부터 지운다.
+		sortNewestFirst(provisioning)
+		sortNewestFirst(ready)
+		for _, kluster := range append(provisioning, ready...)[:extra] {
+			if err := c.deletePoolKluster(ctx, pool, kluster, "the pool was shrunk to its size"); err != nil {
+				return err
+			}
+			if isReady(kluster) {
+				readyCount--
+			} else {
+				provisioningCount--
+			}
+		}
+	}
+
+	return c.mutatePoolStatus(ctx, pool, func(status *v1alpha1.KlusterPoolStatus) {
+		status.Ready = int32(readyCount)
+		status.Provisioning = int32(provisioningCount)
+		status.Claimed = claimed
+		status.ObservedGeneration = pool.Generation
+	})
+}
+
+// DigitalOcean 클러스터가 running이고 삭제 중이 아닌 Kluster. claim에 바인딩할 수 있다.
+func isReady(kluster *v1alpha1.Kluster) bool {
+	return kluster.DeletionTimestamp == nil && kluster.Status.KlusterID != "" && kluster.Status.Progress == "running"
+}
+
+func sortNewestFirst(klusters []*v1alpha1.Kluster) {
+	sort.SliceStable(klusters, func(i, j int) bool {
+		return klusters[j].CreationTimestamp.Before(&klusters[i].CreationTimestamp)
+	})
+}
+
+// createPoolKlusters: pool의 spec으로 Kluster를 count개 만든다.
+// 이름은 "<pool name>-<index>"로, namespace에서 쓰이지 않는 가장 작은 index를 고른다. cache가 늦어서 이미 있는 이름을 고르면
+// AlreadyExists 에러로 다시 처리되므로, informer에 반영되기 전에 다시 sync되어도 Kluster가 더 만들어지지 않는다.
+func (c *Controller) createPoolKlusters(ctx context.Context, pool *v1alpha1.KlusterPool, count int) error {
+	logger := klog.FromContext(ctx)
+	all, err := c.kLister.Klusters(pool.Namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	used := sets.NewString()
+	for _, kluster := range all {
+		used.Insert(kluster.Name)
+	}
+
+	for index := 0; count > 0; index++ {
+		name := fmt.Sprintf("%s-%d", pool.Name, index)
+		if used.Has(name) {
+			continue
+		}
+		kluster := &v1alpha1.Kluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       pool.Namespace,
+				Labels:          map[string]string{v1alpha1.PoolLabel: pool.Name},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(pool, klusterPoolKind)},
+			},
+			Spec: poolKlusterSpec(pool),
+		}
+		if _, err := c.klient.Inspirit941V1alpha1().Klusters(pool.Namespace).Create(ctx, kluster, metav1.CreateOptions{}); err != nil {
+			c.recorder.Event(pool, corev1.EventTypeWarning, "KlusterCreationFailed", err.Error())
+			return err
+		}
+		logger.Info("created Kluster for the pool", "kluster", klog.KObj(kluster))
+		c.recorder.Event(pool, corev1.EventTypeNormal, "KlusterCreated", "Kluster "+name+" was created to fill the pool.")
+		count--
+	}
+	return nil
+}
+
+// pool의 Kluster가 가질 spec
+func poolKlusterSpec(pool *v1alpha1.KlusterPool) v1alpha1.KlusterSpec {
+	spec := v1alpha1.MergeTemplate(v1alpha1.KlusterSpec{}, pool.Spec.KlusterSpec)
+	spec.Template = pool.Spec.Template
+	return spec
+}
+
+// poolKlusterUpToDate: Kluster의 spec이 지금 pool의 spec과 같으면 true.
+// webhook이 채운 값 때문에 다르다고 보지 않도록, 양쪽 다 기본값을 채운 뒤 비교한다.
+func poolKlusterUpToDate(pool *v1alpha1.KlusterPool, kluster *v1alpha1.Kluster) bool {
+	desired := &v1alpha1.Kluster{ObjectMeta: metav1.ObjectMeta{Name: kluster.Name, Namespace: kluster.Namespace}, Spec: poolKlusterSpec(pool)}
+	current := &v1alpha1.Kluster{ObjectMeta: desired.ObjectMeta, Spec: *kluster.Spec.DeepCopy()}
+	klusterscheme.Scheme.Default(desired)
+	klusterscheme.Scheme.Default(current)
+	return equality.Semantic.DeepEqual(desired.Spec, current.Spec)
+}
+
+// lister의 Kluster는 status 갱신 때문에 resourceVersion이 자주 뒤처지므로, 지우기 직전에 다시 조회해서 아직 claim되지 않았는지 확인한다.
+// 같은 이름으로 다시 만들어진 Kluster는 UID로, 조회와 삭제 사이에 claim된 Kluster는 방금 조회한 resourceVersion으로 걸러낸다.
+func (c *Controller) deletePoolKluster(ctx context.Context, pool *v1alpha1.KlusterPool, kluster *v1alpha1.Kluster, reason string) error {
+	logger := klog.FromContext(ctx)
+	current, err := c.klient.Inspirit941V1alpha1().Klusters(kluster.Namespace).Get(ctx, kluster.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if current.UID != kluster.UID || current.Labels[v1alpha1.ClaimLabel] != "" {
+		logger.V(2).Info("Kluster was recreated or claimed, not deleting it", "kluster", klog.KObj(kluster))
+		return nil
+	}
+	logger.Info("deleting unclaimed Kluster of the pool", "kluster", klog.KObj(kluster), "reason", reason)
+	err = c.klient.Inspirit941V1alpha1().Klusters(current.Namespace).Delete(ctx, current.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &current.UID, ResourceVersion: &current.ResourceVersion},
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.recorder.Event(pool, corev1.EventTypeNormal, "KlusterDeleted", "Kluster "+kluster.Name+" was deleted because "+reason+".")
+	return nil
+}
+
+// 최신 KlusterPool을 조회해서 mutate를 적용한 뒤, 바뀐 내용이 있을 때만 status subresource를 업데이트한다.
+func (c *Controller) mutatePoolStatus(ctx context.Context, pool *v1alpha1.KlusterPool, mutate func(status *v1alpha1.KlusterPoolStatus)) error {
+	p, err := c.klient.Inspirit941V1alpha1().KlusterPools(pool.Namespace).Get(ctx, pool.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	status := p.Status
+	mutate(&p.Status)
+	if status == p.Status {
+		return nil
+	}
+	_, err = c.klient.Inspirit941V1alpha1().KlusterPools(p.Namespace).UpdateStatus(ctx, p, metav1.UpdateOptions{})
+	return err
+}
+
+// pool이 생성되거나 spec이 바뀌면 pool과, 바인딩을 기다리던 claim을 다시 처리한다.
+func (c *Controller) handlePoolAdd(obj interface{}) {
+	c.enqueuePool(obj)
+}
+
+func (c *Controller) handlePoolUpdate(oldObj, newObj interface{}) {
+	old, ok := oldObj.(*v1alpha1.KlusterPool)
+	if !ok {
+		return
+	}
+	pool, ok := newObj.(*v1alpha1.KlusterPool)
+	if !ok || old.Generation == pool.Generation {
+		return
+	}
+	c.enqueuePool(pool)
+}
+
+func (c *Controller) enqueuePool(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.ErrorS(err, "getting key of KlusterPool")
+		return
+	}
+	c.poolWq.Add(key)
+	c.enqueuePendingClaims(key)
+}
+
+// pool의 Kluster가 생성 / 삭제되거나 status가 바뀌면(ready 여부) pool을 다시 처리한다.
+// claim된 Kluster가 바뀌면 claim도 다시 처리한다. Retain으로 놓아준 Kluster는 label이 지워지므로 old object도 확인한다.
+func (c *Controller) handlePoolKluster(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	kluster, ok := obj.(*v1alpha1.Kluster)
+	if !ok {
+		return
+	}
+	if pool := kluster.Labels[v1alpha1.PoolLabel]; pool != "" {
+		key := kluster.Namespace + "/" + pool
+		c.poolWq.Add(key)
+		if isReady(kluster) && kluster.Labels[v1alpha1.ClaimLabel] == "" {
+			c.enqueuePendingClaims(key)
+		}
+	}
+	if claim := kluster.Labels[v1alpha1.ClaimLabel]; claim != "" {
+		c.claimWq.Add(kluster.Namespace + "/" + claim)
+	}
+}
+
+func (c *Controller) handlePoolKlusterUpdate(oldObj, newObj interface{}) {
+	c.handlePoolKluster(oldObj)
+	c.handlePoolKluster(newObj)
+}
+
+// poolKey("<namespace>/<pool name>")를 참조하는 claim 중 아직 바인딩되지 않은 claim을 queue에 넣는다.
+func (c *Controller) enqueuePendingClaims(poolKey string) {
+	claims, err := c.claimIndexer.ByIndex(poolIndex, poolKey)
+	if err != nil {
+		klog.ErrorS(err, "listing KlusterClaims of KlusterPool", "pool", poolKey)
+		return
+	}
+	for _, obj := range claims {
+		claim, ok := obj.(*v1alpha1.KlusterClaim)
+		if !ok || claim.Status.Phase == v1alpha1.ClaimPhaseBound || claim.Status.Phase == v1alpha1.ClaimPhaseReleased {
+			continue
+		}
+		c.enqueueClaim(claim)
+	}
+}
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"context"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sort"
+	"testing"
+	"time"
+)
+
+func newPool(size int32) *v1alpha1.KlusterPool {
+	return &v1alpha1.KlusterPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "preview", Namespace: "default", UID: "uid-preview", Generation: 1},
+		Spec: v1alpha1.KlusterPoolSpec{
+			Size:        size,
+			KlusterSpec: v1alpha1.KlusterTemplateSpec{Region: "fra1"},
+		},
+	}
+}
+
+// poolKluster: pool이 age 전에 만든 Kluster. ready면 클러스터가 running이다.
+func poolKluster(pool *v1alpha1.KlusterPool, name string, age time.Duration, ready bool) *v1alpha1.Kluster {
+	kluster := newKluster(name)
+	kluster.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+	kluster.Labels = map[string]string{v1alpha1.PoolLabel: pool.Name}
+	kluster.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(pool, klusterPoolKind)}
+	kluster.Spec = poolKlusterSpec(pool)
+	if ready {
+		kluster.Status.KlusterID = "cluster-" + name
+		kluster.Status.Progress = "running"
+	}
+	return kluster
+}
+
+func claimed(kluster *v1alpha1.Kluster, claim string) *v1alpha1.Kluster {
+	kluster.Labels[v1alpha1.ClaimLabel] = claim
+	kluster.OwnerReferences = nil
+	return kluster
+}
+
+// klusterNames: fake clientset에 있는 Kluster 이름
+func klusterNames(t *testing.T, c *Controller) []string {
+	t.Helper()
+	klusters, err := c.klient.Inspirit941V1alpha1().Klusters("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, kluster := range klusters.Items {
+		names = append(names, kluster.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSyncPool(t *testing.T) {
+	pool := newPool(2)
+	changed := newPool(2)
+	changed.Spec.KlusterSpec.Region = "ams3"
+	tests := []struct {
+		name       string
+		pool       *v1alpha1.KlusterPool
+		klusters   []runtime.Object
+		wantNames  []string
+		wantStatus v1alpha1.KlusterPoolStatus
+	}{
+		{
+			name:       "fill",
+			pool:       pool,
+			wantNames:  []string{"preview-0", "preview-1"},
+			wantStatus: v1alpha1.KlusterPoolStatus{Provisioning: 2, ObservedGeneration: 1},
+		},
+		{
+			// claim된 Kluster는 세지 않으므로 그만큼 새로 만든다. 이름은 쓰이지 않는 가장 작은 index.
+			name: "backfill claimed",
+			pool: pool,
+			klusters: []runtime.Object{
+				claimed(poolKluster(pool, "preview-0", time.Hour, true), "claim"),
+				poolKluster(pool, "preview-1", time.Hour, true),
+			},
+			wantNames:  []string{"preview-0", "preview-1", "preview-2"},
+			wantStatus: v1alpha1.KlusterPoolStatus{Ready: 1, Provisioning: 1, Claimed: 1, ObservedGeneration: 1},
+		},
+		{
+			// 준비되지 않은 Kluster부터, 같은 상태면 최근에 만든 것부터 지운다.
+			name: "shrink",
+			pool: newPool(1),
+			klusters: []runtime.Object{
+				poolKluster(pool, "preview-0", 2*time.Hour, true),
+				poolKluster(pool, "preview-1", time.Hour, true),
+				poolKluster(pool, "preview-2", 3*time.Hour, false),
+			},
+			wantNames:  []string{"preview-0"},
+			wantStatus: v1alpha1.KlusterPoolStatus{Ready: 1, ObservedGeneration: 1},
+		},
+		{
+			// pool의 spec이 바뀌면 claim되지 않은 Kluster만 새 spec으로 다시 만든다.
+			name: "spec changed",
+			pool: changed,
+			klusters: []runtime.Object{
+				claimed(poolKluster(pool, "preview-0", time.Hour, true), "claim"),
+				poolKluster(pool, "preview-1", time.Hour, true),
+				poolKluster(changed, "preview-2", time.Hour, true),
+			},
+			wantNames:  []string{"preview-0", "preview-2", "preview-3"},
+			wantStatus: v1alpha1.KlusterPoolStatus{Ready: 1, Provisioning: 1, Claimed: 1, ObservedGeneration: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, _ := newTestController(t, Options{}, append(tt.klusters, tt.pool)...)
+
+			if err := c.syncPool(context.Background(), "default", "preview"); err != nil {
+				t.Fatal(err)
+			}
+			if names := klusterNames(t, c); !equalStrings(names, tt.wantNames) {
+				t.Errorf("Klusters = %v, want %v", names, tt.wantNames)
+			}
+			created, err := c.klient.Inspirit941V1alpha1().Klusters("default").List(context.Background(), metav1.ListOptions{
+				LabelSelector: labels.SelectorFromSet(labels.Set{v1alpha1.PoolLabel: "preview"}).String(),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, kluster := range created.Items {
+				if kluster.Labels[v1alpha1.ClaimLabel] == "" && (!poolKlusterUpToDate(tt.pool, &kluster) || !metav1.IsControlledBy(&kluster, tt.pool)) {
+					t.Errorf("Kluster %s has spec %+v and owners %v, want the pool spec and the pool as owner", kluster.Name, kluster.Spec, kluster.OwnerReferences)
+				}
+			}
+			pool, err := c.klient.Inspirit941V1alpha1().KlusterPools("default").Get(context.Background(), "preview", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pool.Status != tt.wantStatus {
+				t.Errorf("status = %+v, want %+v", pool.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// 지우기 직전에 다시 조회해서, 다시 만들어졌거나 그 사이에 claim된 Kluster는 지우지 않는다.
+func TestDeletePoolKluster(t *testing.T) {
+	pool := newPool(1)
+	tests := []struct {
+		name        string
+		current     *v1alpha1.Kluster
+		wantDeleted bool
+	}{
+		{name: "unclaimed", current: poolKluster(pool, "preview-0", time.Hour, true), wantDeleted: true},
+		{name: "claimed", current: claimed(poolKluster(pool, "preview-0", time.Hour, true), "claim")},
+		{name: "recreated", current: func() *v1alpha1.Kluster {
+			kluster := poolKluster(pool, "preview-0", time.Minute, false)
+			kluster.UID = types.UID("uid-recreated")
+			return kluster
+		}()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, recorder := newTestController(t, Options{}, pool, tt.current)
+
+			if err := c.deletePoolKluster(context.Background(), pool, poolKluster(pool, "preview-0", time.Hour, true), "test"); err != nil {
+				t.Fatal(err)
+			}
+			_, err := c.klient.Inspirit941V1alpha1().Klusters("default").Get(context.Background(), "preview-0", metav1.GetOptions{})
+			if deleted := apierrors.IsNotFound(err); deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if got := hasEvent(recorder, "KlusterDeleted"); got != tt.wantDeleted {
+				t.Errorf("KlusterDeleted event = %v, want %v", got, tt.wantDeleted)
+			}
+		})
+	}
+
+	// 이미 없는 Kluster는 지워진 것으로 본다.
+	c, _, _ := newTestController(t, Options{}, pool)
+	if err := c.deletePoolKluster(context.Background(), pool, poolKluster(pool, "preview-0", time.Hour, true), "test"); err != nil {
+		t.Errorf("deletePoolKluster() of a missing Kluster = %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"context"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/digitalocean"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// provider: controller가 사용하는 DigitalOcean 호출. digitalocean.Client가 구현하고, 테스트에서는 API를 호출하지 않는 fake로 바꾼다.
+type provider interface {
+	Create(ctx context.Context, k *v1alpha1.Kluster) (string, error)
+	ClusterState(ctx context.Context, k *v1alpha1.Kluster, id string) (string, error)
+	Update(ctx context.Context, k *v1alpha1.Kluster, id string) (bool, error)
+	ObservedStatus(ctx context.Context, k *v1alpha1.Kluster, id string) (*v1alpha1.KlusterStatus, error)
+	Drift(ctx context.Context, k *v1alpha1.Kluster, id string) (digitalocean.ClusterDrift, error)
+	Release(ctx context.Context, k *v1alpha1.Kluster, id string) error
+	Delete(ctx context.Context, k *v1alpha1.Kluster, id string) error
+	Kubeconfig(ctx context.Context, k *v1alpha1.Kluster, id string) ([]byte, error)
+	MonthlyCost(ctx context.Context, k *v1alpha1.Kluster) (float64, error)
+	ManagedClusters(ctx context.Context, tokenSecret digitalocean.TokenSecret) ([]digitalocean.ManagedCluster, error)
+	DeleteOrphan(ctx context.Context, tokenSecret digitalocean.TokenSecret, obj runtime.Object, id string) error
+}
+
+var _ provider = &digitalocean.Client{}
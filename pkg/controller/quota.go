@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+func (c *Controller) quotaWorker() {
+	for c.processNextQueueItem(c.quotaWq, "klusterQuota", c.syncQuota) {
+	}
+}
+
+// checkQuota: spec의 월 예상 비용을 계산해서, 비용이 늘어나는 변경이 namespace의 KlusterQuota 예산을 넘으면 QuotaExceeded condition에 기록하고 true를 리턴한다.
+// 사용량은 각 Kluster가 status.estimatedMonthlyCost에 기록한 비용의 합이다.
+// 비용이 그대로거나 줄어드는 변경은 예산을 넘은 상태여도 막지 않는다. 계산한 비용은 반영 후 status에 기록하도록 같이 리턴한다.
+// 비용이 늘어나는 변경이 예산 안이면, 같은 namespace의 다른 Kluster가 동시에 같은 예산을 쓰지 않도록 DigitalOcean에 반영하기 전에 비용을 먼저 기록(예약)한다.
+// quota가 없는 namespace에서는 가격 조회가 실패해도 클러스터 생성 / 변경을 막지 않고, 이전에 기록한 비용을 유지한다.
+func (c *Controller) checkQuota(ctx context.Context, kluster *v1alpha1.Kluster) (string, bool, error) {
+	logger := klog.FromContext(ctx)
+	quotas, err := c.qLister.KlusterQuotas(kluster.Namespace).List(labels.Everything())
+	if err != nil {
+		return "", false, err
+	}
+	cost, err := c.do.MonthlyCost(ctx, kluster)
+	if err != nil && len(quotas) == 0 {
+		logger.Info("could not estimate monthly cost, keeping the recorded cost", "error", err.Error())
+		return kluster.Status.EstimatedMonthlyCost, false, c.mutateStatus(ctx, kluster, func(status *v1alpha1.KlusterStatus) {
+			meta.RemoveStatusCondition(&status.Conditions, v1alpha1.ConditionQuotaExceeded)
+		})
+	}
+	if err != nil {
+		// 예산을 확인할 수 없으면 클러스터를 만들거나 바꾸지 않고 다시 시도한다.
+		logger.Error(err, "estimating monthly cost")
+		return "", false, err
+	}
+	if len(quotas) == 0 {
+		return formatCost(cost), false, c.mutateStatus(ctx, kluster, func(status *v1alpha1.KlusterStatus) {
+			meta.RemoveStatusCondition(&status.Conditions, v1alpha1.ConditionQuotaExceeded)
+		})
+	}
+
+	condition := metav1.Condition{
+		Type:    v1alpha1.ConditionQuotaExceeded,
+		Status:  metav1.ConditionFalse,
+		Reason:  "WithinBudget",
+		Message: "estimated monthly cost $" + formatCost(cost) + " fits in the KlusterQuotas of the namespace",
+	}
+	if cost > parseCost(kluster.Status.EstimatedMonthlyCost) {
+		// 검사와 예약 사이에 다른 worker가 끼어들지 않도록 namespace마다 한 번에 하나씩 검사한다.
+		unlock := c.quotaLock(kluster.Namespace)
+		defer unlock()
+		used, err := c.namespaceCost(ctx, kluster.Namespace, kluster.Name)
+		if err != nil {
+			return "", false, err
+		}
+		if quota := exceededQuota(quotas, used, cost); quota != nil {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "BudgetExceeded"
+			condition.Message = fmt.Sprintf("estimated monthly cost $%s would raise the usage of KlusterQuota %s to $%s, over the budget of $%s",
+				formatCost(cost), quota.Name, formatCost(used+cost), quota.Spec.MonthlyBudget)
+		} else if !c.opts.DryRun {
+			// 생성 / 변경이 실패해도 예약한 비용은 남는다. 다음 reconcile에서 다시 시도하므로 예산을 다른 Kluster에 넘기지 않는다.
+			if err := c.mutateStatus(ctx, kluster, func(status *v1alpha1.KlusterStatus) {
+				status.EstimatedMonthlyCost = formatCost(cost)
+			}); err != nil {
+				return "", false, err
+			}
+		}
+	}
+	exceeded := condition.Status == metav1.ConditionTrue
+	// resync마다 같은 이벤트가 쌓이지 않도록 내용이 바뀔 때만 남긴다.
+	if previous := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionQuotaExceeded); exceeded && (previous == nil || previous.Message != condition.Message) {
+		logger.Info("Kluster exceeds KlusterQuota", "message", condition.Message)
+		c.recorder.Event(kluster, corev1.EventTypeWarning, "QuotaExceeded", condition.Message)
+	}
+	return formatCost(cost), exceeded, c.setCondition(ctx, kluster, condition)
+}
+
+// 다른 Kluster의 사용량 used에 cost를 더하면 예산을 넘는 quota. 여러 개면 이름 순서로 첫 번째, 없으면 nil.
+func exceededQuota(quotas []*v1alpha1.KlusterQuota, used, cost float64) *v1alpha1.KlusterQuota {
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i].Name < quotas[j].Name })
+	for _, quota := range quotas {
+		if used+cost > parseCost(quota.Spec.MonthlyBudget) {
+			return quota
+		}
+	}
+	return nil
+}
+
+// namespace에서 skip을 제외한 Kluster들이 status에 기록한 월 예상 비용의 합.
+// lister cache에는 다른 worker가 방금 예약한 비용이 아직 반영되지 않았을 수 있으므로 API server에서 조회한다.
+func (c *Controller) namespaceCost(ctx context.Context, ns, skip string) (float64, error) {
+	klusters, err := c.klient.Inspirit941V1alpha1().Klusters(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	used := 0.0
+	for _, kluster := range klusters.Items {
+		if kluster.Name != skip {
+			used += parseCost(kluster.Status.EstimatedMonthlyCost)
+		}
+	}
+	return used, nil
+}
+
+// quotaLock: namespace의 quota lock을 잡고 unlock 함수를 리턴한다.
+func (c *Controller) quotaLock(ns string) func() {
+	c.quotaMu.Lock()
+	if c.quotaLocks == nil {
+		c.quotaLocks = map[string]*sync.Mutex{}
+	}
+	lock, ok := c.quotaLocks[ns]
+	if !ok {
+		lock = &sync.Mutex{}
+		c.quotaLocks[ns] = lock
+	}
+	c.quotaMu.Unlock()
+	lock.Lock()
+	return lock.Unlock
+}
+
+// syncQuota: namespace의 Kluster들이 기록한 월 예상 비용을 합쳐서 quota의 status에 기록한다.
+func (c *Controller) syncQuota(ctx context.Context, ns, name string) error {
+	quota, err := c.qLister.KlusterQuotas(ns).Get(name)
+	if apierrors.IsNotFound(err) {
+		klog.FromContext(ctx).V(4).Info("KlusterQuota was deleted")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	klusters, err := c.kLister.Klusters(ns).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	sort.Slice(klusters, func(i, j int) bool { return klusters[i].Name < klusters[j].Name })
+	used := 0.0
+	var usage []v1alpha1.KlusterQuotaUsage
+	for _, kluster := range klusters {
+		if kluster.Status.EstimatedMonthlyCost == "" {
+			continue
+		}
+		used += parseCost(kluster.Status.EstimatedMonthlyCost)
+		usage = append(usage, v1alpha1.KlusterQuotaUsage{Name: kluster.Name, MonthlyCost: kluster.Status.EstimatedMonthlyCost})
+	}
+	return c.mutateQuotaStatus(ctx, quota, func(status *v1alpha1.KlusterQuotaStatus) {
+		status.Used = formatCost(used)
+		status.Klusters = usage
+		status.ObservedGeneration = quota.Generation
+	})
+}
+
+// 최신 KlusterQuota를 조회해서 mutate를 적용한 뒤, 바뀐 내용이 있을 때만 status subresource를 업데이트한다.
+func (c *Controller) mutateQuotaStatus(ctx context.Context, quota *v1alpha1.KlusterQuota, mutate func(status *v1alpha1.KlusterQuotaStatus)) error {
+	q, err := c.klient.Inspirit941V1alpha1().KlusterQuotas(quota.Namespace).Get(ctx, quota.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	status := q.Status.DeepCopy()
+	mutate(&q.Status)
+	if equality.Semantic.DeepEqual(status, &q.Status) {
+		return nil
+	}
+	_, err = c.klient.Inspirit941V1alpha1().KlusterQuotas(q.Namespace).UpdateStatus(ctx, q, metav1.UpdateOptions{})
+	return err
+}
+
+// 비용은 USD 소수점 둘째 자리까지의 문자열로 기록한다. 값이 없거나 잘못된 값은 0으로 본다.
+func formatCost(cost float64) string {
+	return strconv.FormatFloat(cost, 'f', 2, 64)
+}
+
+func parseCost(cost string) float64 {
+	value, err := strconv.ParseFloat(cost, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// quota가 생성되거나 예산이 바뀌면 사용량을 다시 기록하고, 예산 때문에 막혀 있던 Kluster를 다시 처리한다.
+// quota가 삭제되어도 막혀 있던 Kluster를 다시 처리한다.
+func (c *Controller) handleQuota(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	quota, ok := obj.(*v1alpha1.KlusterQuota)
+	if !ok {
+		return
+	}
+	if quota.DeletionTimestamp == nil {
+		c.quotaWq.Add(quota.Namespace + "/" + quota.Name)
+	}
+	klusters, err := c.kLister.Klusters(quota.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "listing Klusters of KlusterQuota namespace", "quota", klog.KObj(quota))
+		return
+	}
+	for _, kluster := range klusters {
+		c.enqueue(kluster)
+	}
+}
+
+func (c *Controller) handleQuotaUpdate(oldObj, newObj interface{}) {
+	old, ok := oldObj.(*v1alpha1.KlusterQuota)
+	if !ok {
+		return
+	}
+	quota, ok := newObj.(*v1alpha1.KlusterQuota)
+	if !ok || old.Generation == quota.Generation {
+		return
+	}
+	c.handleQuota(quota)
+}
+
+// Kluster가 생성 / 삭제되거나 기록된 비용이 바뀌면 namespace의 quota 사용량을 다시 계산한다.
+func (c *Controller) handleQuotaKluster(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	kluster, ok := obj.(*v1alpha1.Kluster)
+	if !ok {
+		return
+	}
+	quotas, err := c.qLister.KlusterQuotas(kluster.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "listing KlusterQuotas of Kluster namespace", "kluster", klog.KObj(kluster))
+		return
+	}
+	if len(quotas) == 0 {
+		return
+	}
+	for _, quota := range quotas {
+		c.quotaWq.Add(quota.Namespace + "/" + quota.Name)
+	}
+	// 다른 Kluster가 삭제되거나 비용이 줄어서 예산이 남으면, 예산 때문에 막혀 있던 Kluster를 바로 다시 처리한다.
+	klusters, err := c.kLister.Klusters(kluster.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "listing Klusters of KlusterQuota namespace", "kluster", klog.KObj(kluster))
+		return
+	}
+	for _, blocked := range klusters {
+		if blocked.Name != kluster.Name && meta.IsStatusConditionTrue(blocked.Status.Conditions, v1alpha1.ConditionQuotaExceeded) {
+			c.enqueue(blocked)
+		}
+	}
+}
+
+func (c *Controller) handleQuotaKlusterUpdate(oldObj, newObj interface{}) {
+	old, ok := oldObj.(*v1alpha1.Kluster)
+	if !ok {
+		return
+	}
+	kluster, ok := newObj.(*v1alpha1.Kluster)
+	if !ok || old.Status.EstimatedMonthlyCost == kluster.Status.EstimatedMonthlyCost {
+		return
+	}
+	c.handleQuotaKluster(kluster)
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestFormatCost(t *testing.T) {
+	tests := []struct {
+		cost float64
+		want string
+	}{
+		{cost: 0, want: "0.00"},
+		{cost: 54, want: "54.00"},
+		{cost: 12.5, want: "12.50"},
+		{cost: 0.125, want: "0.12"},
+		{cost: 1234.567, want: "1234.57"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatCost(tt.cost); got != tt.want {
+				t.Errorf("formatCost(%v) = %q, want %q", tt.cost, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCost(t *testing.T) {
+	tests := []struct {
+		cost string
+		want float64
+	}{
+		{cost: "54.00", want: 54},
+		{cost: "12.5", want: 12.5},
+		{cost: "100", want: 100},
+		{cost: "", want: 0},
+		{cost: "$10", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cost, func(t *testing.T) {
+			if got := parseCost(tt.cost); got != tt.want {
+				t.Errorf("parseCost(%q) = %v, want %v", tt.cost, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExceededQuota(t *testing.T) {
+	quota := func(name, budget string) *v1alpha1.KlusterQuota {
+		return &v1alpha1.KlusterQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       v1alpha1.KlusterQuotaSpec{MonthlyBudget: budget},
+		}
+	}
+	tests := []struct {
+		name   string
+		quotas []*v1alpha1.KlusterQuota
+		used   float64
+		cost   float64
+		want   string
+	}{
+		{
+			name: "no quota",
+			used: 1000,
+			cost: 1000,
+			want: "",
+		},
+		{
+			name:   "within budget",
+			quotas: []*v1alpha1.KlusterQuota{quota("team", "200")},
+			used:   100,
+			cost:   54,
+		},
+		{
+			name:   "exactly the budget",
+			quotas: []*v1alpha1.KlusterQuota{quota("team", "154.00")},
+			used:   100,
+			cost:   54,
+		},
+		{
+			name:   "over budget",
+			quotas: []*v1alpha1.KlusterQuota{quota("team", "150")},
+			used:   100,
+			cost:   54,
+			want:   "team",
+		},
+		{
+			name:   "the smallest budget applies",
+			quotas: []*v1alpha1.KlusterQuota{quota("team", "1000"), quota("project", "150")},
+			used:   100,
+			cost:   54,
+			want:   "project",
+		},
+		{
+			name:   "first exceeded quota by name",
+			quotas: []*v1alpha1.KlusterQuota{quota("team", "100"), quota("project", "150")},
+			used:   100,
+			cost:   54,
+			want:   "project",
+		},
+		{
+			name:   "invalid budget is zero",
+			quotas: []*v1alpha1.KlusterQuota{quota("team", "")},
+			cost:   1,
+			want:   "team",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if quota := exceededQuota(tt.quotas, tt.used, tt.cost); quota != nil {
+				got = quota.Name
+			}
+			if got != tt.want {
+				t.Errorf("exceededQuota() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,411 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"sort"
+	"time"
+)
+
+// rollout 기본값. spec에 값이 없을 때 사용한다.
+const (
+	defaultRolloutCanary           = 1
+	defaultRolloutMaxConcurrent    = 1
+	defaultRolloutSoakTime         = 10 * time.Minute
+	defaultRolloutProgressDeadline = 60 * time.Minute
+	// upgrade / soak 중인 Kluster의 status를 다시 읽는 주기. Kluster status는 resync 때만 갱신되므로 이 주기로 Kluster도 다시 reconcile 한다.
+	rolloutPollInterval = 30 * time.Second
+)
+
+func (c *Controller) rolloutWorker() {
+	for c.processNextQueueItem(c.rolloutWq, "klusterRollout", c.syncRollout) {
+	}
+}
+
+// syncRollout: selector로 고른 Kluster를 wave로 나눠서, 한 wave씩 spec.version을 바꾸고 새 버전으로 running이 된 뒤 soakTime이 지나면 다음 wave로 넘어간다.
+// wave 안의 클러스터가 degraded / error 상태가 되거나 progressDeadline 안에 끝나지 않으면 spec.paused를 설정해서 rollout을 멈춘다.
+func (c *Controller) syncRollout(ctx context.Context, ns, name string) error {
+	logger := klog.FromContext(ctx)
+	rollout, err := c.rLister.KlusterRollouts(ns).Get(name)
+	if apierrors.IsNotFound(err) {
+		logger.V(4).Info("KlusterRollout was deleted")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if rollout.DeletionTimestamp != nil {
+		return nil
+	}
+	rollout = rollout.DeepCopy()
+	status := rollout.Status.DeepCopy()
+
+	status.ObservedGeneration = rollout.Generation
+
+	// selector / version이 바뀌면 wave를 다시 나눈다. 이미 target version인 Kluster는 바로 Succeeded가 된다.
+	// spec.paused만 바뀐 경우(generation은 바뀜)는 기존 plan을 이어서 진행한다.
+	selector := metav1.FormatLabelSelector(&rollout.Spec.Selector)
+	if status.Version != rollout.Spec.Version || status.Selector != selector {
+		if err := c.planRollout(rollout, status); err != nil {
+			logger.Info("invalid KlusterRollout selector", "error", err.Error())
+			c.recorder.Event(rollout, corev1.EventTypeWarning, "InvalidSelector", err.Error())
+			status.Phase = v1alpha1.RolloutPhasePaused
+			status.Message = "invalid selector: " + err.Error()
+			return c.mutateRolloutStatus(ctx, rollout, status)
+		}
+		logger.Info("planned rollout", "version", rollout.Spec.Version, "klusters", status.Total, "waves", status.Waves)
+		c.recorder.Eventf(rollout, corev1.EventTypeNormal, "RolloutPlanned", "%d Klusters will be upgraded to %s in %d waves.", status.Total, rollout.Spec.Version, status.Waves)
+	}
+
+	if status.Phase == v1alpha1.RolloutPhaseCompleted {
+		return c.mutateRolloutStatus(ctx, rollout, status)
+	}
+	if rollout.Spec.Paused {
+		if status.Phase != v1alpha1.RolloutPhasePaused {
+			status.Message = "paused by spec.paused"
+		}
+		status.Phase = v1alpha1.RolloutPhasePaused
+		return c.mutateRolloutStatus(ctx, rollout, status)
+	}
+	status.Phase = v1alpha1.RolloutPhaseProgressing
+	status.Message = ""
+
+	// 현재 wave가 끝나면 같은 reconcile에서 다음 wave를 시작한다.
+	var failed []string
+	for status.CurrentWave < status.Waves {
+		done := true
+		for i := range status.Klusters {
+			entry := &status.Klusters[i]
+			if entry.Wave != status.CurrentWave {
+				continue
+			}
+			if err := c.progressRolloutKluster(ctx, rollout, entry); err != nil {
+				return err
+			}
+			switch entry.Phase {
+			case v1alpha1.RolloutKlusterFailed:
+				if !rolloutKlusterFailedBefore(&rollout.Status, entry.Name) {
+					failed = append(failed, entry.Name+": "+entry.Message)
+				}
+			case v1alpha1.RolloutKlusterSucceeded, v1alpha1.RolloutKlusterSkipped:
+			default:
+				done = false
+			}
+		}
+		if len(failed) > 0 || !done {
+			break
+		}
+		logger.Info("rollout wave completed", "wave", status.CurrentWave)
+		c.recorder.Eventf(rollout, corev1.EventTypeNormal, "WaveCompleted", "Wave %d of %d was completed.", status.CurrentWave+1, status.Waves)
+		status.CurrentWave++
+	}
+	countUpgraded(status)
+
+	if len(failed) > 0 {
+		message := fmt.Sprintf("wave %d failed: %v", status.CurrentWave, failed)
+		logger.Info("pausing rollout, cluster failed", "failed", failed)
+		c.recorder.Event(rollout, corev1.EventTypeWarning, "RolloutPaused", message)
+		status.Phase = v1alpha1.RolloutPhasePaused
+		status.Message = message
+		if err := c.mutateRolloutStatus(ctx, rollout, status); err != nil {
+			return err
+		}
+		// 실패를 확인한 사람이 spec.paused를 false로 바꿔야 다음 wave가 시작된다.
+		r, err := c.klient.Inspirit941V1alpha1().KlusterRollouts(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		r.Spec.Paused = true
+		_, err = c.klient.Inspirit941V1alpha1().KlusterRollouts(ns).Update(ctx, r, metav1.UpdateOptions{})
+		return err
+	}
+	if status.CurrentWave >= status.Waves {
+		logger.Info("rollout completed", "version", rollout.Spec.Version)
+		c.recorder.Eventf(rollout, corev1.EventTypeNormal, "RolloutCompleted", "%d of %d Klusters are running %s.", status.Upgraded, status.Total, rollout.Spec.Version)
+		status.Phase = v1alpha1.RolloutPhaseCompleted
+		return c.mutateRolloutStatus(ctx, rollout, status)
+	}
+
+	if err := c.mutateRolloutStatus(ctx, rollout, status); err != nil {
+		return err
+	}
+	key, err := cache.MetaNamespaceKeyFunc(rollout)
+	if err != nil {
+		return err
+	}
+	c.rolloutWq.AddAfter(key, rolloutPollInterval)
+	return nil
+}
+
+// planRollout: selector에 맞는 Kluster를 이름 순서로 canary wave와 maxConcurrent 크기의 wave로 나눈다.
+func (c *Controller) planRollout(rollout *v1alpha1.KlusterRollout, status *v1alpha1.KlusterRolloutStatus) error {
+	selector, err := metav1.LabelSelectorAsSelector(&rollout.Spec.Selector)
+	if err != nil {
+		return err
+	}
+	klusters, err := c.kLister.Klusters(rollout.Namespace).List(selector)
+	if err != nil {
+		return err
+	}
+	sort.Slice(klusters, func(i, j int) bool { return klusters[i].Name < klusters[j].Name })
+
+	canary := int32(defaultRolloutCanary)
+	if rollout.Spec.Canary != nil {
+		canary = *rollout.Spec.Canary
+	}
+	maxConcurrent := rollout.Spec.MaxConcurrent
+	if maxConcurrent <= 0 {
+		maxConcurrent = defaultRolloutMaxConcurrent
+	}
+
+	*status = v1alpha1.KlusterRolloutStatus{
+		Phase:              v1alpha1.RolloutPhaseProgressing,
+		Total:              int32(len(klusters)),
+		Version:            rollout.Spec.Version,
+		Selector:           metav1.FormatLabelSelector(&rollout.Spec.Selector),
+		ObservedGeneration: rollout.Generation,
+	}
+	// 이미 target version인 Kluster는 wave 수에 포함하지 않는다.
+	var index int32
+	for _, kluster := range klusters {
+		entry := v1alpha1.RolloutKlusterStatus{
+			Name:            kluster.Name,
+			Phase:           v1alpha1.RolloutKlusterPending,
+			PreviousVersion: kluster.Spec.Version,
+		}
+		if kluster.Spec.Version == rollout.Spec.Version && kluster.Status.Version == rollout.Spec.Version {
+			entry.Phase = v1alpha1.RolloutKlusterSucceeded
+			entry.Message = "already running the target version"
+		} else {
+			entry.Wave = rolloutWave(index, canary, maxConcurrent)
+			index++
+			status.Waves = entry.Wave + 1
+		}
+		status.Klusters = append(status.Klusters, entry)
+	}
+	countUpgraded(status)
+	return nil
+}
+
+// upgrade할 index번째 Kluster의 wave. canary wave가 0번이고, 이후는 maxConcurrent개씩 나눈다.
+func rolloutWave(index, canary, maxConcurrent int32) int32 {
+	if index < canary {
+		return 0
+	}
+	wave := (index - canary) / maxConcurrent
+	if canary > 0 {
+		wave++
+	}
+	return wave
+}
+
+// progressRolloutKluster: wave 안의 Kluster 하나를 다음 단계로 진행한다.
+// Pending이면 spec.version을 바꾸고, Upgrading이면 새 버전으로 running이 됐는지, Soaking이면 soakTime이 지났는지 확인한다.
+func (c *Controller) progressRolloutKluster(ctx context.Context, rollout *v1alpha1.KlusterRollout, entry *v1alpha1.RolloutKlusterStatus) error {
+	logger := klog.LoggerWithValues(klog.FromContext(ctx), "kluster", klog.KRef(rollout.Namespace, entry.Name))
+	switch entry.Phase {
+	case v1alpha1.RolloutKlusterPending, v1alpha1.RolloutKlusterUpgrading, v1alpha1.RolloutKlusterSoaking:
+	default:
+		return nil
+	}
+
+	kluster, err := c.kLister.Klusters(rollout.Namespace).Get(entry.Name)
+	if apierrors.IsNotFound(err) {
+		entry.Phase = v1alpha1.RolloutKlusterSkipped
+		entry.Message = "Kluster was deleted"
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if kluster.DeletionTimestamp != nil {
+		entry.Phase = v1alpha1.RolloutKlusterSkipped
+		entry.Message = "Kluster is being deleted"
+		return nil
+	}
+
+	now := metav1.Now()
+	switch entry.Phase {
+	case v1alpha1.RolloutKlusterPending:
+		// pause된 Kluster는 spec을 바꿔도 반영되지 않으므로 deadline까지 기다리지 않고 건너뛴다.
+		if isPaused(kluster) {
+			entry.Phase = v1alpha1.RolloutKlusterSkipped
+			entry.Message = "Kluster is paused by the " + v1alpha1.PausedAnnotation + " annotation"
+			return nil
+		}
+		// KlusterSet의 Kluster는 set의 spec으로 되돌려지므로 set의 klusterSpec.version을 바꿔야 한다.
+		if owner := metav1.GetControllerOf(kluster); owner != nil && owner.Kind == klusterSetKind.Kind {
+			entry.Phase = v1alpha1.RolloutKlusterSkipped
+			entry.Message = "Kluster is managed by KlusterSet " + owner.Name + ", change the version of the set instead"
+			return nil
+		}
+		// claim되지 않은 KlusterPool의 Kluster는 spec이 pool과 다르면 pool이 새로 만들어서 교체한다.
+		if owner := metav1.GetControllerOf(kluster); owner != nil && owner.Kind == klusterPoolKind.Kind {
+			entry.Phase = v1alpha1.RolloutKlusterSkipped
+			entry.Message = "Kluster is an unclaimed Kluster of KlusterPool " + owner.Name + ", change the version of the pool instead"
+			return nil
+		}
+		entry.PreviousVersion = kluster.Spec.Version
+		if kluster.Spec.Version != rollout.Spec.Version {
+			k := kluster.DeepCopy()
+			k.Spec.Version = rollout.Spec.Version
+			if _, err := c.klient.Inspirit941V1alpha1().Klusters(k.Namespace).Update(ctx, k, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+			logger.Info("upgrading Kluster", "from", entry.PreviousVersion, "to", rollout.Spec.Version)
+			c.recorder.Eventf(kluster, corev1.EventTypeNormal, "RolloutUpgrade", "KlusterRollout %s changed spec.version from %s to %s.", rollout.Name, entry.PreviousVersion, rollout.Spec.Version)
+		}
+		entry.Phase = v1alpha1.RolloutKlusterUpgrading
+		entry.StartedAt = &now
+		c.enqueue(kluster)
+	case v1alpha1.RolloutKlusterUpgrading:
+		if message := degradedMessage(kluster); message != "" {
+			entry.Phase = v1alpha1.RolloutKlusterFailed
+			entry.Message = message
+			return nil
+		}
+		if upgraded(kluster, rollout.Spec.Version) {
+			logger.Info("Kluster is running the new version, soaking")
+			entry.Phase = v1alpha1.RolloutKlusterSoaking
+			entry.ReadyAt = &now
+			return nil
+		}
+		deadline := defaultRolloutProgressDeadline
+		if rollout.Spec.ProgressDeadline != nil {
+			deadline = rollout.Spec.ProgressDeadline.Duration
+		}
+		if entry.StartedAt != nil && now.Sub(entry.StartedAt.Time) > deadline {
+			entry.Phase = v1alpha1.RolloutKlusterFailed
+			entry.Message = fmt.Sprintf("cluster was not running %s within %s (progress %q, version %q)", rollout.Spec.Version, deadline, kluster.Status.Progress, kluster.Status.Version)
+			return nil
+		}
+		// Kluster status는 resync 때만 갱신되므로 upgrade가 끝났는지 확인할 수 있도록 다시 reconcile 한다.
+		c.enqueue(kluster)
+	case v1alpha1.RolloutKlusterSoaking:
+		if message := degradedMessage(kluster); message != "" {
+			entry.Phase = v1alpha1.RolloutKlusterFailed
+			entry.Message = message
+			return nil
+		}
+		soak := defaultRolloutSoakTime
+		if rollout.Spec.SoakTime != nil {
+			soak = rollout.Spec.SoakTime.Duration
+		}
+		if entry.ReadyAt != nil && now.Sub(entry.ReadyAt.Time) >= soak {
+			logger.Info("Kluster upgrade succeeded")
+			entry.Phase = v1alpha1.RolloutKlusterSucceeded
+			return nil
+		}
+		c.enqueue(kluster)
+	}
+	return nil
+}
+
+// degraded / error 상태면 이유를 리턴한다. 정상이면 "".
+func degradedMessage(kluster *v1alpha1.Kluster) string {
+	switch kluster.Status.Progress {
+	case "degraded", "error":
+		message := "cluster is " + kluster.Status.Progress
+		if kluster.Status.Message != "" {
+			message += ": " + kluster.Status.Message
+		}
+		return message
+	}
+	return ""
+}
+
+// 바뀐 spec이 반영됐고(observedGeneration) 클러스터가 새 버전으로 running이면 true.
+func upgraded(kluster *v1alpha1.Kluster, version string) bool {
+	return kluster.Status.ObservedGeneration == kluster.Generation && kluster.Status.Progress == "running" && kluster.Status.Version == version
+}
+
+// 이전 reconcile에서 이미 Failed였던 Kluster. resume한 뒤에 같은 실패로 다시 멈추지 않도록 새로 실패한 Kluster만 센다.
+func rolloutKlusterFailedBefore(status *v1alpha1.KlusterRolloutStatus, name string) bool {
+	for _, entry := range status.Klusters {
+		if entry.Name == name {
+			return entry.Phase == v1alpha1.RolloutKlusterFailed
+		}
+	}
+	return false
+}
+
+func countUpgraded(status *v1alpha1.KlusterRolloutStatus) {
+	status.Upgraded = 0
+	for _, entry := range status.Klusters {
+		if entry.Phase == v1alpha1.RolloutKlusterSucceeded {
+			status.Upgraded++
+		}
+	}
+}
+
+// 바뀐 내용이 있을 때만 status subresource를 업데이트한다. status는 계산된 전체 값으로 덮어쓴다.
+func (c *Controller) mutateRolloutStatus(ctx context.Context, rollout *v1alpha1.KlusterRollout, status *v1alpha1.KlusterRolloutStatus) error {
+	r, err := c.klient.Inspirit941V1alpha1().KlusterRollouts(rollout.Namespace).Get(ctx, rollout.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(&r.Status, status) {
+		return nil
+	}
+	r.Status = *status
+	_, err = c.klient.Inspirit941V1alpha1().KlusterRollouts(r.Namespace).UpdateStatus(ctx, r, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *Controller) handleRolloutAdd(obj interface{}) {
+	c.enqueueRollout(obj)
+}
+
+// status 업데이트와 resync는 무시한다. spec.paused를 바꾸면 generation이 바뀌므로 resume도 여기로 들어온다.
+func (c *Controller) handleRolloutUpdate(oldObj, newObj interface{}) {
+	old, ok := oldObj.(*v1alpha1.KlusterRollout)
+	if !ok {
+		return
+	}
+	rollout, ok := newObj.(*v1alpha1.KlusterRollout)
+	if !ok || old.Generation == rollout.Generation {
+		return
+	}
+	c.enqueueRollout(rollout)
+}
+
+func (c *Controller) enqueueRollout(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.ErrorS(err, "getting key of KlusterRollout")
+		return
+	}
+	c.rolloutWq.Add(key)
+}
+
+// 진행 중인 rollout이 고른 Kluster의 status가 바뀌면 poll 주기를 기다리지 않고 rollout을 다시 처리한다.
+func (c *Controller) handleRolloutKlusterUpdate(_, newObj interface{}) {
+	kluster, ok := newObj.(*v1alpha1.Kluster)
+	if !ok {
+		return
+	}
+	rollouts, err := c.rLister.KlusterRollouts(kluster.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "listing KlusterRollouts")
+		return
+	}
+	for _, rollout := range rollouts {
+		if rollout.Status.Phase != v1alpha1.RolloutPhaseProgressing {
+			continue
+		}
+		for _, entry := range rollout.Status.Klusters {
+			if entry.Name == kluster.Name && entry.Wave == rollout.Status.CurrentWave {
+				c.enqueueRollout(rollout)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	klister "github.com/inspirit941/kluster/pkg/client/listers/inspirit941.dev/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+	"reflect"
+	"testing"
+)
+
+func TestRolloutWave(t *testing.T) {
+	tests := []struct {
+		name          string
+		canary        int32
+		maxConcurrent int32
+		want          []int32
+	}{
+		{name: "one canary, one at a time", canary: 1, maxConcurrent: 1, want: []int32{0, 1, 2, 3, 4}},
+		{name: "one canary, two at a time", canary: 1, maxConcurrent: 2, want: []int32{0, 1, 1, 2, 2}},
+		{name: "two canaries, three at a time", canary: 2, maxConcurrent: 3, want: []int32{0, 0, 1, 1, 1}},
+		{name: "no canary", canary: 0, maxConcurrent: 2, want: []int32{0, 0, 1, 1, 2}},
+		{name: "canary larger than fleet", canary: 10, maxConcurrent: 2, want: []int32{0, 0, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int32
+			for i := int32(0); i < int32(len(tt.want)); i++ {
+				got = append(got, rolloutWave(i, tt.canary, tt.maxConcurrent))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("waves = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanRollout(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	// 이름 순서와 다르게 넣어서 정렬되는지 확인한다. kluster-2는 이미 target version이고, kluster-4는 selector에 맞지 않는다.
+	for _, i := range []int{3, 0, 2, 1, 4} {
+		kluster := &v1alpha1.Kluster{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("kluster-%d", i), Namespace: "default", Labels: map[string]string{"env": "prod"}},
+			Spec:       v1alpha1.KlusterSpec{Version: "1.25.4-do.0"},
+			Status:     v1alpha1.KlusterStatus{Version: "1.25.4-do.0"},
+		}
+		switch i {
+		case 2:
+			kluster.Spec.Version, kluster.Status.Version = "1.26.3-do.0", "1.26.3-do.0"
+		case 4:
+			kluster.Labels["env"] = "dev"
+		}
+		if err := indexer.Add(kluster); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := &Controller{kLister: klister.NewKlusterLister(indexer)}
+	rollout := &v1alpha1.KlusterRollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade", Namespace: "default", Generation: 2},
+		Spec: v1alpha1.KlusterRolloutSpec{
+			Selector:      metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			Version:       "1.26.3-do.0",
+			Canary:        pointer.Int32(1),
+			MaxConcurrent: 2,
+		},
+	}
+
+	status := &v1alpha1.KlusterRolloutStatus{}
+	if err := c.planRollout(rollout, status); err != nil {
+		t.Fatalf("planRollout() error = %v", err)
+	}
+	if status.Total != 4 || status.Upgraded != 1 || status.Waves != 2 || status.ObservedGeneration != 2 {
+		t.Errorf("total / upgraded / waves / observedGeneration = %d / %d / %d / %d, want 4 / 1 / 2 / 2",
+			status.Total, status.Upgraded, status.Waves, status.ObservedGeneration)
+	}
+	type entry struct {
+		name  string
+		phase v1alpha1.RolloutKlusterPhase
+		wave  int32
+	}
+	want := []entry{
+		{"kluster-0", v1alpha1.RolloutKlusterPending, 0},
+		{"kluster-1", v1alpha1.RolloutKlusterPending, 1},
+		{"kluster-2", v1alpha1.RolloutKlusterSucceeded, 0},
+		{"kluster-3", v1alpha1.RolloutKlusterPending, 1},
+	}
+	var got []entry
+	for _, k := range status.Klusters {
+		got = append(got, entry{k.Name, k.Phase, k.Wave})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("klusters = %+v, want %+v", got, want)
+	}
+}
+
+func TestUpgraded(t *testing.T) {
+	tests := []struct {
+		name   string
+		status v1alpha1.KlusterStatus
+		want   bool
+	}{
+		{
+			name:   "running the new version",
+			status: v1alpha1.KlusterStatus{ObservedGeneration: 3, Progress: "running", Version: "1.26.3-do.0"},
+			want:   true,
+		},
+		{
+			name:   "spec change not observed yet",
+			status: v1alpha1.KlusterStatus{ObservedGeneration: 2, Progress: "running", Version: "1.26.3-do.0"},
+		},
+		{
+			name:   "still upgrading",
+			status: v1alpha1.KlusterStatus{ObservedGeneration: 3, Progress: "upgrading", Version: "1.26.3-do.0"},
+		},
+		{
+			name:   "old version",
+			status: v1alpha1.KlusterStatus{ObservedGeneration: 3, Progress: "running", Version: "1.25.4-do.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kluster := &v1alpha1.Kluster{ObjectMeta: metav1.ObjectMeta{Generation: 3}, Status: tt.status}
+			if got := upgraded(kluster, "1.26.3-do.0"); got != tt.want {
+				t.Errorf("upgraded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1/validation"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"time"
+)
+
+// controller가 멈춰 있던 동안 놓친 실행 시각을 찾을 때 schedule 하나당 확인할 최대 횟수.
+// 매분 실행되는 schedule 기준으로 약 1주일.
+const maxMissedSchedules = 10000
+
+// applySchedules: node pool schedule을 평가해서, schedule이 정한 count를 반영한 Kluster 복사본을 리턴한다.
+// schedule은 status.scheduledScaling에 기록된 다음 실행 시각이 지났을 때 실행되고, 그 schedule의 count는
+// 다음 schedule이 실행되거나 사용자가 spec의 count를 바꿀 때까지 유지된다. (수동 변경이 우선)
+// 이번 reconcile에서 실행된 schedule이 있으면 true를 리턴한다.
+func (c *Controller) applySchedules(ctx context.Context, kluster *v1alpha1.Kluster) (*v1alpha1.Kluster, bool, error) {
+	logger := klog.FromContext(ctx)
+	now := time.Now()
+	effective := kluster.DeepCopy()
+	fired := false
+	var statuses []v1alpha1.ScheduledScalingStatus
+	var next time.Time
+
+	for i := range effective.Spec.NodePools {
+		pool := &effective.Spec.NodePools[i]
+		if len(pool.Schedules) == 0 {
+			continue
+		}
+		status := v1alpha1.ScheduledScalingStatus{NodePool: pool.Name}
+		for _, s := range kluster.Status.ScheduledScaling {
+			if s.NodePool == pool.Name {
+				status = *s.DeepCopy()
+			}
+		}
+
+		// 처음 평가하는 pool은 지난 실행 시각을 소급해서 적용하지 않고 다음 실행 시각만 기록한다.
+		if status.NextScheduleTime != nil && !now.Before(status.NextScheduleTime.Time) {
+			if schedule, at := latestFired(ctx, pool.Schedules, status.NextScheduleTime.Time, now); schedule != nil {
+				lastScheduleTime := metav1.NewTime(at)
+				status.ActiveSchedule = schedule.Name
+				status.Count = schedule.Count
+				status.SpecCount = pool.Count
+				status.LastScheduleTime = &lastScheduleTime
+				fired = true
+				logger.Info("node pool schedule fired", "nodePool", pool.Name, "schedule", schedule.Name, "count", schedule.Count)
+				c.recorder.Eventf(kluster, corev1.EventTypeNormal, "ScheduledScaling", "Node pool %s is scaled to %d nodes by schedule %s.", pool.Name, schedule.Count, schedule.Name)
+			}
+		}
+
+		if status.ActiveSchedule != "" {
+			schedule := findSchedule(pool.Schedules, status.ActiveSchedule)
+			switch {
+			case schedule == nil:
+				logger.Info("active schedule was removed, using the count in spec", "nodePool", pool.Name, "schedule", status.ActiveSchedule)
+				clearActiveSchedule(&status)
+			case pool.Count != status.SpecCount:
+				// schedule이 실행된 뒤 spec의 count가 바뀌었으면 사용자의 수동 변경을 따른다.
+				logger.Info("count in spec changed after the schedule fired, using the count in spec", "nodePool", pool.Name, "schedule", status.ActiveSchedule, "count", pool.Count)
+				clearActiveSchedule(&status)
+			default:
+				status.Count = schedule.Count
+				pool.Count = schedule.Count
+			}
+		}
+
+		status.NextSchedule, status.NextScheduleTime, status.NextCount = "", nil, 0
+		if schedule, at := nextSchedule(ctx, pool.Schedules, now); schedule != nil {
+			nextScheduleTime := metav1.NewTime(at)
+			status.NextSchedule = schedule.Name
+			status.NextScheduleTime = &nextScheduleTime
+			status.NextCount = schedule.Count
+			if next.IsZero() || at.Before(next) {
+				next = at
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	if err := c.mutateStatus(ctx, kluster, func(status *v1alpha1.KlusterStatus) {
+		status.ScheduledScaling = statuses
+	}); err != nil {
+		return nil, false, err
+	}
+	effective.Status.ScheduledScaling = statuses
+
+	// 다음 schedule 실행 시각에 맞춰 다시 처리한다.
+	if !next.IsZero() {
+		key, err := cache.MetaNamespaceKeyFunc(kluster)
+		if err != nil {
+			return nil, false, err
+		}
+		c.wq.AddAfter(key, time.Until(next))
+	}
+	return effective, fired, nil
+}
+
+// from ~ now 사이에 실행 시각이 있는 schedule 중 가장 늦게 실행된 schedule과 그 시각. 같은 시각이면 목록에서 뒤에 있는 schedule.
+func latestFired(ctx context.Context, schedules []v1alpha1.ScalingSchedule, from, now time.Time) (*v1alpha1.ScalingSchedule, time.Time) {
+	var latest *v1alpha1.ScalingSchedule
+	var latestAt time.Time
+	for i := range schedules {
+		parsed, location, err := validation.ParseSchedule(schedules[i])
+		if err != nil {
+			klog.FromContext(ctx).Error(err, "parsing schedule", "schedule", schedules[i].Name)
+			continue
+		}
+		// cron은 분 단위이므로 from 자체가 실행 시각인 경우도 포함되도록 1초 전부터 찾는다.
+		var last time.Time
+		for t, n := parsed.Next(from.Add(-time.Second).In(location)), 0; !t.IsZero() && !t.After(now) && n < maxMissedSchedules; t, n = parsed.Next(t), n+1 {
+			last = t
+		}
+		if !last.IsZero() && !last.Before(latestAt) {
+			latest, latestAt = &schedules[i], last
+		}
+	}
+	return latest, latestAt
+}
+
+// now 이후 가장 먼저 실행되는 schedule과 그 시각
+func nextSchedule(ctx context.Context, schedules []v1alpha1.ScalingSchedule, now time.Time) (*v1alpha1.ScalingSchedule, time.Time) {
+	var earliest *v1alpha1.ScalingSchedule
+	var earliestAt time.Time
+	for i := range schedules {
+		parsed, location, err := validation.ParseSchedule(schedules[i])
+		if err != nil {
+			klog.FromContext(ctx).Error(err, "parsing schedule", "schedule", schedules[i].Name)
+			continue
+		}
+		at := parsed.Next(now.In(location))
+		if !at.IsZero() && (earliest == nil || at.Before(earliestAt)) {
+			earliest, earliestAt = &schedules[i], at
+		}
+	}
+	return earliest, earliestAt
+}
+
+func findSchedule(schedules []v1alpha1.ScalingSchedule, name string) *v1alpha1.ScalingSchedule {
+	for i := range schedules {
+		if schedules[i].Name == name {
+			return &schedules[i]
+		}
+	}
+	return nil
+}
+
+func clearActiveSchedule(status *v1alpha1.ScheduledScalingStatus) {
+	status.ActiveSchedule = ""
+	status.Count = 0
+	status.SpecCount = 0
+	status.LastScheduleTime = nil
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func TestLatestFired(t *testing.T) {
+	schedules := []v1alpha1.ScalingSchedule{
+		{Name: "day", Schedule: "0 8 * * *", Count: 3},
+		{Name: "night", Schedule: "0 20 * * *", Count: 1},
+		{Name: "seoul", Schedule: "0 8 * * *", TimeZone: "Asia/Seoul", Count: 2},
+	}
+	at := func(day, hour, minute int) time.Time { return time.Date(2022, 12, day, hour, minute, 0, 0, time.UTC) }
+	tests := []struct {
+		name      string
+		from, now time.Time
+		want      string
+		wantAt    time.Time
+	}{
+		{name: "one schedule", from: at(1, 7, 0), now: at(1, 9, 0), want: "day", wantAt: at(1, 8, 0)},
+		{name: "latest of several", from: at(1, 7, 0), now: at(1, 21, 0), want: "night", wantAt: at(1, 20, 0)},
+		// from 자체가 실행 시각이면 포함한다.
+		{name: "from is the schedule time", from: at(1, 8, 0), now: at(1, 8, 0), want: "day", wantAt: at(1, 8, 0)},
+		{name: "nothing fired", from: at(1, 9, 0), now: at(1, 10, 0)},
+		// Asia/Seoul 08:00은 UTC로 전날 23:00.
+		{name: "time zone", from: at(1, 22, 0), now: at(1, 23, 30), want: "seoul", wantAt: at(1, 23, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, fired := latestFired(context.Background(), schedules, tt.from, tt.now)
+			var got string
+			if schedule != nil {
+				got = schedule.Name
+			}
+			if got != tt.want || (tt.want != "" && !fired.Equal(tt.wantAt)) {
+				t.Errorf("latestFired() = %q at %v, want %q at %v", got, fired, tt.want, tt.wantAt)
+			}
+		})
+	}
+}
+
+func TestNextSchedule(t *testing.T) {
+	schedules := []v1alpha1.ScalingSchedule{
+		{Name: "day", Schedule: "0 8 * * *", Count: 3},
+		{Name: "night", Schedule: "0 20 * * *", Count: 1},
+		// 잘못된 schedule은 건너뛴다.
+		{Name: "invalid", Schedule: "every day", Count: 2},
+	}
+	now := time.Date(2022, 12, 1, 9, 0, 0, 0, time.UTC)
+	schedule, at := nextSchedule(context.Background(), schedules, now)
+	if schedule == nil || schedule.Name != "night" || !at.Equal(time.Date(2022, 12, 1, 20, 0, 0, 0, time.UTC)) {
+		t.Errorf("nextSchedule() = %v at %v, want night at 20:00", schedule, at)
+	}
+}
+
+// scheduledKluster: schedule이 있는 node pool 하나를 가진 Kluster. "minutely"는 매분, "yearly"는 1월 1일에 실행된다.
+func scheduledKluster(count int, status ...v1alpha1.ScheduledScalingStatus) *v1alpha1.Kluster {
+	kluster := runningKluster("kluster")
+	kluster.Spec.NodePools = []v1alpha1.NodePool{{
+		Name:  "web",
+		Size:  "s-2vcpu-2gb",
+		Count: count,
+		Schedules: []v1alpha1.ScalingSchedule{
+			{Name: "minutely", Schedule: "* * * * *", Count: 1},
+			{Name: "yearly", Schedule: "0 0 1 1 *", Count: 5},
+		},
+	}}
+	kluster.Status.ScheduledScaling = status
+	return kluster
+}
+
+func TestApplySchedules(t *testing.T) {
+	past := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+	tests := []struct {
+		name       string
+		kluster    *v1alpha1.Kluster
+		wantFired  bool
+		wantCount  int
+		wantActive string
+	}{
+		// 처음 평가할 때는 지난 실행 시각을 소급하지 않는다.
+		{name: "first evaluation", kluster: scheduledKluster(3), wantCount: 3},
+		{
+			name:       "fired",
+			kluster:    scheduledKluster(3, v1alpha1.ScheduledScalingStatus{NodePool: "web", NextSchedule: "minutely", NextScheduleTime: &past}),
+			wantFired:  true,
+			wantCount:  1,
+			wantActive: "minutely",
+		},
+		{
+			// 다음 schedule이 실행되기 전까지 schedule의 count를 유지한다.
+			name:       "active",
+			kluster:    scheduledKluster(3, v1alpha1.ScheduledScalingStatus{NodePool: "web", ActiveSchedule: "yearly", Count: 5, SpecCount: 3, LastScheduleTime: &past}),
+			wantCount:  5,
+			wantActive: "yearly",
+		},
+		{
+			// schedule이 실행된 뒤 spec의 count를 바꾸면 수동 변경을 따른다.
+			name:      "count changed in spec",
+			kluster:   scheduledKluster(4, v1alpha1.ScheduledScalingStatus{NodePool: "web", ActiveSchedule: "yearly", Count: 5, SpecCount: 3, LastScheduleTime: &past}),
+			wantCount: 4,
+		},
+		{
+			name:      "active schedule removed",
+			kluster:   scheduledKluster(3, v1alpha1.ScheduledScalingStatus{NodePool: "web", ActiveSchedule: "weekend", Count: 2, SpecCount: 3, LastScheduleTime: &past}),
+			wantCount: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, recorder := newTestController(t, Options{}, tt.kluster)
+
+			kluster := tt.kluster.DeepCopy()
+			effective, fired, err := c.applySchedules(context.Background(), kluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fired != tt.wantFired {
+				t.Errorf("fired = %v, want %v", fired, tt.wantFired)
+			}
+			if got := effective.Spec.NodePools[0].Count; got != tt.wantCount {
+				t.Errorf("effective count = %d, want %d", got, tt.wantCount)
+			}
+			// 넘겨받은 Kluster의 spec은 바꾸지 않는다.
+			if kluster.Spec.NodePools[0].Count != tt.kluster.Spec.NodePools[0].Count {
+				t.Errorf("count in spec was changed to %d", kluster.Spec.NodePools[0].Count)
+			}
+			if got := hasEvent(recorder, "ScheduledScaling"); got != tt.wantFired {
+				t.Errorf("ScheduledScaling event = %v, want %v", got, tt.wantFired)
+			}
+
+			statuses := getKluster(t, c, "kluster").Status.ScheduledScaling
+			if len(statuses) != 1 {
+				t.Fatalf("scheduledScaling = %+v", statuses)
+			}
+			status := statuses[0]
+			if status.NodePool != "web" || status.ActiveSchedule != tt.wantActive {
+				t.Errorf("nodePool = %q, activeSchedule = %q, want web, %q", status.NodePool, status.ActiveSchedule, tt.wantActive)
+			}
+			// 다음 실행은 매분 실행되는 schedule.
+			if status.NextSchedule != "minutely" || status.NextCount != 1 || status.NextScheduleTime == nil || !status.NextScheduleTime.After(time.Now()) {
+				t.Errorf("next = %q (%d) at %v, want minutely in the future", status.NextSchedule, status.NextCount, status.NextScheduleTime)
+			}
+		})
+	}
+}
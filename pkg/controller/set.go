@@ -0,0 +1,263 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	klusterscheme "github.com/inspirit941/kluster/pkg/client/clientset/versioned/scheme"
+	"hash/fnv"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"strings"
+)
+
+var klusterSetKind = v1alpha1.SchemeGroupVersion.WithKind("KlusterSet")
+
+func (c *Controller) setWorker() {
+	for c.processNextQueueItem(c.setWq, "klusterSet", c.syncSet) {
+	}
+}
+
+// syncSet: spec.regions의 region마다 "<set name>-<region>" Kluster를 만들고, 공통 spec이 바뀌면 모든 Kluster에 반영한다.
+// spec에서 빠진 region의 Kluster는 지우고, 각 Kluster의 상태를 모아서 status에 기록한다.
+func (c *Controller) syncSet(ctx context.Context, ns, name string) error {
+	logger := klog.FromContext(ctx)
+	set, err := c.sLister.KlusterSets(ns).Get(name)
+	// 삭제된 set의 Kluster는 ownerReference로 garbage collector가 지운다.
+	if apierrors.IsNotFound(err) {
+		logger.V(4).Info("KlusterSet was deleted")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if set.DeletionTimestamp != nil {
+		return nil
+	}
+
+	owned, err := c.kLister.Klusters(ns).List(labels.SelectorFromSet(labels.Set{v1alpha1.SetLabel: set.Name}))
+	if err != nil {
+		return err
+	}
+	byRegion := map[string]*v1alpha1.Kluster{}
+	for _, kluster := range owned {
+		if !metav1.IsControlledBy(kluster, set) {
+			continue
+		}
+		// label이 없는 Kluster는 이전 버전이 만든 것이다. spec.region으로 찾고, 업데이트할 때 label을 붙인다.
+		region := kluster.Labels[v1alpha1.SetRegionLabel]
+		if region == "" {
+			region = kluster.Spec.Region
+		}
+		byRegion[region] = kluster
+	}
+
+	// region 하나가 실패해도 나머지 region은 진행하고, 실패한 내용은 모아서 리턴한다.
+	var errs []string
+	regions := sets.NewString(set.Spec.Regions...)
+	for _, region := range set.Spec.Regions {
+		kluster, err := c.syncSetKluster(ctx, set, region, byRegion[region])
+		if err != nil {
+			logger.Error(err, "syncing Kluster of the set", "region", region)
+			errs = append(errs, region+": "+err.Error())
+			continue
+		}
+		byRegion[region] = kluster
+	}
+	for region, kluster := range byRegion {
+		if regions.Has(region) || kluster.DeletionTimestamp != nil {
+			continue
+		}
+		logger.Info("deleting Kluster of the region removed from the set", "region", region, "kluster", klog.KObj(kluster))
+		err := c.klient.Inspirit941V1alpha1().Klusters(ns).Delete(ctx, kluster.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &kluster.UID},
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, region+": "+err.Error())
+			continue
+		}
+		c.recorder.Event(set, corev1.EventTypeNormal, "KlusterDeleted", "Kluster "+kluster.Name+" was deleted because region "+region+" was removed.")
+	}
+
+	var statuses []v1alpha1.KlusterSetRegionStatus
+	var ready int32
+	var notReady []string
+	for _, region := range set.Spec.Regions {
+		status := v1alpha1.KlusterSetRegionStatus{Region: region}
+		if kluster := byRegion[region]; kluster != nil {
+			status.Kluster = kluster.Name
+			status.KlusterID = kluster.Status.KlusterID
+			status.Progress = kluster.Status.Progress
+			status.Ready = isReady(kluster)
+		}
+		if status.Ready {
+			ready++
+		} else {
+			notReady = append(notReady, region)
+		}
+		statuses = append(statuses, status)
+	}
+	condition := metav1.Condition{
+		Type:    v1alpha1.ConditionReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AllRegionsReady",
+		Message: "clusters of all regions are running",
+	}
+	if len(notReady) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RegionsNotReady"
+		condition.Message = "clusters of regions " + strings.Join(notReady, ", ") + " are not running"
+	}
+	if len(errs) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SyncFailed"
+		condition.Message = strings.Join(errs, "; ")
+	}
+	if err := c.mutateSetStatus(ctx, set, func(status *v1alpha1.KlusterSetStatus) {
+		status.DesiredRegions = int32(len(set.Spec.Regions))
+		status.ReadyRegions = ready
+		status.Regions = statuses
+		if len(errs) == 0 {
+			status.ObservedGeneration = set.Generation
+		}
+		condition.ObservedGeneration = set.Generation
+		meta.SetStatusCondition(&status.Conditions, condition)
+	}); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("syncing Klusters of the set: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// syncSetKluster: region의 Kluster가 없으면 만들고, 있으면 spec이 set의 공통 spec과 같도록 업데이트한다.
+// 기본값이 채워진 spec끼리 비교해야 webhook이 채운 값 때문에 매번 업데이트하지 않는다.
+func (c *Controller) syncSetKluster(ctx context.Context, set *v1alpha1.KlusterSet, region string, current *v1alpha1.Kluster) (*v1alpha1.Kluster, error) {
+	desired := &v1alpha1.Kluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      setKlusterName(set.Name, region),
+			Namespace: set.Namespace,
+		},
+		Spec: v1alpha1.MergeTemplate(v1alpha1.KlusterSpec{Region: region}, set.Spec.KlusterSpec),
+	}
+	desired.Spec.Template = set.Spec.Template
+	klusterscheme.Scheme.Default(desired)
+
+	if current == nil {
+		desired.Labels = map[string]string{v1alpha1.SetLabel: set.Name, v1alpha1.SetRegionLabel: region}
+		desired.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(set, klusterSetKind)}
+		kluster, err := c.klient.Inspirit941V1alpha1().Klusters(set.Namespace).Create(ctx, desired, metav1.CreateOptions{})
+		if err != nil {
+			// 같은 이름의 Kluster가 set과 관계없이 이미 있으면 가져오지 않는다.
+			c.recorder.Event(set, corev1.EventTypeWarning, "KlusterCreationFailed", err.Error())
+			return nil, err
+		}
+		klog.FromContext(ctx).Info("created Kluster for the region", "region", region, "kluster", klog.KObj(kluster))
+		c.recorder.Event(set, corev1.EventTypeNormal, "KlusterCreated", "Kluster "+kluster.Name+" was created for region "+region+".")
+		return kluster, nil
+	}
+
+	if equality.Semantic.DeepEqual(current.Spec, desired.Spec) && current.Labels[v1alpha1.SetRegionLabel] == region {
+		return current, nil
+	}
+	kluster := current.DeepCopy()
+	kluster.Spec = desired.Spec
+	if kluster.Labels == nil {
+		kluster.Labels = map[string]string{}
+	}
+	kluster.Labels[v1alpha1.SetRegionLabel] = region
+	kluster, err := c.klient.Inspirit941V1alpha1().Klusters(set.Namespace).Update(ctx, kluster, metav1.UpdateOptions{})
+	if err != nil {
+		// region / vpcUUID처럼 생성 후 바꿀 수 없는 값을 바꾸면 CRD validation에서 거부된다.
+		c.recorder.Event(set, corev1.EventTypeWarning, "KlusterUpdateFailed", err.Error())
+		return nil, err
+	}
+	klog.FromContext(ctx).Info("updated Kluster spec from the set", "region", region, "kluster", klog.KObj(kluster))
+	return kluster, nil
+}
+
+// setKlusterName: region Kluster의 이름 "<set name>-<region>".
+// Kluster 이름은 클러스터 이름(63자 제한)으로도 쓰이므로, 길면 set 이름을 자르고 잘리기 전 이름의 hash를 붙여 겹치지 않게 한다.
+func setKlusterName(set, region string) string {
+	name := set + "-" + region
+	if len(name) <= utilvalidation.DNS1123LabelMaxLength {
+		return name
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	suffix := fmt.Sprintf("-%08x-%s", h.Sum32(), region)
+	prefix := set
+	if n := utilvalidation.DNS1123LabelMaxLength - len(suffix); len(prefix) > n {
+		prefix = prefix[:n]
+	}
+	return strings.TrimRight(prefix, "-.") + suffix
+}
+
+// 최신 KlusterSet을 조회해서 mutate를 적용한 뒤, 바뀐 내용이 있을 때만 status subresource를 업데이트한다.
+func (c *Controller) mutateSetStatus(ctx context.Context, set *v1alpha1.KlusterSet, mutate func(status *v1alpha1.KlusterSetStatus)) error {
+	s, err := c.klient.Inspirit941V1alpha1().KlusterSets(set.Namespace).Get(ctx, set.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	status := s.Status.DeepCopy()
+	mutate(&s.Status)
+	if equality.Semantic.DeepEqual(status, &s.Status) {
+		return nil
+	}
+	_, err = c.klient.Inspirit941V1alpha1().KlusterSets(s.Namespace).UpdateStatus(ctx, s, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *Controller) handleSetAdd(obj interface{}) {
+	c.enqueueSet(obj)
+}
+
+// status 업데이트와 resync는 무시한다. Kluster 상태 변화는 handleSetKluster로 들어온다.
+func (c *Controller) handleSetUpdate(oldObj, newObj interface{}) {
+	old, ok := oldObj.(*v1alpha1.KlusterSet)
+	if !ok {
+		return
+	}
+	set, ok := newObj.(*v1alpha1.KlusterSet)
+	if !ok || old.Generation == set.Generation {
+		return
+	}
+	c.enqueueSet(set)
+}
+
+func (c *Controller) enqueueSet(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.ErrorS(err, "getting key of KlusterSet")
+		return
+	}
+	c.setWq.Add(key)
+}
+
+// set의 Kluster가 생성 / 삭제되거나 spec / status가 바뀌면 set을 다시 처리한다.
+// 직접 수정된 Kluster spec은 set의 spec으로 되돌려진다.
+func (c *Controller) handleSetKluster(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	kluster, ok := obj.(*v1alpha1.Kluster)
+	if !ok {
+		return
+	}
+	if set := kluster.Labels[v1alpha1.SetLabel]; set != "" {
+		c.setWq.Add(kluster.Namespace + "/" + set)
+	}
+}
+
+func (c *Controller) handleSetKlusterUpdate(_, newObj interface{}) {
+	c.handleSetKluster(newObj)
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	klusterscheme "github.com/inspirit941/kluster/pkg/client/clientset/versioned/scheme"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"strings"
+	"testing"
+)
+
+func TestSetKlusterName(t *testing.T) {
+	if got := setKlusterName("web", "nyc1"); got != "web-nyc1" {
+		t.Errorf("name = %q, want %q", got, "web-nyc1")
+	}
+
+	long := strings.Repeat("a", 70)
+	got := setKlusterName(long, "nyc1")
+	if len(got) > 63 {
+		t.Errorf("name %q is longer than 63 characters", got)
+	}
+	if !strings.HasSuffix(got, "-nyc1") {
+		t.Errorf("name %q does not end with the region", got)
+	}
+	if got != setKlusterName(long, "nyc1") {
+		t.Errorf("name is not stable")
+	}
+	// 잘린 부분만 다른 set끼리 이름이 겹치지 않아야 한다.
+	if other := setKlusterName(long+"b", "nyc1"); other == got {
+		t.Errorf("sets %q and %q got the same name %q", long, long+"b", got)
+	}
+}
+
+func newSet(regions ...string) *v1alpha1.KlusterSet {
+	return &v1alpha1.KlusterSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "uid-web", Generation: 1},
+		Spec: v1alpha1.KlusterSetSpec{
+			Regions:     regions,
+			KlusterSpec: v1alpha1.KlusterTemplateSpec{Version: "1.25.4-do.0"},
+		},
+	}
+}
+
+// setKluster: set이 region에 만든 Kluster. ready면 클러스터가 running이다.
+func setKluster(set *v1alpha1.KlusterSet, region string, ready bool) *v1alpha1.Kluster {
+	kluster := newKluster(setKlusterName(set.Name, region))
+	kluster.Labels = map[string]string{v1alpha1.SetLabel: set.Name, v1alpha1.SetRegionLabel: region}
+	kluster.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(set, klusterSetKind)}
+	kluster.Spec = v1alpha1.MergeTemplate(v1alpha1.KlusterSpec{Region: region}, set.Spec.KlusterSpec)
+	klusterscheme.Scheme.Default(kluster)
+	if ready {
+		kluster.Status.KlusterID = "cluster-" + region
+		kluster.Status.Progress = "running"
+	}
+	return kluster
+}
+
+func getSet(t *testing.T, c *Controller) *v1alpha1.KlusterSet {
+	t.Helper()
+	set, err := c.klient.Inspirit941V1alpha1().KlusterSets("default").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return set
+}
+
+func TestSyncSet(t *testing.T) {
+	set := newSet("nyc1", "fra1")
+	outdated := setKluster(set, "fra1", true)
+	outdated.Spec.Version = "1.24.8-do.0"
+	// region label이 없는 Kluster는 spec.region으로 찾는다.
+	unlabeled := setKluster(set, "fra1", true)
+	delete(unlabeled.Labels, v1alpha1.SetRegionLabel)
+
+	tests := []struct {
+		name       string
+		klusters   []runtime.Object
+		wantNames  []string
+		wantReady  int32
+		wantReason string
+	}{
+		{
+			name:       "create",
+			wantNames:  []string{"web-fra1", "web-nyc1"},
+			wantReason: "RegionsNotReady",
+		},
+		{
+			name:       "all ready",
+			klusters:   []runtime.Object{setKluster(set, "nyc1", true), setKluster(set, "fra1", true)},
+			wantNames:  []string{"web-fra1", "web-nyc1"},
+			wantReady:  2,
+			wantReason: "AllRegionsReady",
+		},
+		{
+			name:       "spec changed",
+			klusters:   []runtime.Object{setKluster(set, "nyc1", true), outdated},
+			wantNames:  []string{"web-fra1", "web-nyc1"},
+			wantReady:  2,
+			wantReason: "AllRegionsReady",
+		},
+		{
+			name:       "without region label",
+			klusters:   []runtime.Object{setKluster(set, "nyc1", true), unlabeled},
+			wantNames:  []string{"web-fra1", "web-nyc1"},
+			wantReady:  2,
+			wantReason: "AllRegionsReady",
+		},
+		{
+			name:       "region removed",
+			klusters:   []runtime.Object{setKluster(set, "nyc1", true), setKluster(set, "fra1", false), setKluster(set, "sfo3", true)},
+			wantNames:  []string{"web-fra1", "web-nyc1"},
+			wantReady:  1,
+			wantReason: "RegionsNotReady",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, _ := newTestController(t, Options{}, append(tt.klusters, set)...)
+
+			if err := c.syncSet(context.Background(), "default", "web"); err != nil {
+				t.Fatal(err)
+			}
+			if names := klusterNames(t, c); !equalStrings(names, tt.wantNames) {
+				t.Errorf("Klusters = %v, want %v", names, tt.wantNames)
+			}
+			for _, region := range set.Spec.Regions {
+				kluster := getKluster(t, c, setKlusterName(set.Name, region))
+				if kluster.Spec.Region != region || kluster.Spec.Version != "1.25.4-do.0" || !metav1.IsControlledBy(kluster, set) ||
+					kluster.Labels[v1alpha1.SetLabel] != "web" || kluster.Labels[v1alpha1.SetRegionLabel] != region {
+					t.Errorf("Kluster %s has spec %+v, labels %v and owners %v", kluster.Name, kluster.Spec, kluster.Labels, kluster.OwnerReferences)
+				}
+			}
+
+			status := getSet(t, c).Status
+			if status.DesiredRegions != 2 || status.ReadyRegions != tt.wantReady || len(status.Regions) != 2 || status.ObservedGeneration != 1 {
+				t.Errorf("status = %+v, want 2 desired and %d ready regions", status, tt.wantReady)
+			}
+			if status.Regions[0].Region != "nyc1" || status.Regions[0].Kluster != "web-nyc1" {
+				t.Errorf("status of the first region = %+v", status.Regions[0])
+			}
+			if condition := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionReady); condition == nil || condition.Reason != tt.wantReason {
+				t.Errorf("Ready condition = %+v, want reason %s", condition, tt.wantReason)
+			}
+		})
+	}
+}
+
+// set과 관계없는 같은 이름의 Kluster는 가져오지 않고, 나머지 region은 계속 진행한다.
+func TestSyncSetNameTaken(t *testing.T) {
+	set := newSet("nyc1", "fra1")
+	taken := newKluster("web-nyc1")
+	c, _, recorder := newTestController(t, Options{}, set, taken)
+
+	if err := c.syncSet(context.Background(), "default", "web"); err == nil {
+		t.Fatal("syncSet() returned no error")
+	}
+	if kluster := getKluster(t, c, "web-nyc1"); len(kluster.OwnerReferences) != 0 {
+		t.Errorf("owners of the existing Kluster = %v, want none", kluster.OwnerReferences)
+	}
+	if _, err := c.klient.Inspirit941V1alpha1().Klusters("default").Get(context.Background(), "web-fra1", metav1.GetOptions{}); apierrors.IsNotFound(err) {
+		t.Error("Kluster of the other region was not created")
+	}
+	status := getSet(t, c).Status
+	if condition := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionReady); condition == nil || condition.Reason != "SyncFailed" {
+		t.Errorf("Ready condition = %+v, want reason SyncFailed", condition)
+	}
+	// 실패한 region이 있으면 observedGeneration을 올리지 않는다.
+	if status.ObservedGeneration != 0 {
+		t.Errorf("observedGeneration = %d, want 0", status.ObservedGeneration)
+	}
+	if !hasEvent(recorder, "KlusterCreationFailed") {
+		t.Error("no KlusterCreationFailed event")
+	}
+}
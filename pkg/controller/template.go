@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	klusterscheme "github.com/inspirit941/kluster/pkg/client/clientset/versioned/scheme"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Kluster informer의 index. "<namespace>/<template name>"으로 template을 참조하는 Kluster를 찾는다.
+const templateIndex = "template"
+
+func indexByTemplate(obj interface{}) ([]string, error) {
+	kluster, ok := obj.(*v1alpha1.Kluster)
+	if !ok || kluster.Spec.Template == "" {
+		return nil, nil
+	}
+	return []string{kluster.Namespace + "/" + kluster.Spec.Template}, nil
+}
+
+// resolveSpec: spec.template이 있으면 KlusterTemplate과 합친 뒤 기본값을 채운 복사본을 리턴한다.
+// lister가 준 object는 cache를 가리키므로 항상 복사본을 만든다. template이 없으면 NotFound 에러를 리턴함.
+func (c *Controller) resolveSpec(kluster *v1alpha1.Kluster) (*v1alpha1.Kluster, *v1alpha1.KlusterTemplate, error) {
+	resolved := kluster.DeepCopy()
+	var template *v1alpha1.KlusterTemplate
+	if name := kluster.Spec.Template; name != "" {
+		var err error
+		template, err = c.tLister.KlusterTemplates(kluster.Namespace).Get(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		resolved.Spec = v1alpha1.MergeTemplate(kluster.Spec, template.Spec)
+	}
+	klusterscheme.Scheme.Default(resolved)
+	return resolved, template, nil
+}
+
+// template이 생성 / 변경 / 삭제되면 그 template을 참조하는 Kluster를 다시 처리한다.
+func (c *Controller) handleTemplateAdd(obj interface{}) {
+	c.enqueueTemplateKlusters(obj)
+}
+
+// resync(resourceVersion이 같음)와 metadata만 바뀐 경우는 무시한다.
+func (c *Controller) handleTemplateUpdate(oldObj, newObj interface{}) {
+	old, ok := oldObj.(*v1alpha1.KlusterTemplate)
+	if !ok {
+		return
+	}
+	template, ok := newObj.(*v1alpha1.KlusterTemplate)
+	if !ok || old.Generation == template.Generation {
+		return
+	}
+	c.enqueueTemplateKlusters(template)
+}
+
+func (c *Controller) handleTemplateDel(obj interface{}) {
+	c.enqueueTemplateKlusters(obj)
+}
+
+func (c *Controller) enqueueTemplateKlusters(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.ErrorS(err, "getting key of KlusterTemplate")
+		return
+	}
+	klusters, err := c.klusterIndexer.ByIndex(templateIndex, key)
+	if err != nil {
+		klog.ErrorS(err, "listing Klusters using KlusterTemplate", "template", key)
+		return
+	}
+	klog.V(4).InfoS("KlusterTemplate changed, enqueueing Klusters", "template", key, "klusters", len(klusters))
+	for _, kluster := range klusters {
+		c.enqueue(kluster)
+	}
+}
+
+func templateMessage(template *v1alpha1.KlusterTemplate) string {
+	return fmt.Sprintf("spec is merged with KlusterTemplate %s (generation %d)", template.Name, template.Generation)
+}
@@ -2,61 +2,514 @@ package digitalocean
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/digitalocean/godo"
 	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1/validation"
+	"github.com/inspirit941/kluster/pkg/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 )
 
+var tracer = tracing.Tracer("pkg/digitalocean")
+
+// godo.NewFromToken과 같은 client를 만들되, 모든 HTTP 호출이 span으로 남도록 otelhttp transport를 끼워넣는다.
+// span은 ctx에 들어있는 reconcile span의 child로 생성된다.
+func (c *Client) newClient(token string) *godo.Client {
+	transport := otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return "DigitalOcean " + r.Method + " " + r.URL.Path
+		}),
+	)
+	httpClient := &http.Client{
+		Transport: &oauth2.Transport{
+			Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
+			Base:   transport,
+		},
+	}
+	client := godo.NewClient(httpClient)
+	if c.apiURL != nil {
+		client.BaseURL = c.apiURL
+	}
+	return client
+}
+
+// Client: controller가 사용하는 provider layer. DigitalOcean 호출에 필요한 token은 Kluster spec의 secret에서 가져온다.
+// dryRun이면 조회(GET)는 그대로 하고, 클러스터를 변경하는 호출은 실행하지 않고 요청 내용을 로그 / 이벤트로만 남긴다.
+// managementClusterID는 이 controller가 돌고 있는 클러스터의 ID로, 생성한 클러스터의 owner tag에 들어간다.
+type Client struct {
+	kube                kubernetes.Interface
+	recorder            record.EventRecorder
+	dryRun              bool
+	managementClusterID string
+
+	// droplet size slug별 월 가격 cache. 가격은 자주 바뀌지 않으므로 pricesTTL 동안 다시 조회하지 않는다.
+	pricesMu sync.Mutex
+	prices   map[string]float64
+	pricesAt time.Time
+
+	// DigitalOcean API 주소. 테스트에서 fake API 서버를 가리킬 때만 설정하고, nil이면 godo 기본값을 사용한다.
+	apiURL *url.URL
+}
+
+func NewClient(kube kubernetes.Interface, recorder record.EventRecorder, dryRun bool, managementClusterID string) *Client {
+	return &Client{
+		kube:                kube,
+		recorder:            recorder,
+		dryRun:              dryRun,
+		managementClusterID: managementClusterID,
+	}
+}
+
+// dry-run 때문에 create / delete 같은 호출이 실행되지 않았음을 알리는 에러. controller는 이 에러를 실패로 보지 않는다.
+var ErrDryRun = errors.New("DigitalOcean API call skipped by dry-run")
+
+// dry-run 이벤트 메시지에 넣을 payload 최대 길이. 전체 payload는 로그에 남는다.
+const maxEventPayload = 512
+
+// mutate: 클러스터를 변경하는 DigitalOcean 호출은 모두 이 함수를 거친다.
+// dry-run이면 call을 실행하지 않고 false를 리턴한다. DryRun 이벤트는 obj(보통 Kluster)에 기록된다.
+func (c *Client) mutate(ctx context.Context, obj runtime.Object, action string, request interface{}, call func() error) (bool, error) {
+	if !c.dryRun {
+		return true, call()
+	}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return false, err
+	}
+	klog.FromContext(ctx).Info("dry-run: skipping DigitalOcean API call", "action", action, "request", string(payload))
+	trace.SpanFromContext(ctx).AddEvent("dry-run", trace.WithAttributes(attribute.String("digitalocean.action", action)))
+	message := string(payload)
+	if len(message) > maxEventPayload {
+		message = message[:maxEventPayload] + "..."
+	}
+	c.recorder.Eventf(obj, corev1.EventTypeNormal, "DryRun", "Would call Digital Ocean %s: %s", action, message)
+	return false, nil
+}
+
+// Kluster spec의 token secret으로 godo client를 만든다.
+func (c *Client) godoClient(ctx context.Context, k *v1alpha1.Kluster) (*godo.Client, error) {
+	// digitalOcean은 토큰을 토대로 K8S secret 정보 가져와서 수행하는 방식
+	token, err := getToken(ctx, c.kube, KlusterTokenSecret(k))
+	if err != nil {
+		return nil, err
+	}
+	return c.newClient(token), nil
+}
+
 // https://docs.digitalocean.com/reference/api/api-reference/#tag/Kubernetes
-func Create(c kubernetes.Interface, spec v1alpha1.KlusterSpec) (string, error) {
-	token, err := getToken(c, spec.TokenSecret)
+// owner tag(ownerTags)는 spec.tags와 함께 클러스터에 붙는다. dry-run이면 ErrDryRun을 리턴한다.
+func (c *Client) Create(ctx context.Context, k *v1alpha1.Kluster) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "digitalocean.Create")
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	spec := k.Spec
+	client, err := c.godoClient(ctx, k)
 	if err != nil {
 		return "", err
 	}
-	// digitalOcean은 토큰을 토대로 K8S secret 정보 가져와서 수행하는 방식
-	client := godo.NewFromToken(token)
+	// 이전 reconcile에서 생성 요청은 성공했지만 status에 cluster ID를 기록하지 못했다면, 같은 클러스터를 다시 만들지 않는다.
+	if id, err := findOwnedCluster(ctx, client, k.UID); err != nil {
+		return "", err
+	} else if id != "" {
+		klog.FromContext(ctx).Info("found cluster already created for this Kluster", "clusterID", id)
+		span.SetAttributes(attribute.String("digitalocean.cluster_id", id))
+		return id, nil
+	}
+	// spec은 controller에서 validation 패키지로 검증된 상태로 넘어온다. (node pool이 최소 1개 이상)
 	request := &godo.KubernetesClusterCreateRequest{
-		Name:        spec.Name,
-		VersionSlug: spec.Version,
-		RegionSlug:  spec.Region,
-		// todo: nodepool input 값의 validation, 여러 개의 nodepool 리스트 요청일 경우 리스트로 request 생성하기.
-		NodePools: []*godo.KubernetesNodePoolCreateRequest{
-			{
-				Size:  spec.NodePools[0].Size,
-				Name:  spec.NodePools[0].Name,
-				Count: spec.NodePools[0].Count,
-			},
-		},
+		Name:         spec.Name,
+		VersionSlug:  spec.Version,
+		RegionSlug:   spec.Region,
+		VPCUUID:      spec.VPCUUID,
+		Tags:         append(append([]string{}, spec.Tags...), c.ownerTags(k)...),
+		HA:           pointer.BoolDeref(spec.HA, false),
+		AutoUpgrade:  pointer.BoolDeref(spec.AutoUpgrade, false),
+		SurgeUpgrade: pointer.BoolDeref(spec.SurgeUpgrade, false),
+	}
+	if spec.MaintenancePolicy != nil {
+		if request.MaintenancePolicy, err = maintenancePolicy(spec.MaintenancePolicy, nil); err != nil {
+			return "", err
+		}
 	}
-	cluster, _, err := client.Kubernetes.Create(context.Background(), request)
+	for _, pool := range spec.NodePools {
+		request.NodePools = append(request.NodePools, nodePoolCreateRequest(pool))
+	}
+	klog.FromContext(ctx).V(2).Info("calling DigitalOcean create cluster API", "region", request.RegionSlug, "version", request.VersionSlug, "ha", request.HA, "vpc", request.VPCUUID)
+	var cluster *godo.KubernetesCluster
+	ok, err := c.mutate(ctx, k, "create cluster", request, func() (err error) {
+		cluster, _, err = client.Kubernetes.Create(ctx, request)
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
+	if !ok {
+		return "", ErrDryRun
+	}
+	span.SetAttributes(attribute.String("digitalocean.cluster_id", cluster.ID))
 	return cluster.ID, nil
 }
 
-// call k8s api server to get secret from secretName
-func getToken(client kubernetes.Interface, secretName string) (string, error) {
-	namespace := strings.Split(secretName, "/")[0]
-	name := strings.Split(secretName, "/")[1]
-	s, err := client.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+// Update: 생성된 클러스터의 version과 옵션(tags, HA, auto / surge upgrade, maintenance policy, node pool, registry)을 spec에 맞춘다.
+// DigitalOcean에서 변경할 수 없는 값(name, region, VPC)은 validation에서 막고 있으므로 여기서는 다루지 않는다.
+// 실제로 변경 요청을 보냈으면 true를 리턴한다. dry-run이면 변경할 내용을 모두 로그 / 이벤트로 남기고 false를 리턴한다.
+// https://docs.digitalocean.com/reference/api/api-reference/#operation/kubernetes_update_cluster
+func (c *Client) Update(ctx context.Context, k *v1alpha1.Kluster, id string) (_ bool, err error) {
+	ctx, span := tracer.Start(ctx, "digitalocean.Update")
+	span.SetAttributes(attribute.String("digitalocean.cluster_id", id))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+	logger := klog.FromContext(ctx)
+
+	spec := k.Spec
+	client, err := c.godoClient(ctx, k)
+	if err != nil {
+		return false, err
+	}
+	cluster, _, err := client.Kubernetes.Get(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	// 바뀐 필드만 request에 채운다. 아무것도 채워지지 않으면 update API를 호출하지 않음.
+	request := &godo.KubernetesClusterUpdateRequest{}
+	changed := false
+	updated := false
+
+	// version이 latest가 아니고 실제 버전과 다르면 upgrade API를 호출한다. DigitalOcean은 downgrade 요청을 거절함.
+	if spec.Version != validation.LatestVersion && spec.Version != cluster.VersionSlug {
+		upgrade := &godo.KubernetesClusterUpgradeRequest{VersionSlug: spec.Version}
+		logger.V(2).Info("calling DigitalOcean upgrade cluster API", "from", cluster.VersionSlug, "to", spec.Version)
+		ok, err := c.mutate(ctx, k, "upgrade cluster", upgrade, func() error {
+			_, err := client.Kubernetes.Upgrade(ctx, id, upgrade)
+			return err
+		})
+		if err != nil {
+			return false, err
+		}
+		updated = updated || ok
+	}
+	// godo의 update request는 surgeUpgrade에 omitempty가 붙어 있어서 surge upgrade를 끄는 요청은 보낼 수 없다.
+	// ha / autoUpgrade가 spec에 없으면 클러스터의 현재 값을 유지한다. adopt한 클러스터의 설정을 바꾸지 않도록.
+	if spec.HA != nil && *spec.HA && !cluster.HA {
+		request.HA = godo.Bool(true)
+		changed = true
+	}
+	if spec.AutoUpgrade != nil && *spec.AutoUpgrade != cluster.AutoUpgrade {
+		request.AutoUpgrade = godo.Bool(*spec.AutoUpgrade)
+		changed = true
+	}
+	if pointer.BoolDeref(spec.SurgeUpgrade, false) && !cluster.SurgeUpgrade {
+		request.SurgeUpgrade = true
+		changed = true
+	}
+	if spec.MaintenancePolicy != nil {
+		policy, err := maintenancePolicy(spec.MaintenancePolicy, cluster.MaintenancePolicy)
+		if err != nil {
+			return false, err
+		}
+		if cluster.MaintenancePolicy == nil || policy.Day != cluster.MaintenancePolicy.Day || policy.StartTime != cluster.MaintenancePolicy.StartTime {
+			request.MaintenancePolicy = policy
+			changed = true
+		}
+	}
+	if changed {
+		logger.V(2).Info("calling DigitalOcean update cluster API", "request", request)
+		ok, err := c.mutate(ctx, k, "update cluster", request, func() error {
+			_, _, err := client.Kubernetes.Update(ctx, id, request)
+			return err
+		})
+		if err != nil {
+			return false, err
+		}
+		updated = updated || ok
+	}
+
+	// spec.tags가 비어 있으면 사용자 tag는 건드리지 않고, owner tag만 항상 붙어 있도록 맞춘다. (adopt한 클러스터 포함)
+	tags := spec.Tags
+	if len(tags) == 0 {
+		tags = userTags(cluster.Tags)
+	}
+	desiredTags := sets.NewString(tags...).Insert(c.ownerTags(k)...)
+	if current := sets.NewString(withoutProviderTags(cluster.Tags)...); !current.Equal(desiredTags) {
+		logger.V(2).Info("updating cluster tags", "tags", desiredTags.List())
+		ok, err := c.updateClusterTags(ctx, k, client, cluster, desiredTags.List())
+		if err != nil {
+			return false, err
+		}
+		updated = updated || ok
+	}
+
+	// node pool은 cluster update가 아니라 node pool API로 생성 / 변경 / 삭제한다.
+	poolsUpdated, err := c.syncNodePools(ctx, k, client, id, cluster.NodePools)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || poolsUpdated
+
+	// container registry 연동은 cluster update가 아니라 별도 API로 켜고 끈다.
+	if registryEnabled := pointer.BoolDeref(spec.RegistryEnabled, false); registryEnabled != cluster.RegistryEnabled {
+		registryRequest := &godo.KubernetesClusterRegistryRequest{ClusterUUIDs: []string{id}}
+		logger.V(2).Info("calling DigitalOcean registry API", "registryEnabled", registryEnabled)
+		action, call := "add registry", client.Kubernetes.AddRegistry
+		if !registryEnabled {
+			action, call = "remove registry", client.Kubernetes.RemoveRegistry
+		}
+		ok, err := c.mutate(ctx, k, action, registryRequest, func() error {
+			_, err := call(ctx, registryRequest)
+			return err
+		})
+		if err != nil {
+			return false, err
+		}
+		updated = updated || ok
+	}
+	span.SetAttributes(attribute.Bool("digitalocean.cluster_updated", updated))
+	return updated, nil
+}
+
+// spec의 maintenance policy를 godo 타입으로 변환한다. 비어 있는 값은 current(현재 클러스터 설정)의 값을 유지.
+func maintenancePolicy(p *v1alpha1.MaintenancePolicy, current *godo.KubernetesMaintenancePolicy) (*godo.KubernetesMaintenancePolicy, error) {
+	policy := &godo.KubernetesMaintenancePolicy{}
+	if current != nil {
+		policy.Day = current.Day
+		policy.StartTime = current.StartTime
+	}
+	if p.Day != "" {
+		day, err := godo.KubernetesMaintenanceToDay(p.Day)
+		if err != nil {
+			return nil, err
+		}
+		policy.Day = day
+	}
+	if p.StartTime != "" {
+		policy.StartTime = p.StartTime
+	}
+	return policy, nil
+}
+
+// TokenSecret: DigitalOcean token이 저장된 secret. Ref는 "<namespace>/<name>", Key는 token이 들어 있는 data key.
+type TokenSecret struct {
+	Ref string
+	Key string
+}
+
+// KlusterTokenSecret: Kluster가 참조하는 token secret. v1beta1로 지정한 secret key도 반영한다.
+func KlusterTokenSecret(k *v1alpha1.Kluster) TokenSecret {
+	return TokenSecret{Ref: k.Spec.TokenSecret, Key: v1alpha1.TokenSecretKey(k)}
+}
+
+// SplitSecretRef: "<namespace>/<name>" 형식의 token secret 참조를 namespace와 name으로 나눈다.
+func SplitSecretRef(ref string) (namespace, name string, err error) {
+	namespace, name, ok := strings.Cut(ref, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("token secret %q must be <namespace>/<name>", ref)
+	}
+	return namespace, name, nil
+}
+
+// call k8s api server to get secret from secret.Ref
+func getToken(ctx context.Context, client kubernetes.Interface, secret TokenSecret) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "digitalocean.getToken")
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	namespace, name, err := SplitSecretRef(secret.Ref)
 	if err != nil {
 		return "", err
 	}
-	return string(s.Data["token"]), nil
+	key := secret.Key
+	if key == "" {
+		key = v1alpha1.DefaultTokenSecretKey
+	}
+	span.SetAttributes(attribute.String("k8s.namespace.name", namespace), attribute.String("k8s.secret.name", name))
+	klog.FromContext(ctx).V(4).Info("getting DigitalOcean token from secret", "secret", klog.KRef(namespace, name), "key", key)
+	s, err := client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	token, ok := s.Data[key]
+	if !ok {
+		return "", fmt.Errorf("token secret %s has no key %q", secret.Ref, key)
+	}
+	return string(token), nil
 }
 
 // digitalOcean에서 생성한 클러스터의 상태 체크용 함수
 // https://docs.digitalocean.com/reference/api/api-reference/#operation/kubernetes_get_cluster
-func ClusterState(c kubernetes.Interface, spec v1alpha1.KlusterSpec, id string) (string, error) {
-	token, err := getToken(c, spec.TokenSecret)
+func (c *Client) ClusterState(ctx context.Context, k *v1alpha1.Kluster, id string) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "digitalocean.ClusterState")
+	span.SetAttributes(attribute.String("digitalocean.cluster_id", id))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	client, err := c.godoClient(ctx, k)
 	if err != nil {
 		return "", err
 	}
-	// digitalOcean은 토큰을 토대로 K8S secret 정보 가져와서 수행하는 방식
-	client := godo.NewFromToken(token)
-	cluster, _, err := client.Kubernetes.Get(context.Background(), id)
-	return string(cluster.Status.State), err
+	cluster, _, err := client.Kubernetes.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	span.SetAttributes(attribute.String("digitalocean.cluster_state", string(cluster.Status.State)))
+	return string(cluster.Status.State), nil
+}
+
+// Kubeconfig: 클러스터에 접근할 수 있는 kubeconfig를 가져온다. KlusterClaim의 kubeconfig secret에 들어간다.
+// 조회 API이므로 dry-run에서도 호출한다.
+// https://docs.digitalocean.com/reference/api/api-reference/#operation/kubernetes_get_kubeconfig
+func (c *Client) Kubeconfig(ctx context.Context, k *v1alpha1.Kluster, id string) (_ []byte, err error) {
+	ctx, span := tracer.Start(ctx, "digitalocean.Kubeconfig")
+	span.SetAttributes(attribute.String("digitalocean.cluster_id", id))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	client, err := c.godoClient(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+	config, _, err := client.Kubernetes.GetKubeConfig(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return config.KubeconfigYAML, nil
+}
+
+// ObservedStatus: DigitalOcean 클러스터 object에서 status에 기록할 값(endpoint, IP, subnet, version, 상태 메시지, 시간, node pool)을 읽어온다.
+// KlusterID / conditions 등 controller가 관리하는 값은 채우지 않는다.
+func (c *Client) ObservedStatus(ctx context.Context, k *v1alpha1.Kluster, id string) (_ *v1alpha1.KlusterStatus, err error) {
+	ctx, span := tracer.Start(ctx, "digitalocean.ObservedStatus")
+	span.SetAttributes(attribute.String("digitalocean.cluster_id", id))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	client, err := c.godoClient(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+	cluster, _, err := client.Kubernetes.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &v1alpha1.KlusterStatus{
+		Version:       cluster.VersionSlug,
+		Endpoint:      cluster.Endpoint,
+		IPv4:          cluster.IPv4,
+		VPCUUID:       cluster.VPCUUID,
+		ClusterSubnet: cluster.ClusterSubnet,
+		ServiceSubnet: cluster.ServiceSubnet,
+		NodePools:     nodePoolStatuses(cluster.NodePools),
+	}
+	if cluster.Status != nil {
+		status.Progress = string(cluster.Status.State)
+		status.Message = cluster.Status.Message
+	}
+	if !cluster.CreatedAt.IsZero() {
+		createdAt := metav1.NewTime(cluster.CreatedAt)
+		status.CreatedAt = &createdAt
+	}
+	if !cluster.UpdatedAt.IsZero() {
+		updatedAt := metav1.NewTime(cluster.UpdatedAt)
+		status.UpdatedAt = &updatedAt
+	}
+	for _, pool := range cluster.NodePools {
+		status.Nodes += int32(pool.Count)
+	}
+	span.SetAttributes(attribute.String("digitalocean.cluster_state", status.Progress))
+	return status, nil
+}
+
+// Delete: deletionPolicy가 Delete인 Kluster가 삭제될 때 DigitalOcean 클러스터를 삭제한다. 이미 없는 클러스터는 삭제된 것으로 본다.
+// dry-run이면 ErrDryRun을 리턴한다.
+// https://docs.digitalocean.com/reference/api/api-reference/#operation/kubernetes_delete_cluster
+func (c *Client) Delete(ctx context.Context, k *v1alpha1.Kluster, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "digitalocean.Delete")
+	span.SetAttributes(attribute.String("digitalocean.cluster_id", id))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	client, err := c.godoClient(ctx, k)
+	if err != nil {
+		return err
+	}
+	klog.FromContext(ctx).V(2).Info("calling DigitalOcean delete cluster API")
+	ok, err := c.mutate(ctx, k, "delete cluster", map[string]string{"id": id}, func() error {
+		if _, err := client.Kubernetes.Delete(ctx, id); err != nil && !isNotFound(err) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrDryRun
+	}
+	return nil
+}
+
+// Release: deletionPolicy가 Retain인 Kluster가 삭제될 때 클러스터는 그대로 두고 owner tag만 지운다. dry-run이면 ErrDryRun을 리턴한다.
+func (c *Client) Release(ctx context.Context, k *v1alpha1.Kluster, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "digitalocean.Release")
+	span.SetAttributes(attribute.String("digitalocean.cluster_id", id))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	client, err := c.godoClient(ctx, k)
+	if err != nil {
+		return err
+	}
+	cluster, _, err := client.Kubernetes.Get(ctx, id)
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	tags := userTags(cluster.Tags)
+	klog.FromContext(ctx).V(2).Info("removing owner tags from retained cluster", "tags", tags)
+	ok, err := c.updateClusterTags(ctx, k, client, cluster, tags)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrDryRun
+	}
+	return nil
 }
@@ -0,0 +1,598 @@
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/digitalocean/godo"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAPI: DigitalOcean API 대신 응답하는 서버. 클러스터는 ID로 들고 있고, 조회가 아닌 요청은 "<method> <path>"와 body로 기록한다.
+type fakeAPI struct {
+	t        *testing.T
+	mu       sync.Mutex
+	clusters map[string]*godo.KubernetesCluster
+	sizes    []godo.Size
+	requests []apiRequest
+}
+
+type apiRequest struct {
+	call string
+	body []byte
+}
+
+// newFakeAPI: fakeAPI와, 그 서버를 호출하는 Client. token secret은 default/dosecret.
+func newFakeAPI(t *testing.T, dryRun bool, clusters ...*godo.KubernetesCluster) (*fakeAPI, *Client, *record.FakeRecorder) {
+	api := &fakeAPI{t: t, clusters: map[string]*godo.KubernetesCluster{}}
+	for _, cluster := range clusters {
+		api.clusters[cluster.ID] = cluster
+	}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	apiURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dosecret", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("token")},
+	})
+	recorder := record.NewFakeRecorder(100)
+	client := NewClient(kube, recorder, dryRun, "mgmt")
+	client.apiURL = apiURL
+	return api, client, recorder
+}
+
+func (a *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.t.Error(err)
+	}
+	if r.Method != http.MethodGet {
+		a.requests = append(a.requests, apiRequest{call: r.Method + " " + r.URL.Path, body: body})
+	}
+	reply := func(code int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		if v != nil {
+			if err := json.NewEncoder(w).Encode(v); err != nil {
+				a.t.Error(err)
+			}
+		}
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/kubernetes/clusters")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case r.URL.Path == "/v2/sizes":
+		reply(http.StatusOK, map[string]interface{}{"sizes": a.sizes, "links": map[string]interface{}{}})
+	case strings.HasPrefix(r.URL.Path, "/v2/kubernetes/registry"):
+		reply(http.StatusNoContent, nil)
+	case !strings.HasPrefix(r.URL.Path, "/v2/kubernetes/clusters"):
+		reply(http.StatusNotFound, map[string]string{"id": "not_found", "message": r.URL.Path})
+	case parts[0] == "" && r.Method == http.MethodGet:
+		var clusters []*godo.KubernetesCluster
+		for _, cluster := range a.clusters {
+			clusters = append(clusters, cluster)
+		}
+		sort.Slice(clusters, func(i, j int) bool { return clusters[i].ID < clusters[j].ID })
+		reply(http.StatusOK, map[string]interface{}{"kubernetes_clusters": clusters, "links": map[string]interface{}{}, "meta": map[string]int{"total": len(clusters)}})
+	case parts[0] == "" && r.Method == http.MethodPost:
+		request := &godo.KubernetesClusterCreateRequest{}
+		if err := json.Unmarshal(body, request); err != nil {
+			a.t.Error(err)
+		}
+		cluster := &godo.KubernetesCluster{ID: "new-cluster", Name: request.Name, RegionSlug: request.RegionSlug, Tags: request.Tags}
+		a.clusters[cluster.ID] = cluster
+		reply(http.StatusCreated, map[string]interface{}{"kubernetes_cluster": cluster})
+	default:
+		cluster, ok := a.clusters[parts[0]]
+		if !ok {
+			reply(http.StatusNotFound, map[string]string{"id": "not_found", "message": "cluster " + parts[0] + " not found"})
+			return
+		}
+		switch {
+		case len(parts) == 1 && r.Method == http.MethodGet:
+			reply(http.StatusOK, map[string]interface{}{"kubernetes_cluster": cluster})
+		case len(parts) == 1 && r.Method == http.MethodPut:
+			reply(http.StatusAccepted, map[string]interface{}{"kubernetes_cluster": cluster})
+		case len(parts) == 1 && r.Method == http.MethodDelete:
+			delete(a.clusters, cluster.ID)
+			reply(http.StatusNoContent, nil)
+		case parts[1] == "kubeconfig":
+			w.Header().Set("Content-Type", "application/yaml")
+			fmt.Fprintf(w, "apiVersion: v1\nkind: Config\n# %s\n", cluster.ID)
+		case parts[1] == "node_pools":
+			reply(http.StatusAccepted, map[string]interface{}{"node_pool": &godo.KubernetesNodePool{ID: "new-pool"}})
+		default:
+			reply(http.StatusAccepted, nil)
+		}
+	}
+}
+
+// sent: call("<method> <path>")로 보낸 요청의 body
+func (a *fakeAPI) sent(call string) [][]byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var bodies [][]byte
+	for _, r := range a.requests {
+		if r.call == call {
+			bodies = append(bodies, r.body)
+		}
+	}
+	return bodies
+}
+
+// calls: 조회가 아닌 요청 목록
+func (a *fakeAPI) calls() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var calls []string
+	for _, r := range a.requests {
+		calls = append(calls, r.call)
+	}
+	return calls
+}
+
+func apiKluster() *v1alpha1.Kluster {
+	return &v1alpha1.Kluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kluster-0", Namespace: "default", UID: "uid-0"},
+		Spec: v1alpha1.KlusterSpec{
+			Name:            "kluster-0",
+			Region:          "nyc1",
+			Version:         "1.25.4-do.0",
+			TokenSecret:     "default/dosecret",
+			NodePools:       []v1alpha1.NodePool{{Name: "pool-0", Size: "s-2vcpu-2gb", Count: 3}},
+			PrimaryNodePool: "pool-0",
+		},
+	}
+}
+
+// apiCluster: k의 spec과 같은 DigitalOcean 클러스터. c가 붙이는 owner tag도 붙어 있다.
+func apiCluster(c *Client, k *v1alpha1.Kluster) *godo.KubernetesCluster {
+	cluster := &godo.KubernetesCluster{
+		ID:          "cluster-1",
+		Name:        k.Spec.Name,
+		RegionSlug:  k.Spec.Region,
+		VersionSlug: k.Spec.Version,
+		VPCUUID:     k.Spec.VPCUUID,
+		Tags:        append(append([]string{"k8s", "k8s:cluster-1"}, k.Spec.Tags...), c.ownerTags(k)...),
+		Status:      &godo.KubernetesClusterStatus{State: godo.KubernetesClusterStatusRunning},
+	}
+	for i, pool := range k.Spec.NodePools {
+		cluster.NodePools = append(cluster.NodePools, &godo.KubernetesNodePool{
+			ID:        fmt.Sprintf("pool-id-%d", i),
+			Name:      pool.Name,
+			Size:      pool.Size,
+			Count:     pool.Count,
+			AutoScale: pool.AutoScale,
+			MinNodes:  pool.MinNodes,
+			MaxNodes:  pool.MaxNodes,
+			Labels:    pool.Labels,
+			Taints:    taints(pool.Taints),
+			Tags:      pool.Tags,
+		})
+	}
+	return cluster
+}
+
+func equalStrings(a, b []string) bool {
+	return len(a) == len(b) && (len(a) == 0 || reflect.DeepEqual(a, b))
+}
+
+func TestGetToken(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dosecret", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("default-token"), "do-token": []byte("custom-token")},
+	})
+	tests := []struct {
+		name    string
+		secret  TokenSecret
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "default key",
+			secret: TokenSecret{Ref: "default/dosecret"},
+			want:   "default-token",
+		},
+		{
+			name:   "custom key",
+			secret: TokenSecret{Ref: "default/dosecret", Key: "do-token"},
+			want:   "custom-token",
+		},
+		{
+			name:    "missing key",
+			secret:  TokenSecret{Ref: "default/dosecret", Key: "api-token"},
+			wantErr: true,
+		},
+		{
+			name:    "missing secret",
+			secret:  TokenSecret{Ref: "default/other"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid reference",
+			secret:  TokenSecret{Ref: "dosecret"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getToken(context.Background(), client, tt.secret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitSecretRef(t *testing.T) {
+	tests := []struct {
+		ref           string
+		wantNamespace string
+		wantName      string
+		wantErr       bool
+	}{
+		{ref: "default/dosecret", wantNamespace: "default", wantName: "dosecret"},
+		{ref: "dosecret", wantErr: true},
+		{ref: "/dosecret", wantErr: true},
+		{ref: "default/", wantErr: true},
+		{ref: "default/do/secret", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			namespace, name, err := SplitSecretRef(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitSecretRef() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if namespace != tt.wantNamespace || name != tt.wantName {
+				t.Errorf("SplitSecretRef() = %q, %q, want %q, %q", namespace, name, tt.wantNamespace, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	api, client, _ := newFakeAPI(t, false)
+	k := apiKluster()
+	k.Spec.VPCUUID = "c33931f2-a26a-4e61-b85c-4e95a2ec431b"
+	k.Spec.Tags = []string{"team-a"}
+	k.Spec.HA = pointer.Bool(true)
+	k.Spec.AutoUpgrade = pointer.Bool(true)
+	k.Spec.SurgeUpgrade = pointer.Bool(true)
+	k.Spec.MaintenancePolicy = &v1alpha1.MaintenancePolicy{Day: "monday", StartTime: "04:00"}
+
+	id, err := client.Create(context.Background(), k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "new-cluster" {
+		t.Errorf("Create() = %q, want new-cluster", id)
+	}
+	bodies := api.sent("POST /v2/kubernetes/clusters")
+	if len(bodies) != 1 {
+		t.Fatalf("create requests = %d, want 1", len(bodies))
+	}
+	request := &godo.KubernetesClusterCreateRequest{}
+	if err := json.Unmarshal(bodies[0], request); err != nil {
+		t.Fatal(err)
+	}
+	if request.Name != "kluster-0" || request.RegionSlug != "nyc1" || request.VersionSlug != "1.25.4-do.0" || request.VPCUUID != k.Spec.VPCUUID {
+		t.Errorf("request = %+v", request)
+	}
+	if !request.HA || !request.AutoUpgrade || !request.SurgeUpgrade {
+		t.Errorf("ha / autoUpgrade / surgeUpgrade = %v / %v / %v, want all true", request.HA, request.AutoUpgrade, request.SurgeUpgrade)
+	}
+	if p := request.MaintenancePolicy; p == nil || p.Day != godo.KubernetesMaintenanceDayMonday || p.StartTime != "04:00" {
+		t.Errorf("maintenance policy = %+v", p)
+	}
+	// 사용자 tag 뒤에 owner tag가 붙는다.
+	wantTags := append([]string{"team-a"}, client.ownerTags(k)...)
+	if !equalStrings(request.Tags, wantTags) {
+		t.Errorf("tags = %v, want %v", request.Tags, wantTags)
+	}
+	if len(request.NodePools) != 1 || request.NodePools[0].Name != "pool-0" || request.NodePools[0].Count != 3 {
+		t.Errorf("node pools = %+v", request.NodePools)
+	}
+}
+
+// 이전 reconcile에서 만든 클러스터가 있으면 다시 만들지 않는다.
+func TestCreateFindsOwnedCluster(t *testing.T) {
+	k := apiKluster()
+	api, client, _ := newFakeAPI(t, false)
+	api.clusters["cluster-1"] = apiCluster(client, k)
+
+	id, err := client.Create(context.Background(), k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "cluster-1" {
+		t.Errorf("Create() = %q, want cluster-1", id)
+	}
+	if calls := api.calls(); len(calls) != 0 {
+		t.Errorf("requests = %v, want none", calls)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name string
+		// spec / cluster: 기본 spec과 같은 클러스터에서 바꿀 값
+		spec      func(k *v1alpha1.Kluster)
+		cluster   func(c *godo.KubernetesCluster)
+		wantCalls []string
+		// update cluster 요청 body에 있어야 하는 값. nil이면 그 필드가 없어야 한다.
+		wantUpdate map[string]interface{}
+	}{
+		{name: "in sync"},
+		{
+			name:      "version",
+			spec:      func(k *v1alpha1.Kluster) { k.Spec.Version = "1.26.3-do.0" },
+			wantCalls: []string{"POST /v2/kubernetes/clusters/cluster-1/upgrade"},
+		},
+		{
+			// latest는 생성할 때만 의미가 있고, 이후에는 upgrade하지 않는다.
+			name: "latest version",
+			spec: func(k *v1alpha1.Kluster) { k.Spec.Version = "latest" },
+		},
+		{
+			name:       "enable ha",
+			spec:       func(k *v1alpha1.Kluster) { k.Spec.HA = pointer.Bool(true) },
+			wantCalls:  []string{"PUT /v2/kubernetes/clusters/cluster-1"},
+			wantUpdate: map[string]interface{}{"ha": true, "auto_upgrade": nil},
+		},
+		{
+			name:    "ha unset keeps the cluster value",
+			cluster: func(c *godo.KubernetesCluster) { c.HA = true },
+		},
+		{
+			name:       "disable auto upgrade",
+			spec:       func(k *v1alpha1.Kluster) { k.Spec.AutoUpgrade = pointer.Bool(false) },
+			cluster:    func(c *godo.KubernetesCluster) { c.AutoUpgrade = true },
+			wantCalls:  []string{"PUT /v2/kubernetes/clusters/cluster-1"},
+			wantUpdate: map[string]interface{}{"auto_upgrade": false, "ha": nil},
+		},
+		{
+			name:    "auto upgrade unset keeps the cluster value",
+			cluster: func(c *godo.KubernetesCluster) { c.AutoUpgrade = true },
+		},
+		{
+			name: "maintenance policy",
+			spec: func(k *v1alpha1.Kluster) { k.Spec.MaintenancePolicy = &v1alpha1.MaintenancePolicy{StartTime: "04:00"} },
+			cluster: func(c *godo.KubernetesCluster) {
+				c.MaintenancePolicy = &godo.KubernetesMaintenancePolicy{Day: godo.KubernetesMaintenanceDaySunday, StartTime: "00:00"}
+			},
+			wantCalls:  []string{"PUT /v2/kubernetes/clusters/cluster-1"},
+			wantUpdate: map[string]interface{}{"maintenance_policy": map[string]interface{}{"day": "sunday", "start_time": "04:00", "duration": ""}},
+		},
+		{
+			name:      "enable registry",
+			spec:      func(k *v1alpha1.Kluster) { k.Spec.RegistryEnabled = pointer.Bool(true) },
+			wantCalls: []string{"POST /v2/kubernetes/registry"},
+		},
+		{
+			name:      "disable registry",
+			cluster:   func(c *godo.KubernetesCluster) { c.RegistryEnabled = true },
+			wantCalls: []string{"DELETE /v2/kubernetes/registry"},
+		},
+		{
+			// spec.tags가 없으면 사용자 tag는 두고 owner tag만 맞춘다.
+			name:    "user tags without spec tags",
+			cluster: func(c *godo.KubernetesCluster) { c.Tags = append(c.Tags, "added-in-console") },
+		},
+		{
+			name:      "spec tags",
+			spec:      func(k *v1alpha1.Kluster) { k.Spec.Tags = []string{"team-a"} },
+			cluster:   func(c *godo.KubernetesCluster) { c.Tags = append(c.Tags, "team-b") },
+			wantCalls: []string{"PUT /v2/kubernetes/clusters/cluster-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := apiKluster()
+			api, client, _ := newFakeAPI(t, false)
+			cluster := apiCluster(client, k)
+			if tt.spec != nil {
+				tt.spec(k)
+			}
+			if tt.cluster != nil {
+				tt.cluster(cluster)
+			}
+			api.clusters[cluster.ID] = cluster
+
+			updated, err := client.Update(context.Background(), k, cluster.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if calls := api.calls(); !equalStrings(calls, tt.wantCalls) {
+				t.Fatalf("requests = %v, want %v", calls, tt.wantCalls)
+			}
+			if updated != (len(tt.wantCalls) > 0) {
+				t.Errorf("Update() = %v with requests %v", updated, tt.wantCalls)
+			}
+			if tt.wantUpdate == nil {
+				return
+			}
+			body := map[string]interface{}{}
+			if err := json.Unmarshal(api.sent("PUT /v2/kubernetes/clusters/cluster-1")[0], &body); err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range tt.wantUpdate {
+				got, ok := body[key]
+				if want == nil {
+					if ok {
+						t.Errorf("update request has %s = %v, want it unset", key, got)
+					}
+					continue
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("update request %s = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestObservedStatus(t *testing.T) {
+	k := apiKluster()
+	api, client, _ := newFakeAPI(t, false)
+	cluster := apiCluster(client, k)
+	cluster.Endpoint = "https://cluster-1.k8s.ondigitalocean.com"
+	cluster.IPv4 = "10.0.0.1"
+	cluster.VPCUUID = "vpc-1"
+	cluster.ClusterSubnet = "10.244.0.0/16"
+	cluster.ServiceSubnet = "10.245.0.0/16"
+	cluster.Status = &godo.KubernetesClusterStatus{State: godo.KubernetesClusterStatusDegraded, Message: "node pool is unhealthy"}
+	cluster.CreatedAt = time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	cluster.NodePools = append(cluster.NodePools, &godo.KubernetesNodePool{ID: "pool-id-1", Name: "pool-1", Count: 2})
+	api.clusters[cluster.ID] = cluster
+
+	status, err := client.ObservedStatus(context.Background(), k, cluster.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Progress != "degraded" || status.Message != "node pool is unhealthy" || status.Version != "1.25.4-do.0" ||
+		status.Endpoint != cluster.Endpoint || status.IPv4 != "10.0.0.1" || status.VPCUUID != "vpc-1" ||
+		status.ClusterSubnet != "10.244.0.0/16" || status.ServiceSubnet != "10.245.0.0/16" {
+		t.Errorf("status = %+v", status)
+	}
+	// node 수는 모든 node pool의 합. 값이 없는 시각은 nil로 둔다.
+	if status.Nodes != 5 || len(status.NodePools) != 2 {
+		t.Errorf("nodes = %d, node pools = %+v, want 5 nodes in 2 pools", status.Nodes, status.NodePools)
+	}
+	if status.CreatedAt == nil || !status.CreatedAt.Time.Equal(cluster.CreatedAt) || status.UpdatedAt != nil {
+		t.Errorf("createdAt = %v, updatedAt = %v", status.CreatedAt, status.UpdatedAt)
+	}
+
+	if _, err := client.ObservedStatus(context.Background(), k, "missing"); err == nil {
+		t.Error("ObservedStatus() of a missing cluster returned no error")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	k := apiKluster()
+	api, client, _ := newFakeAPI(t, false)
+	api.clusters["cluster-1"] = apiCluster(client, k)
+
+	if err := client.Delete(context.Background(), k, "cluster-1"); err != nil {
+		t.Fatal(err)
+	}
+	// 이미 없는 클러스터는 삭제된 것으로 본다.
+	if err := client.Delete(context.Background(), k, "cluster-1"); err != nil {
+		t.Fatalf("Delete() of a deleted cluster = %v", err)
+	}
+	want := []string{"DELETE /v2/kubernetes/clusters/cluster-1", "DELETE /v2/kubernetes/clusters/cluster-1"}
+	if calls := api.calls(); !equalStrings(calls, want) {
+		t.Errorf("requests = %v, want %v", calls, want)
+	}
+}
+
+func TestRelease(t *testing.T) {
+	k := apiKluster()
+	k.Spec.Tags = []string{"team-a"}
+	api, client, _ := newFakeAPI(t, false)
+	api.clusters["cluster-1"] = apiCluster(client, k)
+
+	if err := client.Release(context.Background(), k, "cluster-1"); err != nil {
+		t.Fatal(err)
+	}
+	bodies := api.sent("PUT /v2/kubernetes/clusters/cluster-1")
+	if calls := api.calls(); len(calls) != 1 || len(bodies) != 1 {
+		t.Fatalf("requests = %v, want one tag update", calls)
+	}
+	// owner tag와 DigitalOcean이 붙이는 tag는 빠지고 사용자 tag만 남는다.
+	request := &clusterTagsRequest{}
+	if err := json.Unmarshal(bodies[0], request); err != nil {
+		t.Fatal(err)
+	}
+	if request.Name != "kluster-0" || !equalStrings(request.Tags, []string{"team-a"}) {
+		t.Errorf("tag update = %+v, want kluster-0 with [team-a]", request)
+	}
+
+	// 이미 없는 클러스터는 정리할 것이 없다.
+	if err := client.Release(context.Background(), k, "missing"); err != nil {
+		t.Errorf("Release() of a missing cluster = %v", err)
+	}
+}
+
+// dry-run이면 조회는 DigitalOcean에 보내지만, 클러스터를 변경하는 요청은 보내지 않고 payload를 DryRun 이벤트로 남긴다.
+func TestDryRun(t *testing.T) {
+	k := apiKluster()
+	api, client, recorder := newFakeAPI(t, true)
+	if _, err := client.Create(context.Background(), k); !errors.Is(err, ErrDryRun) {
+		t.Errorf("Create() = %v, want ErrDryRun", err)
+	}
+	cluster := apiCluster(client, k)
+	api.clusters[cluster.ID] = cluster
+	k.Spec.Version = "1.26.1-do.0"
+	if updated, err := client.Update(context.Background(), k, cluster.ID); err != nil || updated {
+		t.Errorf("Update() = %v, %v, want false, nil", updated, err)
+	}
+	if err := client.Delete(context.Background(), k, cluster.ID); !errors.Is(err, ErrDryRun) {
+		t.Errorf("Delete() = %v, want ErrDryRun", err)
+	}
+	if err := client.Release(context.Background(), k, cluster.ID); !errors.Is(err, ErrDryRun) {
+		t.Errorf("Release() = %v, want ErrDryRun", err)
+	}
+	if calls := api.calls(); len(calls) != 0 {
+		t.Errorf("requests = %v, want none", calls)
+	}
+	// 읽기는 그대로 DigitalOcean에서 한다.
+	if status, err := client.ObservedStatus(context.Background(), k, cluster.ID); err != nil || status.Progress != "running" {
+		t.Errorf("ObservedStatus() = %+v, %v", status, err)
+	}
+
+	var got []string
+	for _, event := range drainEvents(recorder) {
+		if !strings.HasPrefix(event, "Normal DryRun ") {
+			t.Errorf("unexpected event %q", event)
+			continue
+		}
+		got = append(got, event)
+	}
+	for i, want := range []string{
+		`Would call Digital Ocean create cluster: {"name":"kluster-0","region":"nyc1","version":"1.25.4-do.0"`,
+		`Would call Digital Ocean upgrade cluster: {"version":"1.26.1-do.0"}`,
+		`Would call Digital Ocean delete cluster: {"id":"cluster-1"}`,
+		`Would call Digital Ocean update cluster tags: {"name":"kluster-0","tags":[]}`,
+	} {
+		if i >= len(got) || !strings.Contains(got[i], want) {
+			t.Errorf("events = %q, want #%d to contain %q", got, i, want)
+		}
+	}
+}
+
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
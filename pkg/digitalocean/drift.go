@@ -0,0 +1,165 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"github.com/digitalocean/godo"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1/validation"
+	"github.com/inspirit941/kluster/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+	"reflect"
+)
+
+// ClusterDrift: spec과 실제 클러스터의 차이. 각 항목은 "<field>: spec <값>, actual <값>" 형태.
+type ClusterDrift struct {
+	// Update로 spec에 맞출 수 있는 차이
+	Correctable []string
+	// DigitalOcean API로 바꿀 수 없는 차이(name, region, vpcUUID, node pool size). 클러스터나 node pool을 다시 만들어야 맞춰진다.
+	Uncorrectable []string
+}
+
+// All: 모든 차이를 Correctable, Uncorrectable 순서로 리턴한다.
+func (d ClusterDrift) All() []string {
+	return append(append([]string{}, d.Correctable...), d.Uncorrectable...)
+}
+
+// Drift: spec과 실제 DigitalOcean 클러스터를 비교해서 다른 항목을 리턴한다.
+// 기존 클러스터를 adopt하기 전과, resync 때 drift를 감지할 때 사용한다.
+func (c *Client) Drift(ctx context.Context, k *v1alpha1.Kluster, id string) (_ ClusterDrift, err error) {
+	ctx, span := tracer.Start(ctx, "digitalocean.Drift")
+	span.SetAttributes(attribute.String("digitalocean.cluster_id", id))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	client, err := c.godoClient(ctx, k)
+	if err != nil {
+		return ClusterDrift{}, err
+	}
+	cluster, _, err := client.Kubernetes.Get(ctx, id)
+	if err != nil {
+		return ClusterDrift{}, err
+	}
+	drift := diff(k.Spec, cluster)
+	span.SetAttributes(
+		attribute.Int("digitalocean.drift_count", len(drift.Correctable)),
+		attribute.Int("digitalocean.uncorrectable_drift_count", len(drift.Uncorrectable)))
+	return drift, nil
+}
+
+// spec에 값이 없는 optional 필드(tags, labels, maintenance policy 등)는 DigitalOcean 기본값을 쓰겠다는 의미이므로 비교하지 않는다.
+func diff(spec v1alpha1.KlusterSpec, cluster *godo.KubernetesCluster) ClusterDrift {
+	var drift ClusterDrift
+	add := func(field string, want, got interface{}) {
+		drift.Correctable = append(drift.Correctable, fmt.Sprintf("%s: spec %v, actual %v", field, want, got))
+	}
+	// Update가 건드리지 않는 값. Correct 정책이어도 되돌릴 수 없다.
+	cannot := func(field string, want, got interface{}) {
+		drift.Uncorrectable = append(drift.Uncorrectable, fmt.Sprintf("%s: spec %v, actual %v", field, want, got))
+	}
+
+	if spec.Name != cluster.Name {
+		cannot("spec.name", spec.Name, cluster.Name)
+	}
+	if spec.Region != cluster.RegionSlug {
+		cannot("spec.region", spec.Region, cluster.RegionSlug)
+	}
+	if spec.Version != validation.LatestVersion && spec.Version != cluster.VersionSlug {
+		add("spec.version", spec.Version, cluster.VersionSlug)
+	}
+	if spec.VPCUUID != "" && spec.VPCUUID != cluster.VPCUUID {
+		cannot("spec.vpcUUID", spec.VPCUUID, cluster.VPCUUID)
+	}
+	if len(spec.Tags) > 0 && !sets.NewString(spec.Tags...).Equal(sets.NewString(userTags(cluster.Tags)...)) {
+		add("spec.tags", spec.Tags, userTags(cluster.Tags))
+	}
+	// ha / autoUpgrade는 spec에 값이 있을 때만 비교한다. (Update도 값이 있을 때만 반영함)
+	// HA control plane은 끌 수 없으므로 spec이 false인데 클러스터에서 켜져 있으면 되돌릴 수 없다.
+	if spec.HA != nil && *spec.HA != cluster.HA {
+		if *spec.HA {
+			add("spec.ha", true, cluster.HA)
+		} else {
+			cannot("spec.ha", false, cluster.HA)
+		}
+	}
+	if spec.AutoUpgrade != nil && *spec.AutoUpgrade != cluster.AutoUpgrade {
+		add("spec.autoUpgrade", *spec.AutoUpgrade, cluster.AutoUpgrade)
+	}
+	// surge upgrade는 update API로 끌 수 없으므로(Update 참고), 클러스터에서 켜져 있는 것은 drift로 보지 않는다.
+	if pointer.BoolDeref(spec.SurgeUpgrade, false) && !cluster.SurgeUpgrade {
+		add("spec.surgeUpgrade", true, cluster.SurgeUpgrade)
+	}
+	if registryEnabled := pointer.BoolDeref(spec.RegistryEnabled, false); registryEnabled != cluster.RegistryEnabled {
+		add("spec.registryEnabled", registryEnabled, cluster.RegistryEnabled)
+	}
+	if p := spec.MaintenancePolicy; p != nil && cluster.MaintenancePolicy != nil {
+		if p.Day != "" && p.Day != cluster.MaintenancePolicy.Day.String() {
+			add("spec.maintenancePolicy.day", p.Day, cluster.MaintenancePolicy.Day.String())
+		}
+		if p.StartTime != "" && p.StartTime != cluster.MaintenancePolicy.StartTime {
+			add("spec.maintenancePolicy.startTime", p.StartTime, cluster.MaintenancePolicy.StartTime)
+		}
+	}
+
+	actualPools := map[string]*godo.KubernetesNodePool{}
+	for _, pool := range cluster.NodePools {
+		actualPools[pool.Name] = pool
+	}
+	for _, pool := range spec.NodePools {
+		current, ok := actualPools[pool.Name]
+		if !ok {
+			drift.Correctable = append(drift.Correctable, fmt.Sprintf("spec.nodePools[%s]: not found in the cluster", pool.Name))
+			continue
+		}
+		delete(actualPools, pool.Name)
+		poolDrift := nodePoolDiff(pool, current)
+		drift.Correctable = append(drift.Correctable, poolDrift.Correctable...)
+		drift.Uncorrectable = append(drift.Uncorrectable, poolDrift.Uncorrectable...)
+	}
+	// map 순서에 따라 메시지가 바뀌지 않도록 정렬한다.
+	extra := sets.NewString()
+	for name := range actualPools {
+		extra.Insert(name)
+	}
+	for _, name := range extra.List() {
+		drift.Correctable = append(drift.Correctable, fmt.Sprintf("spec.nodePools[%s]: node pool exists in the cluster but not in spec", name))
+	}
+	return drift
+}
+
+func nodePoolDiff(pool v1alpha1.NodePool, current *godo.KubernetesNodePool) ClusterDrift {
+	var drift ClusterDrift
+	message := func(field string, want, got interface{}) string {
+		return fmt.Sprintf("spec.nodePools[%s].%s: spec %v, actual %v", pool.Name, field, want, got)
+	}
+	add := func(field string, want, got interface{}) {
+		drift.Correctable = append(drift.Correctable, message(field, want, got))
+	}
+	// node pool update API는 size를 바꿀 수 없다.
+	if pool.Size != current.Size {
+		drift.Uncorrectable = append(drift.Uncorrectable, message("size", pool.Size, current.Size))
+	}
+	// autoScale이 켜진 pool의 노드 수는 autoscaler가 관리한다.
+	if !pool.AutoScale && pool.Count != current.Count {
+		add("count", pool.Count, current.Count)
+	}
+	if pool.AutoScale != current.AutoScale {
+		add("autoScale", pool.AutoScale, current.AutoScale)
+	} else if pool.AutoScale && (pool.MinNodes != current.MinNodes || pool.MaxNodes != current.MaxNodes) {
+		add("minNodes/maxNodes", fmt.Sprintf("%d/%d", pool.MinNodes, pool.MaxNodes), fmt.Sprintf("%d/%d", current.MinNodes, current.MaxNodes))
+	}
+	if len(pool.Labels) > 0 && !reflect.DeepEqual(pool.Labels, current.Labels) {
+		add("labels", pool.Labels, current.Labels)
+	}
+	if want, got := sets.NewString(taintStrings(taints(pool.Taints))...), sets.NewString(taintStrings(current.Taints)...); !want.Equal(got) {
+		add("taints", want.List(), got.List())
+	}
+	if len(pool.Tags) > 0 && !sets.NewString(pool.Tags...).Equal(sets.NewString(userTags(current.Tags)...)) {
+		add("tags", pool.Tags, userTags(current.Tags))
+	}
+	return drift
+}
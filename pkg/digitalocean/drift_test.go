@@ -0,0 +1,223 @@
+package digitalocean
+
+import (
+	"github.com/digitalocean/godo"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"k8s.io/utils/pointer"
+	"reflect"
+	"testing"
+)
+
+func driftSpec() v1alpha1.KlusterSpec {
+	return v1alpha1.KlusterSpec{
+		Name:    "kluster-0",
+		Region:  "nyc1",
+		Version: "1.25.4-do.0",
+		NodePools: []v1alpha1.NodePool{
+			{Name: "pool-0", Size: "s-2vcpu-2gb", Count: 3},
+		},
+	}
+}
+
+// driftSpec과 같은 상태의 클러스터. DigitalOcean / owner tag가 붙어 있다.
+func driftCluster() *godo.KubernetesCluster {
+	return &godo.KubernetesCluster{
+		Name:        "kluster-0",
+		RegionSlug:  "nyc1",
+		VersionSlug: "1.25.4-do.0",
+		Tags:        []string{"k8s", "k8s:1234", ManagedTag, OwnerNamePrefix + "kluster-0"},
+		NodePools: []*godo.KubernetesNodePool{
+			{Name: "pool-0", Size: "s-2vcpu-2gb", Count: 3, Tags: []string{"k8s", "k8s:worker"}},
+		},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    func(spec *v1alpha1.KlusterSpec)
+		cluster func(cluster *godo.KubernetesCluster)
+		want    []string
+		// update로 되돌릴 수 없는 차이
+		wantUncorrectable []string
+	}{
+		{
+			name: "in sync",
+		},
+		{
+			name: "latest version is not compared",
+			spec: func(spec *v1alpha1.KlusterSpec) { spec.Version = "latest" },
+		},
+		{
+			name:    "version",
+			cluster: func(cluster *godo.KubernetesCluster) { cluster.VersionSlug = "1.24.8-do.0" },
+			want:    []string{"spec.version: spec 1.25.4-do.0, actual 1.24.8-do.0"},
+		},
+		{
+			name:              "region cannot be corrected",
+			cluster:           func(cluster *godo.KubernetesCluster) { cluster.RegionSlug = "sfo3" },
+			wantUncorrectable: []string{"spec.region: spec nyc1, actual sfo3"},
+		},
+		{
+			name: "name and vpcUUID cannot be corrected",
+			spec: func(spec *v1alpha1.KlusterSpec) { spec.VPCUUID = "vpc-0" },
+			cluster: func(cluster *godo.KubernetesCluster) {
+				cluster.Name, cluster.VPCUUID = "kluster-1", "vpc-1"
+			},
+			wantUncorrectable: []string{"spec.name: spec kluster-0, actual kluster-1", "spec.vpcUUID: spec vpc-0, actual vpc-1"},
+		},
+		{
+			name: "empty tags are not compared",
+			cluster: func(cluster *godo.KubernetesCluster) {
+				cluster.Tags = append(cluster.Tags, "team-a")
+			},
+		},
+		{
+			name: "system tags are ignored",
+			spec: func(spec *v1alpha1.KlusterSpec) { spec.Tags = []string{"team-a"} },
+			cluster: func(cluster *godo.KubernetesCluster) {
+				cluster.Tags = append(cluster.Tags, "team-a")
+			},
+		},
+		{
+			name: "tags",
+			spec: func(spec *v1alpha1.KlusterSpec) { spec.Tags = []string{"team-a"} },
+			cluster: func(cluster *godo.KubernetesCluster) {
+				cluster.Tags = append(cluster.Tags, "team-b")
+			},
+			want: []string{"spec.tags: spec [team-a], actual [team-b]"},
+		},
+		{
+			name:    "ha unset is not compared",
+			cluster: func(cluster *godo.KubernetesCluster) { cluster.HA = true },
+		},
+		{
+			name: "ha disabled in the cluster",
+			spec: func(spec *v1alpha1.KlusterSpec) { spec.HA = pointer.Bool(true) },
+			want: []string{"spec.ha: spec true, actual false"},
+		},
+		{
+			name:              "ha enabled in the cluster cannot be corrected",
+			spec:              func(spec *v1alpha1.KlusterSpec) { spec.HA = pointer.Bool(false) },
+			cluster:           func(cluster *godo.KubernetesCluster) { cluster.HA = true },
+			wantUncorrectable: []string{"spec.ha: spec false, actual true"},
+		},
+		{
+			name: "autoUpgrade",
+			spec: func(spec *v1alpha1.KlusterSpec) { spec.AutoUpgrade = pointer.Bool(true) },
+			want: []string{"spec.autoUpgrade: spec true, actual false"},
+		},
+		{
+			name:    "autoUpgrade unset is not compared",
+			cluster: func(cluster *godo.KubernetesCluster) { cluster.AutoUpgrade = true },
+		},
+		{
+			name:    "autoUpgrade disabled",
+			spec:    func(spec *v1alpha1.KlusterSpec) { spec.AutoUpgrade = pointer.Bool(false) },
+			cluster: func(cluster *godo.KubernetesCluster) { cluster.AutoUpgrade = true },
+			want:    []string{"spec.autoUpgrade: spec false, actual true"},
+		},
+		{
+			name: "surgeUpgrade disabled in the cluster",
+			spec: func(spec *v1alpha1.KlusterSpec) { spec.SurgeUpgrade = pointer.Bool(true) },
+			want: []string{"spec.surgeUpgrade: spec true, actual false"},
+		},
+		{
+			name:    "surgeUpgrade enabled in the cluster cannot be corrected",
+			spec:    func(spec *v1alpha1.KlusterSpec) { spec.SurgeUpgrade = pointer.Bool(false) },
+			cluster: func(cluster *godo.KubernetesCluster) { cluster.SurgeUpgrade = true },
+		},
+		{
+			name:    "surgeUpgrade unset",
+			cluster: func(cluster *godo.KubernetesCluster) { cluster.SurgeUpgrade = true },
+		},
+		{
+			name: "maintenance policy",
+			spec: func(spec *v1alpha1.KlusterSpec) {
+				spec.MaintenancePolicy = &v1alpha1.MaintenancePolicy{Day: "monday", StartTime: "04:00"}
+			},
+			cluster: func(cluster *godo.KubernetesCluster) {
+				cluster.MaintenancePolicy = &godo.KubernetesMaintenancePolicy{Day: godo.KubernetesMaintenanceDayMonday, StartTime: "02:00"}
+			},
+			want: []string{"spec.maintenancePolicy.startTime: spec 04:00, actual 02:00"},
+		},
+		{
+			name:    "node count",
+			cluster: func(cluster *godo.KubernetesCluster) { cluster.NodePools[0].Count = 2 },
+			want:    []string{"spec.nodePools[pool-0].count: spec 3, actual 2"},
+		},
+		{
+			name: "node size cannot be corrected",
+			cluster: func(cluster *godo.KubernetesCluster) {
+				cluster.NodePools[0].Size, cluster.NodePools[0].Count = "s-4vcpu-8gb", 2
+			},
+			want:              []string{"spec.nodePools[pool-0].count: spec 3, actual 2"},
+			wantUncorrectable: []string{"spec.nodePools[pool-0].size: spec s-2vcpu-2gb, actual s-4vcpu-8gb"},
+		},
+		{
+			name: "autoScale node count is not compared",
+			spec: func(spec *v1alpha1.KlusterSpec) {
+				pool := &spec.NodePools[0]
+				pool.AutoScale, pool.MinNodes, pool.MaxNodes = true, 1, 5
+			},
+			cluster: func(cluster *godo.KubernetesCluster) {
+				pool := cluster.NodePools[0]
+				pool.AutoScale, pool.MinNodes, pool.MaxNodes, pool.Count = true, 1, 5, 4
+			},
+		},
+		{
+			name: "autoScale range",
+			spec: func(spec *v1alpha1.KlusterSpec) {
+				pool := &spec.NodePools[0]
+				pool.AutoScale, pool.MinNodes, pool.MaxNodes = true, 1, 5
+			},
+			cluster: func(cluster *godo.KubernetesCluster) {
+				pool := cluster.NodePools[0]
+				pool.AutoScale, pool.MinNodes, pool.MaxNodes = true, 1, 3
+			},
+			want: []string{"spec.nodePools[pool-0].minNodes/maxNodes: spec 1/5, actual 1/3"},
+		},
+		{
+			name: "taints",
+			spec: func(spec *v1alpha1.KlusterSpec) {
+				spec.NodePools[0].Taints = []v1alpha1.Taint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}}
+			},
+			want: []string{"spec.nodePools[pool-0].taints: spec [dedicated=gpu:NoSchedule], actual []"},
+		},
+		{
+			name: "missing and extra node pools",
+			spec: func(spec *v1alpha1.KlusterSpec) {
+				spec.NodePools = append(spec.NodePools, v1alpha1.NodePool{Name: "pool-1", Size: "s-2vcpu-2gb", Count: 1})
+			},
+			cluster: func(cluster *godo.KubernetesCluster) {
+				cluster.NodePools = append(cluster.NodePools,
+					&godo.KubernetesNodePool{Name: "pool-3", Size: "s-2vcpu-2gb", Count: 1},
+					&godo.KubernetesNodePool{Name: "pool-2", Size: "s-2vcpu-2gb", Count: 1})
+			},
+			want: []string{
+				"spec.nodePools[pool-1]: not found in the cluster",
+				"spec.nodePools[pool-2]: node pool exists in the cluster but not in spec",
+				"spec.nodePools[pool-3]: node pool exists in the cluster but not in spec",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := driftSpec()
+			if tt.spec != nil {
+				tt.spec(&spec)
+			}
+			cluster := driftCluster()
+			if tt.cluster != nil {
+				tt.cluster(cluster)
+			}
+			got := diff(spec, cluster)
+			if !reflect.DeepEqual(got.Correctable, tt.want) {
+				t.Errorf("diff().Correctable = %q, want %q", got.Correctable, tt.want)
+			}
+			if !reflect.DeepEqual(got.Uncorrectable, tt.wantUncorrectable) {
+				t.Errorf("diff().Uncorrectable = %q, want %q", got.Uncorrectable, tt.wantUncorrectable)
+			}
+		})
+	}
+}
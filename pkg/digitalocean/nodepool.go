@@ -0,0 +1,165 @@
+package digitalocean
+
+import (
+	"context"
+	"github.com/digitalocean/godo"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"reflect"
+)
+
+// DigitalOcean이 각 노드에 붙이는 node pool 이름 label
+const NodePoolLabel = "doks.digitalocean.com/node-pool"
+
+func nodePoolCreateRequest(pool v1alpha1.NodePool) *godo.KubernetesNodePoolCreateRequest {
+	return &godo.KubernetesNodePoolCreateRequest{
+		Size:      pool.Size,
+		Name:      pool.Name,
+		Count:     pool.Count,
+		Tags:      pool.Tags,
+		Labels:    pool.Labels,
+		Taints:    taints(pool.Taints),
+		AutoScale: pool.AutoScale,
+		MinNodes:  pool.MinNodes,
+		MaxNodes:  pool.MaxNodes,
+	}
+}
+
+// syncNodePools: spec의 node pool과 실제 node pool을 이름으로 매칭해서
+// spec에만 있는 pool은 생성하고, 양쪽에 있는 pool은 바뀐 값만 update, spec에서 빠진 pool은 삭제한다.
+// https://docs.digitalocean.com/reference/api/api-reference/#operation/kubernetes_update_nodePool
+func (c *Client) syncNodePools(ctx context.Context, k *v1alpha1.Kluster, client *godo.Client, id string, actual []*godo.KubernetesNodePool) (bool, error) {
+	logger := klog.FromContext(ctx)
+	actualByName := map[string]*godo.KubernetesNodePool{}
+	for _, pool := range actual {
+		actualByName[pool.Name] = pool
+	}
+
+	updated := false
+	desired := sets.NewString()
+	for _, pool := range k.Spec.NodePools {
+		desired.Insert(pool.Name)
+		current, ok := actualByName[pool.Name]
+		if !ok {
+			logger.V(2).Info("calling DigitalOcean create node pool API", "nodePool", pool.Name)
+			request := nodePoolCreateRequest(pool)
+			ok, err := c.mutate(ctx, k, "create node pool", request, func() error {
+				_, _, err := client.Kubernetes.CreateNodePool(ctx, id, request)
+				return err
+			})
+			if err != nil {
+				return updated, err
+			}
+			updated = updated || ok
+			continue
+		}
+		request, ok := nodePoolUpdateRequest(pool, current)
+		if !ok {
+			continue
+		}
+		logger.V(2).Info("calling DigitalOcean update node pool API", "nodePool", pool.Name, "request", request)
+		poolID := current.ID
+		ok, err := c.mutate(ctx, k, "update node pool "+poolID, request, func() error {
+			_, _, err := client.Kubernetes.UpdateNodePool(ctx, id, poolID, request)
+			return err
+		})
+		if err != nil {
+			return updated, err
+		}
+		updated = updated || ok
+	}
+
+	// spec에서 빠진 pool 삭제. validation에서 spec에 pool이 최소 1개 있도록 보장하므로 클러스터가 비는 일은 없다.
+	for _, pool := range actual {
+		if desired.Has(pool.Name) {
+			continue
+		}
+		logger.Info("deleting node pool removed from spec", "nodePool", pool.Name, "nodePoolID", pool.ID)
+		poolID := pool.ID
+		ok, err := c.mutate(ctx, k, "delete node pool "+poolID, map[string]string{"name": pool.Name, "id": poolID}, func() error {
+			_, err := client.Kubernetes.DeleteNodePool(ctx, id, poolID)
+			return err
+		})
+		if err != nil {
+			return updated, err
+		}
+		updated = updated || ok
+	}
+	return updated, nil
+}
+
+// 바뀐 값이 없으면 false를 리턴한다.
+// autoScale이 켜진 pool의 count는 autoscaler가 관리하므로 비교하지 않는다.
+// godo update request의 tags / labels는 omitempty라서 전부 지우는 요청은 보낼 수 없다.
+func nodePoolUpdateRequest(pool v1alpha1.NodePool, current *godo.KubernetesNodePool) (*godo.KubernetesNodePoolUpdateRequest, bool) {
+	// name / count는 update API의 required 값이므로 항상 채운다.
+	request := &godo.KubernetesNodePoolUpdateRequest{
+		Name:  pool.Name,
+		Count: godo.Int(current.Count),
+	}
+	changed := false
+	if !pool.AutoScale && pool.Count != current.Count {
+		request.Count = godo.Int(pool.Count)
+		changed = true
+	}
+	if pool.AutoScale != current.AutoScale || (pool.AutoScale && (pool.MinNodes != current.MinNodes || pool.MaxNodes != current.MaxNodes)) {
+		request.AutoScale = godo.Bool(pool.AutoScale)
+		request.MinNodes = godo.Int(pool.MinNodes)
+		request.MaxNodes = godo.Int(pool.MaxNodes)
+		changed = true
+	}
+	if len(pool.Labels) > 0 && !reflect.DeepEqual(pool.Labels, current.Labels) {
+		request.Labels = pool.Labels
+		changed = true
+	}
+	if desired := taints(pool.Taints); !sets.NewString(taintStrings(desired)...).Equal(sets.NewString(taintStrings(current.Taints)...)) {
+		if desired == nil {
+			desired = []godo.Taint{}
+		}
+		request.Taints = &desired
+		changed = true
+	}
+	if len(pool.Tags) > 0 && !sets.NewString(userTags(current.Tags)...).Equal(sets.NewString(pool.Tags...)) {
+		request.Tags = pool.Tags
+		changed = true
+	}
+	return request, changed
+}
+
+func taints(in []v1alpha1.Taint) []godo.Taint {
+	var out []godo.Taint
+	for _, t := range in {
+		out = append(out, godo.Taint{Key: t.Key, Value: t.Value, Effect: t.Effect})
+	}
+	return out
+}
+
+func taintStrings(in []godo.Taint) []string {
+	var out []string
+	for _, t := range in {
+		out = append(out, t.String())
+	}
+	return out
+}
+
+// 각 node pool의 실제 노드 수와 노드 이름 / 상태. status.nodePools에 기록됨.
+func nodePoolStatuses(pools []*godo.KubernetesNodePool) []v1alpha1.NodePoolStatus {
+	var statuses []v1alpha1.NodePoolStatus
+	for _, pool := range pools {
+		status := v1alpha1.NodePoolStatus{
+			Name:  pool.Name,
+			ID:    pool.ID,
+			Count: pool.Count,
+		}
+		for _, node := range pool.Nodes {
+			nodeStatus := v1alpha1.NodeStatus{Name: node.Name}
+			if node.Status != nil {
+				nodeStatus.State = node.Status.State
+			}
+			status.Nodes = append(status.Nodes, nodeStatus)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
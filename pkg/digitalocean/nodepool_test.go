@@ -0,0 +1,184 @@
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/digitalocean/godo"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"testing"
+)
+
+func TestNodePoolUpdateRequest(t *testing.T) {
+	current := func() *godo.KubernetesNodePool {
+		return &godo.KubernetesNodePool{
+			ID:     "pool-id",
+			Name:   "web",
+			Size:   "s-2vcpu-2gb",
+			Count:  3,
+			Labels: map[string]string{"role": "web"},
+			Taints: []godo.Taint{{Key: "dedicated", Value: "web", Effect: "NoSchedule"}},
+			Tags:   []string{"k8s", "k8s:cluster-1", "k8s:worker", "team-a"},
+		}
+	}
+	spec := func() v1alpha1.NodePool {
+		return v1alpha1.NodePool{
+			Name:   "web",
+			Size:   "s-2vcpu-2gb",
+			Count:  3,
+			Labels: map[string]string{"role": "web"},
+			Taints: []v1alpha1.Taint{{Key: "dedicated", Value: "web", Effect: "NoSchedule"}},
+			Tags:   []string{"team-a"},
+		}
+	}
+	tests := []struct {
+		name        string
+		pool        func(p *v1alpha1.NodePool)
+		current     func(p *godo.KubernetesNodePool)
+		wantChanged bool
+		wantCount   int
+		check       func(t *testing.T, r *godo.KubernetesNodePoolUpdateRequest)
+	}{
+		{name: "in sync", wantCount: 3},
+		{name: "count", pool: func(p *v1alpha1.NodePool) { p.Count = 5 }, wantChanged: true, wantCount: 5},
+		{
+			// autoscaler가 바꾼 count는 되돌리지 않는다.
+			name:      "autoscaled count",
+			pool:      func(p *v1alpha1.NodePool) { p.AutoScale, p.MinNodes, p.MaxNodes = true, 1, 5 },
+			current:   func(p *godo.KubernetesNodePool) { p.AutoScale, p.MinNodes, p.MaxNodes, p.Count = true, 1, 5, 4 },
+			wantCount: 4,
+		},
+		{
+			name:        "enable autoscaling",
+			pool:        func(p *v1alpha1.NodePool) { p.AutoScale, p.MinNodes, p.MaxNodes = true, 2, 6 },
+			wantChanged: true,
+			wantCount:   3,
+			check: func(t *testing.T, r *godo.KubernetesNodePoolUpdateRequest) {
+				if r.AutoScale == nil || !*r.AutoScale || *r.MinNodes != 2 || *r.MaxNodes != 6 {
+					t.Errorf("autoscale = %v, min = %v, max = %v", r.AutoScale, r.MinNodes, r.MaxNodes)
+				}
+			},
+		},
+		{
+			name:        "max nodes",
+			pool:        func(p *v1alpha1.NodePool) { p.AutoScale, p.MinNodes, p.MaxNodes = true, 1, 8 },
+			current:     func(p *godo.KubernetesNodePool) { p.AutoScale, p.MinNodes, p.MaxNodes = true, 1, 5 },
+			wantChanged: true,
+			wantCount:   3,
+		},
+		{
+			name:        "labels",
+			pool:        func(p *v1alpha1.NodePool) { p.Labels = map[string]string{"role": "api"} },
+			wantChanged: true,
+			wantCount:   3,
+			check: func(t *testing.T, r *godo.KubernetesNodePoolUpdateRequest) {
+				if r.Labels["role"] != "api" {
+					t.Errorf("labels = %v", r.Labels)
+				}
+			},
+		},
+		{
+			// godo request의 labels는 omitempty라서 전부 지우는 요청은 보낼 수 없다.
+			name:      "labels removed from spec",
+			pool:      func(p *v1alpha1.NodePool) { p.Labels = nil },
+			wantCount: 3,
+		},
+		{
+			name:        "taints removed from spec",
+			pool:        func(p *v1alpha1.NodePool) { p.Taints = nil },
+			wantChanged: true,
+			wantCount:   3,
+			check: func(t *testing.T, r *godo.KubernetesNodePoolUpdateRequest) {
+				if r.Taints == nil || len(*r.Taints) != 0 {
+					t.Errorf("taints = %v, want an empty list", r.Taints)
+				}
+			},
+		},
+		{
+			name:        "tags",
+			pool:        func(p *v1alpha1.NodePool) { p.Tags = []string{"team-b"} },
+			wantChanged: true,
+			wantCount:   3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, cur := spec(), current()
+			if tt.pool != nil {
+				tt.pool(&pool)
+			}
+			if tt.current != nil {
+				tt.current(cur)
+			}
+			request, changed := nodePoolUpdateRequest(pool, cur)
+			if changed != tt.wantChanged {
+				t.Fatalf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+			// name / count는 update API의 required 값이다.
+			if request.Name != "web" || request.Count == nil || *request.Count != tt.wantCount {
+				t.Errorf("name = %q, count = %v, want web, %d", request.Name, request.Count, tt.wantCount)
+			}
+			if tt.check != nil {
+				tt.check(t, request)
+			}
+		})
+	}
+}
+
+// spec에만 있는 pool은 만들고, spec에서 빠진 pool은 지운다.
+func TestUpdateSyncsNodePools(t *testing.T) {
+	k := apiKluster()
+	api, client, _ := newFakeAPI(t, false)
+	cluster := apiCluster(client, k)
+	cluster.NodePools = append(cluster.NodePools, &godo.KubernetesNodePool{ID: "old-pool-id", Name: "old", Size: "s-2vcpu-2gb", Count: 1})
+	api.clusters[cluster.ID] = cluster
+	k.Spec.NodePools = append(k.Spec.NodePools, v1alpha1.NodePool{
+		Name:      "batch",
+		Size:      "s-4vcpu-8gb",
+		AutoScale: true,
+		MinNodes:  1,
+		MaxNodes:  4,
+		Labels:    map[string]string{"role": "batch"},
+		Taints:    []v1alpha1.Taint{{Key: "batch", Effect: "NoSchedule"}},
+		Tags:      []string{"team-a"},
+	})
+
+	updated, err := client.Update(context.Background(), k, cluster.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"POST /v2/kubernetes/clusters/cluster-1/node_pools", "DELETE /v2/kubernetes/clusters/cluster-1/node_pools/old-pool-id"}
+	if calls := api.calls(); !updated || !equalStrings(calls, want) {
+		t.Fatalf("Update() = %v with requests %v, want true with %v", updated, calls, want)
+	}
+	request := &godo.KubernetesNodePoolCreateRequest{}
+	if err := json.Unmarshal(api.sent(want[0])[0], request); err != nil {
+		t.Fatal(err)
+	}
+	if request.Name != "batch" || !request.AutoScale || request.MinNodes != 1 || request.MaxNodes != 4 ||
+		request.Labels["role"] != "batch" || len(request.Taints) != 1 || request.Taints[0].Key != "batch" || !equalStrings(request.Tags, []string{"team-a"}) {
+		t.Errorf("create node pool request = %+v", request)
+	}
+}
+
+func TestNodePoolStatuses(t *testing.T) {
+	pools := []*godo.KubernetesNodePool{{
+		ID:    "pool-id",
+		Name:  "web",
+		Count: 2,
+		Nodes: []*godo.KubernetesNode{
+			{Name: "web-1", Status: &godo.KubernetesNodeStatus{State: "running"}},
+			{Name: "web-2"},
+		},
+	}}
+	statuses := nodePoolStatuses(pools)
+	if len(statuses) != 1 {
+		t.Fatalf("statuses = %+v", statuses)
+	}
+	status := statuses[0]
+	if status.Name != "web" || status.ID != "pool-id" || status.Count != 2 || len(status.Nodes) != 2 {
+		t.Fatalf("status = %+v", status)
+	}
+	if status.Nodes[0] != (v1alpha1.NodeStatus{Name: "web-1", State: "running"}) || status.Nodes[1] != (v1alpha1.NodeStatus{Name: "web-2"}) {
+		t.Errorf("nodes = %+v", status.Nodes)
+	}
+}
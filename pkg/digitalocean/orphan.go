@@ -0,0 +1,142 @@
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"github.com/digitalocean/godo"
+	"github.com/inspirit941/kluster/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"time"
+)
+
+// ManagedCluster: 이 management cluster의 owner tag가 붙은 DigitalOcean 클러스터. owner 정보는 tag에서 읽는다.
+type ManagedCluster struct {
+	ID        string
+	Name      string
+	Region    string
+	Namespace string
+	// Kluster 이름. tag에 쓸 수 없는 문자는 '_'로 바뀌어 있으므로 표시용으로만 사용한다.
+	KlusterName string
+	UID         types.UID
+	CreatedAt   time.Time
+}
+
+// owner tag가 없는 상태에서는 다른 management cluster의 클러스터와 구분할 수 없으므로 sweep하지 않는다.
+var errNoManagementClusterID = errors.New("management cluster ID is not set")
+
+// ManagedClusters: tokenSecret 계정의 클러스터 중 이 management cluster가 관리하는 클러스터 목록
+func (c *Client) ManagedClusters(ctx context.Context, tokenSecret TokenSecret) (_ []ManagedCluster, err error) {
+	ctx, span := tracer.Start(ctx, "digitalocean.ManagedClusters")
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	if c.managementClusterID == "" {
+		return nil, errNoManagementClusterID
+	}
+	token, err := getToken(ctx, c.kube, tokenSecret)
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := listClusters(ctx, c.newClient(token))
+	if err != nil {
+		return nil, err
+	}
+	mgmtTag := sanitizedTag(ManagementClusterPrefix, c.managementClusterID)
+	var managed []ManagedCluster
+	for _, cluster := range clusters {
+		if !hasTag(cluster.Tags, ManagedTag) || !hasTag(cluster.Tags, mgmtTag) {
+			continue
+		}
+		managed = append(managed, ManagedCluster{
+			ID:          cluster.ID,
+			Name:        cluster.Name,
+			Region:      cluster.RegionSlug,
+			Namespace:   tagValue(cluster.Tags, OwnerNamespacePrefix),
+			KlusterName: tagValue(cluster.Tags, OwnerNamePrefix),
+			UID:         types.UID(tagValue(cluster.Tags, OwnerUIDPrefix)),
+			CreatedAt:   cluster.CreatedAt,
+		})
+	}
+	span.SetAttributes(attribute.Int("digitalocean.managed_clusters", len(managed)))
+	return managed, nil
+}
+
+// DeleteOrphan: Kluster가 없는 클러스터를 삭제한다. DryRun 이벤트는 obj에 기록되고, dry-run이면 ErrDryRun을 리턴한다.
+func (c *Client) DeleteOrphan(ctx context.Context, tokenSecret TokenSecret, obj runtime.Object, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "digitalocean.DeleteOrphan")
+	span.SetAttributes(attribute.String("digitalocean.cluster_id", id))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	token, err := getToken(ctx, c.kube, tokenSecret)
+	if err != nil {
+		return err
+	}
+	client := c.newClient(token)
+	klog.FromContext(ctx).V(2).Info("calling DigitalOcean delete cluster API for orphaned cluster", "clusterID", id)
+	ok, err := c.mutate(ctx, obj, "delete orphaned cluster", map[string]string{"id": id}, func() error {
+		if _, err := client.Kubernetes.Delete(ctx, id); err != nil && !isNotFound(err) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrDryRun
+	}
+	return nil
+}
+
+// uid의 owner tag가 붙은 클러스터 ID. 없으면 "".
+func findOwnedCluster(ctx context.Context, client *godo.Client, uid types.UID) (string, error) {
+	clusters, err := listClusters(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	for _, cluster := range clusters {
+		if hasTag(cluster.Tags, OwnerUIDPrefix+string(uid)) {
+			return cluster.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// 계정의 모든 클러스터를 page 단위로 조회한다.
+// https://docs.digitalocean.com/reference/api/api-reference/#operation/kubernetes_list_clusters
+func listClusters(ctx context.Context, client *godo.Client) ([]*godo.KubernetesCluster, error) {
+	var clusters []*godo.KubernetesCluster
+	opt := &godo.ListOptions{Page: 1, PerPage: 200}
+	for {
+		page, resp, err := client.Kubernetes.List(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, page...)
+		if resp == nil || resp.Links == nil || resp.Links.IsLastPage() {
+			return clusters, nil
+		}
+		current, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+		opt.Page = current + 1
+	}
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"github.com/digitalocean/godo"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"testing"
+)
+
+func TestManagedClusters(t *testing.T) {
+	api, client, _ := newFakeAPI(t, false)
+	owned := apiCluster(client, apiKluster())
+	other := &godo.KubernetesCluster{ID: "cluster-2", Name: "other-mgmt", Tags: []string{ManagedTag, OwnerUIDPrefix + "uid-1", ManagementClusterPrefix + "other"}}
+	manual := &godo.KubernetesCluster{ID: "cluster-3", Name: "manual", Tags: []string{"team-a"}}
+	for _, cluster := range []*godo.KubernetesCluster{owned, other, manual} {
+		api.clusters[cluster.ID] = cluster
+	}
+
+	// 다른 management cluster의 클러스터와 owner tag가 없는 클러스터는 제외한다.
+	clusters, err := client.ManagedClusters(context.Background(), TokenSecret{Ref: "default/dosecret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ManagedCluster{ID: "cluster-1", Name: "kluster-0", Region: "nyc1", Namespace: "default", KlusterName: "kluster-0", UID: "uid-0"}
+	if len(clusters) != 1 || clusters[0] != want {
+		t.Errorf("ManagedClusters() = %+v, want [%+v]", clusters, want)
+	}
+
+	client.managementClusterID = ""
+	if _, err := client.ManagedClusters(context.Background(), TokenSecret{Ref: "default/dosecret"}); !errors.Is(err, errNoManagementClusterID) {
+		t.Errorf("ManagedClusters() without a management cluster ID = %v, want errNoManagementClusterID", err)
+	}
+}
+
+func TestDeleteOrphan(t *testing.T) {
+	api, client, _ := newFakeAPI(t, false)
+	api.clusters["cluster-1"] = apiCluster(client, apiKluster())
+
+	if err := client.DeleteOrphan(context.Background(), TokenSecret{Ref: "default/dosecret"}, &v1alpha1.Kluster{}, "cluster-1"); err != nil {
+		t.Fatal(err)
+	}
+	if calls := api.calls(); !equalStrings(calls, []string{"DELETE /v2/kubernetes/clusters/cluster-1"}) {
+		t.Errorf("requests = %v", calls)
+	}
+
+	_, client, recorder := newFakeAPI(t, true)
+	if err := client.DeleteOrphan(context.Background(), TokenSecret{Ref: "default/dosecret"}, &v1alpha1.Kluster{}, "cluster-1"); !errors.Is(err, ErrDryRun) {
+		t.Errorf("DeleteOrphan() in dry-run = %v, want ErrDryRun", err)
+	}
+	if events := drainEvents(recorder); len(events) != 1 || events[0] != `Normal DryRun Would call Digital Ocean delete orphaned cluster: {"id":"cluster-1"}` {
+		t.Errorf("events = %q", events)
+	}
+}
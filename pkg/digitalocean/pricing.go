@@ -0,0 +1,80 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"github.com/digitalocean/godo"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1/validation"
+	"github.com/inspirit941/kluster/pkg/tracing"
+	"time"
+)
+
+// size 가격 cache 유지 시간
+const pricesTTL = time.Hour
+
+// MonthlyCost: spec의 node pool 크기 / 노드 수와 DigitalOcean size 가격으로 계산한 월 예상 비용(USD).
+// 노드 수는 KlusterPolicy와 같은 방식으로 센다. autoscale pool은 maxNodes, schedule이 있는 pool은 가장 큰 count를 사용한다.
+func (c *Client) MonthlyCost(ctx context.Context, k *v1alpha1.Kluster) (_ float64, err error) {
+	ctx, span := tracer.Start(ctx, "digitalocean.MonthlyCost")
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	prices, err := c.sizePrices(ctx, k)
+	if err != nil {
+		return 0, err
+	}
+	return monthlyCost(k.Spec.NodePools, prices)
+}
+
+// size slug별 월 가격으로 계산한 node pool들의 월 비용
+func monthlyCost(pools []v1alpha1.NodePool, prices map[string]float64) (float64, error) {
+	cost := 0.0
+	for _, pool := range pools {
+		price, ok := prices[pool.Size]
+		if !ok {
+			return 0, fmt.Errorf("node pool %s: unknown droplet size %q", pool.Name, pool.Size)
+		}
+		cost += price * float64(validation.PoolNodes(pool))
+	}
+	return cost, nil
+}
+
+// sizePrices: droplet size slug별 월 가격. 가격은 계정과 관계없으므로 처음 조회한 Kluster의 token으로 가져와서 모든 Kluster가 같이 쓴다.
+// https://docs.digitalocean.com/reference/api/api-reference/#operation/sizes_list
+func (c *Client) sizePrices(ctx context.Context, k *v1alpha1.Kluster) (map[string]float64, error) {
+	c.pricesMu.Lock()
+	defer c.pricesMu.Unlock()
+	if c.prices != nil && time.Since(c.pricesAt) < pricesTTL {
+		return c.prices, nil
+	}
+
+	client, err := c.godoClient(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+	prices := map[string]float64{}
+	opt := &godo.ListOptions{Page: 1, PerPage: 200}
+	for {
+		sizes, resp, err := client.Sizes.List(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, size := range sizes {
+			prices[size.Slug] = size.PriceMonthly
+		}
+		if resp == nil || resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+		current, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+		opt.Page = current + 1
+	}
+	c.prices = prices
+	c.pricesAt = time.Now()
+	return prices, nil
+}
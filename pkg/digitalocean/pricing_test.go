@@ -0,0 +1,67 @@
+package digitalocean
+
+import (
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"testing"
+)
+
+func TestMonthlyCost(t *testing.T) {
+	prices := map[string]float64{"s-2vcpu-2gb": 18, "s-4vcpu-8gb": 48}
+	tests := []struct {
+		name    string
+		pools   []v1alpha1.NodePool
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "no node pools",
+		},
+		{
+			name:  "fixed count",
+			pools: []v1alpha1.NodePool{{Name: "web", Size: "s-2vcpu-2gb", Count: 3}},
+			want:  54,
+		},
+		{
+			name: "several pools",
+			pools: []v1alpha1.NodePool{
+				{Name: "web", Size: "s-2vcpu-2gb", Count: 3},
+				{Name: "batch", Size: "s-4vcpu-8gb", Count: 2},
+			},
+			want: 150,
+		},
+		{
+			name:  "autoScale counts maxNodes",
+			pools: []v1alpha1.NodePool{{Name: "web", Size: "s-2vcpu-2gb", Count: 2, AutoScale: true, MinNodes: 1, MaxNodes: 5}},
+			want:  90,
+		},
+		{
+			name: "schedules count the largest count",
+			pools: []v1alpha1.NodePool{{
+				Name:  "web",
+				Size:  "s-2vcpu-2gb",
+				Count: 2,
+				Schedules: []v1alpha1.ScalingSchedule{
+					{Name: "day", Schedule: "0 8 * * *", Count: 4},
+					{Name: "night", Schedule: "0 20 * * *", Count: 1},
+				},
+			}},
+			want: 72,
+		},
+		{
+			name:    "unknown size",
+			pools:   []v1alpha1.NodePool{{Name: "gpu", Size: "g-2vcpu-8gb", Count: 1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := monthlyCost(tt.pools, prices)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("monthlyCost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("monthlyCost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"github.com/digitalocean/godo"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Kluster가 생성 / adopt한 클러스터에 붙이는 owner tag.
+// 어떤 management cluster의 어떤 Kluster가 관리하는 클러스터인지 DigitalOcean 쪽에서도 알 수 있고, Retain으로 놓아줄 때 이 tag만 지운다.
+// orphan sweeper는 ManagedTag와 이 management cluster의 tag가 붙은 클러스터만 대상으로 한다.
+const (
+	ManagedTag              = "kluster-managed"
+	OwnerUIDPrefix          = "kluster-uid:"
+	ManagementClusterPrefix = "kluster-mgmt:"
+	OwnerNamespacePrefix    = "kluster-namespace:"
+	OwnerNamePrefix         = "kluster-name:"
+)
+
+var ownerTagPrefixes = []string{OwnerUIDPrefix, ManagementClusterPrefix, OwnerNamespacePrefix, OwnerNamePrefix}
+
+// DigitalOcean tag에 쓸 수 없는 문자('.' 등)는 '_'로 바꾸고, 최대 길이 255자에 맞춰 자른다.
+var invalidTagChars = regexp.MustCompile(`[^a-zA-Z0-9_:\-]`)
+
+func sanitizedTag(prefix, value string) string {
+	t := prefix + invalidTagChars.ReplaceAllString(value, "_")
+	if len(t) > 255 {
+		t = t[:255]
+	}
+	return t
+}
+
+// ownerTags: k가 관리하는 클러스터에 붙는 tag 목록. management cluster ID가 없으면 해당 tag는 생략한다.
+func (c *Client) ownerTags(k *v1alpha1.Kluster) []string {
+	tags := []string{ManagedTag, OwnerUIDPrefix + string(k.UID), sanitizedTag(OwnerNamespacePrefix, k.Namespace), sanitizedTag(OwnerNamePrefix, k.Name)}
+	if c.managementClusterID != "" {
+		tags = append(tags, sanitizedTag(ManagementClusterPrefix, c.managementClusterID))
+	}
+	return tags
+}
+
+func isOwnerTag(t string) bool {
+	if t == ManagedTag {
+		return true
+	}
+	for _, prefix := range ownerTagPrefixes {
+		if strings.HasPrefix(t, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// prefix로 시작하는 tag의 값. 없으면 "".
+func tagValue(tags []string, prefix string) string {
+	for _, t := range tags {
+		if strings.HasPrefix(t, prefix) {
+			return strings.TrimPrefix(t, prefix)
+		}
+	}
+	return ""
+}
+
+// DigitalOcean이 자동으로 붙이는 tag. k8s, k8s:<cluster id>, k8s:worker 등
+func isProviderTag(tag string) bool {
+	return tag == "k8s" || strings.HasPrefix(tag, "k8s:")
+}
+
+// provider tag와 owner tag
+func isSystemTag(tag string) bool {
+	return isProviderTag(tag) || isOwnerTag(tag)
+}
+
+// provider tag를 제외한 tag. 사용자 tag와 owner tag가 남는다.
+func withoutProviderTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		if !isProviderTag(tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// system tag를 제외한 사용자 tag
+func userTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		if !isSystemTag(tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// godo의 KubernetesClusterUpdateRequest는 tags에 omitempty가 붙어 있어서 tag를 모두 지우는 요청을 보낼 수 없다.
+// 같은 update API를 tags가 항상 포함되는 body로 직접 호출한다. name은 update API의 required 값.
+type clusterTagsRequest struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+func (c *Client) updateClusterTags(ctx context.Context, k *v1alpha1.Kluster, client *godo.Client, cluster *godo.KubernetesCluster, tags []string) (bool, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+	request := &clusterTagsRequest{Name: cluster.Name, Tags: tags}
+	return c.mutate(ctx, k, "update cluster tags", request, func() error {
+		req, err := client.NewRequest(ctx, http.MethodPut, "/v2/kubernetes/clusters/"+cluster.ID, request)
+		if err != nil {
+			return err
+		}
+		_, err = client.Do(ctx, req, nil)
+		return err
+	})
+}
+
+func isNotFound(err error) bool {
+	var errResp *godo.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
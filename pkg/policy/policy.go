@@ -0,0 +1,119 @@
+package policy
+
+import (
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1/validation"
+	klusterscheme "github.com/inspirit941/kluster/pkg/client/clientset/versioned/scheme"
+	informer "github.com/inspirit941/kluster/pkg/client/informers/externalversions/inspirit941.dev/v1alpha1"
+	klister "github.com/inspirit941/kluster/pkg/client/listers/inspirit941.dev/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Checker: namespace의 KlusterPolicy로 Kluster를 검사한다. admission webhook과 controller가 같은 규칙으로 검사하도록 한 곳에 모아둔다.
+// namespace의 다른 Kluster 노드 수를 세야 하므로 lister를 사용한다.
+type Checker struct {
+	policies  klister.KlusterPolicyLister
+	klusters  klister.KlusterLister
+	templates klister.KlusterTemplateLister
+	synced    []cache.InformerSynced
+}
+
+// informer factory가 시작되기 전에 만들어야 policy informer도 같이 시작된다.
+func NewChecker(informers informer.Interface) *Checker {
+	return &Checker{
+		policies:  informers.KlusterPolicies().Lister(),
+		klusters:  informers.Klusters().Lister(),
+		templates: informers.KlusterTemplates().Lister(),
+		synced: []cache.InformerSynced{
+			informers.KlusterPolicies().Informer().HasSynced,
+			informers.Klusters().Informer().HasSynced,
+			informers.KlusterTemplates().Informer().HasSynced,
+		},
+	}
+}
+
+func (c *Checker) HasSynced() bool {
+	for _, synced := range c.synced {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolve: template과 합친 뒤 기본값을 채운 spec. template이 없으면 NotFound 에러를 리턴한다.
+func (c *Checker) Resolve(kluster *v1alpha1.Kluster) (*v1alpha1.KlusterSpec, error) {
+	resolved := kluster.DeepCopy()
+	if name := kluster.Spec.Template; name != "" {
+		template, err := c.templates.KlusterTemplates(kluster.Namespace).Get(name)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Spec = v1alpha1.MergeTemplate(kluster.Spec, template.Spec)
+	}
+	klusterscheme.Scheme.Default(resolved)
+	return &resolved.Spec, nil
+}
+
+// Check: spec(template과 합치고 기본값을 채운 kluster의 spec)이 namespace의 모든 KlusterPolicy를 지키는지 확인한다.
+// current는 kluster가 이미 쓰고 있는 노드 수로, namespace 노드 수가 한도를 넘은 상태에서도 노드를 늘리지 않는 변경은 허용한다.
+// policy가 줄어든 뒤에도 이미 있는 클러스터를 줄이는 작업까지 막지 않기 위해서다.
+func (c *Checker) Check(kluster *v1alpha1.Kluster, spec *v1alpha1.KlusterSpec, current int) (field.ErrorList, error) {
+	policies, err := c.policies.KlusterPolicies(kluster.Namespace).List(labels.Everything())
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+	specPath := field.NewPath("spec")
+	var errs field.ErrorList
+	for _, policy := range policies {
+		errs = append(errs, validation.ValidatePolicy(spec, policy, specPath)...)
+	}
+
+	nodes := validation.KlusterNodes(spec)
+	if nodes <= current {
+		return errs, nil
+	}
+	var total int
+	totalCounted := false
+	for _, policy := range policies {
+		max := policy.Spec.MaxNodesPerNamespace
+		if max == nil {
+			continue
+		}
+		if !totalCounted {
+			if total, err = c.namespaceNodes(kluster); err != nil {
+				return nil, err
+			}
+			total += nodes
+			totalCounted = true
+		}
+		if total > int(*max) {
+			errs = append(errs, field.Forbidden(specPath.Child("nodePools"), fmt.Sprintf("KlusterPolicy %s allows at most %d nodes in namespace %s, %d nodes would be used", policy.Name, *max, kluster.Namespace, total)))
+		}
+	}
+	return errs, nil
+}
+
+// kluster를 제외한 namespace의 Kluster 노드 수의 합. 삭제 중인 Kluster는 세지 않는다.
+// template을 찾을 수 없는 Kluster는 Kluster spec에 있는 node pool만 센다.
+func (c *Checker) namespaceNodes(kluster *v1alpha1.Kluster) (int, error) {
+	klusters, err := c.klusters.Klusters(kluster.Namespace).List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, other := range klusters {
+		if other.Name == kluster.Name || other.DeletionTimestamp != nil {
+			continue
+		}
+		spec, err := c.Resolve(other)
+		if err != nil {
+			spec = &other.Spec
+		}
+		total += validation.KlusterNodes(spec)
+	}
+	return total, nil
+}
@@ -0,0 +1,135 @@
+package policy
+
+import (
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/client/clientset/versioned/fake"
+	"github.com/inspirit941/kluster/pkg/client/informers/externalversions"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"testing"
+)
+
+// newChecker: objects를 informer cache에 넣은 Checker. informer는 시작하지 않는다.
+func newChecker(t *testing.T, objects ...runtime.Object) *Checker {
+	t.Helper()
+	informers := externalversions.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Inspirit941().V1alpha1()
+	checker := NewChecker(informers)
+	for _, obj := range objects {
+		var err error
+		switch obj := obj.(type) {
+		case *v1alpha1.Kluster:
+			err = informers.Klusters().Informer().GetIndexer().Add(obj)
+		case *v1alpha1.KlusterTemplate:
+			err = informers.KlusterTemplates().Informer().GetIndexer().Add(obj)
+		case *v1alpha1.KlusterPolicy:
+			err = informers.KlusterPolicies().Informer().GetIndexer().Add(obj)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return checker
+}
+
+// newKluster: 기본값을 채우면 3개 노드를 가진 Kluster
+func newKluster(namespace, name string) *v1alpha1.Kluster {
+	return &v1alpha1.Kluster{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+}
+
+func newPolicy(name string, spec v1alpha1.KlusterPolicySpec) *v1alpha1.KlusterPolicy {
+	return &v1alpha1.KlusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}, Spec: spec}
+}
+
+func TestResolve(t *testing.T) {
+	template := &v1alpha1.KlusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "small", Namespace: "default"},
+		Spec:       v1alpha1.KlusterTemplateSpec{Region: "fra1", NodePools: []v1alpha1.NodePool{{Name: "web", Size: "s-1vcpu-2gb", Count: 1}}},
+	}
+	checker := newChecker(t, template)
+
+	kluster := newKluster("default", "kluster")
+	kluster.Spec.Template = "small"
+	spec, err := checker.Resolve(kluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// template 값 위에 기본값이 채워진다.
+	if spec.Region != "fra1" || spec.Version != v1alpha1.DefaultVersion || len(spec.NodePools) != 1 || spec.PrimaryNodePool != "web" {
+		t.Errorf("Resolve() = %+v", spec)
+	}
+	if kluster.Spec.Region != "" {
+		t.Error("Resolve() changed the Kluster")
+	}
+
+	kluster.Spec.Template = "missing"
+	if _, err := checker.Resolve(kluster); !apierrors.IsNotFound(err) {
+		t.Errorf("Resolve() with a missing template = %v, want NotFound", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	deleting := newKluster("default", "deleting")
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	withTemplate := newKluster("default", "with-template")
+	withTemplate.Spec.Template = "large"
+	large := &v1alpha1.KlusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "large", Namespace: "default"},
+		Spec:       v1alpha1.KlusterTemplateSpec{NodePools: []v1alpha1.NodePool{{Name: "web", Size: "s-2vcpu-2gb", Count: 6}}},
+	}
+	maxNodes := func(max int32) *v1alpha1.KlusterPolicy {
+		return newPolicy("nodes", v1alpha1.KlusterPolicySpec{MaxNodesPerNamespace: pointer.Int32(max)})
+	}
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		current int
+		want    int
+	}{
+		{name: "no policy"},
+		{name: "region", objects: []runtime.Object{newPolicy("regions", v1alpha1.KlusterPolicySpec{AllowedRegions: []string{"fra1"}})}, want: 1},
+		// 모든 policy를 지켜야 한다.
+		{
+			name: "several policies",
+			objects: []runtime.Object{
+				newPolicy("regions", v1alpha1.KlusterPolicySpec{AllowedRegions: []string{"fra1"}}),
+				newPolicy("tags", v1alpha1.KlusterPolicySpec{RequiredTags: []string{"team-a"}}),
+			},
+			want: 2,
+		},
+		{name: "within namespace nodes", objects: []runtime.Object{maxNodes(6), newKluster("default", "other")}},
+		{name: "over namespace nodes", objects: []runtime.Object{maxNodes(5), newKluster("default", "other")}, want: 1},
+		// 노드를 늘리지 않는 변경은 한도를 넘은 상태여도 허용한다.
+		{name: "not increasing nodes", objects: []runtime.Object{maxNodes(5), newKluster("default", "other")}, current: 3},
+		// 삭제 중인 Kluster, 다른 namespace의 Kluster, 검사하는 Kluster 자신은 세지 않는다.
+		{
+			name:    "uncounted Klusters",
+			objects: []runtime.Object{maxNodes(3), deleting, newKluster("other", "other"), newKluster("default", "kluster")},
+		},
+		// template을 참조하는 Kluster는 template과 합친 spec으로 센다.
+		{name: "Kluster with template", objects: []runtime.Object{maxNodes(8), large, withTemplate}, want: 1},
+		// template을 찾을 수 없으면 Kluster spec에 있는 node pool만 센다.
+		{name: "Kluster with missing template", objects: []runtime.Object{maxNodes(8), withTemplate}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := newChecker(t, tt.objects...)
+			kluster := newKluster("default", "kluster")
+			spec, err := checker.Resolve(kluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			errs, err := checker.Check(kluster, spec, tt.current)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(errs) != tt.want {
+				t.Errorf("Check() = %v, want %d errors", errs, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// --tracing-exporter flag로 지정 가능한 값
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+
+	serviceName = "kluster"
+	// 모든 span에 붙는 Kluster 식별자. 이 값으로 한 Kluster의 reconcile / DigitalOcean 호출을 묶어서 조회한다.
+	KlusterUIDKey = attribute.Key("kluster.uid")
+)
+
+// Options: operator 실행 시 flag로 받는 tracing 설정
+type Options struct {
+	Exporter     string
+	OTLPEndpoint string
+	OTLPInsecure bool
+}
+
+// Setup은 global TracerProvider를 설정하고, 종료 시 남은 span을 flush하는 shutdown 함수를 리턴한다.
+// exporter가 none이면 otel의 기본 no-op provider를 그대로 사용한다.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch opts.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOTLP:
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.OTLPEndpoint)}
+		if opts.OTLPInsecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, clientOpts...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q, must be one of %s, %s, %s", opts.Exporter, ExporterNone, ExporterStdout, ExporterOTLP)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating %s exporter: %w", opts.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown, nil
+}
+
+// Tracer: 패키지별 tracer. global provider가 나중에 설정되어도 otel이 알아서 위임해준다.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer("github.com/inspirit941/kluster/" + name)
+}
+
+// KlusterUID: span attribute로 붙일 Kluster UID
+func KlusterUID(uid types.UID) attribute.KeyValue {
+	return KlusterUIDKey.String(string(uid))
+}
+
+// RecordError: span에 에러를 기록하고 status를 Error로 바꾼다.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
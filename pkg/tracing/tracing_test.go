@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		exporter string
+		wantErr  bool
+	}{
+		{exporter: ""},
+		{exporter: ExporterNone},
+		{exporter: ExporterStdout},
+		{exporter: "jaeger", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.exporter, func(t *testing.T) {
+			shutdown, err := Setup(context.Background(), Options{Exporter: tt.exporter})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Setup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if err := shutdown(context.Background()); err != nil {
+				t.Errorf("shutdown() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRecordError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "ok")
+	RecordError(span, nil)
+	span.End()
+	_, span = tracer.Start(context.Background(), "failed")
+	RecordError(span, errors.New("boom"))
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if got := spans[0].Status().Code; got != codes.Unset {
+		t.Errorf("status of the span without error = %v, want Unset", got)
+	}
+	if len(spans[0].Events()) != 0 {
+		t.Errorf("span without error has events %v", spans[0].Events())
+	}
+	if got := spans[1].Status(); got.Code != codes.Error || got.Description != "boom" {
+		t.Errorf("status of the failed span = %+v, want Error boom", got)
+	}
+	if events := spans[1].Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Errorf("events of the failed span = %v, want one exception event", events)
+	}
+}
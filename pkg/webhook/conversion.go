@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1beta1"
+	"io"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"net/http"
+)
+
+// CRD의 conversion strategy가 Webhook이면 API server는 저장된 버전과 요청된 버전이 다를 때 /convert를 호출한다.
+// 모든 변환은 hub(v1beta1)를 거친다: 원래 버전 -> v1beta1 -> 목표 버전
+func serveConversion(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	review := &apiextensionsv1.ConversionReview{}
+	if _, _, err := codecs.UniversalDeserializer().Decode(body, nil, review); err != nil {
+		http.Error(w, fmt.Sprintf("decoding conversion review: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "conversion review has no request", http.StatusBadRequest)
+		return
+	}
+
+	logger := klog.LoggerWithValues(klog.Background(), "uid", review.Request.UID, "desiredAPIVersion", review.Request.DesiredAPIVersion)
+	response := &apiextensionsv1.ConversionResponse{
+		UID:    review.Request.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+	for _, obj := range review.Request.Objects {
+		converted, err := convertKluster(obj.Raw, review.Request.DesiredAPIVersion)
+		if err != nil {
+			logger.Error(err, "converting Kluster")
+			response.ConvertedObjects = nil
+			response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			break
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	logger.V(2).Info("conversion request handled", "objects", len(review.Request.Objects), "status", response.Result.Status)
+
+	review.Response = response
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		logger.Error(err, "writing conversion response")
+	}
+}
+
+func convertKluster(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.Kind != "Kluster" {
+		return nil, fmt.Errorf("unsupported kind %q", typeMeta.Kind)
+	}
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+
+	// 원래 버전 -> hub
+	hub := &v1beta1.Kluster{}
+	switch typeMeta.APIVersion {
+	case v1beta1.SchemeGroupVersion.String():
+		if err := json.Unmarshal(raw, hub); err != nil {
+			return nil, err
+		}
+	case v1alpha1.SchemeGroupVersion.String():
+		spoke := &v1alpha1.Kluster{}
+		if err := json.Unmarshal(raw, spoke); err != nil {
+			return nil, err
+		}
+		if err := spoke.ConvertTo(hub); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported apiVersion %q", typeMeta.APIVersion)
+	}
+
+	// hub -> 목표 버전
+	var out runtime.Object
+	switch desiredAPIVersion {
+	case v1beta1.SchemeGroupVersion.String():
+		out = hub
+	case v1alpha1.SchemeGroupVersion.String():
+		spoke := &v1alpha1.Kluster{}
+		if err := spoke.ConvertFrom(hub); err != nil {
+			return nil, err
+		}
+		out = spoke
+	default:
+		return nil, fmt.Errorf("unsupported desired apiVersion %q", desiredAPIVersion)
+	}
+	gv, err := schema.ParseGroupVersion(desiredAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	out.GetObjectKind().SetGroupVersionKind(gv.WithKind("Kluster"))
+	return json.Marshal(out)
+}
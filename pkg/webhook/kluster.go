@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1/validation"
+	klusterscheme "github.com/inspirit941/kluster/pkg/client/clientset/versioned/scheme"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+)
+
+// Kluster 생성 / 수정 요청에 기본값을 채워넣는다. 기본값은 defaulter-gen이 만든 SetObjectDefaults_Kluster를 그대로 사용함.
+// 변경된 spec은 JSON patch로 돌려준다.
+func defaultKlusterReview(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	kluster := &v1alpha1.Kluster{}
+	if err := json.Unmarshal(req.Object.Raw, kluster); err != nil {
+		return badRequest(fmt.Errorf("decoding Kluster: %w", err))
+	}
+	// create 요청의 object에는 namespace가 비어 있을 수 있음
+	if kluster.Namespace == "" {
+		kluster.Namespace = req.Namespace
+	}
+
+	defaulted := kluster.DeepCopy()
+	if req.Operation == admissionv1.Update {
+		old := &v1alpha1.Kluster{}
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return badRequest(fmt.Errorf("decoding old Kluster: %w", err))
+		}
+		syncReplicasFromCount(&defaulted.Spec, &old.Spec)
+	}
+	klusterscheme.Scheme.Default(defaulted)
+	if equality.Semantic.DeepEqual(kluster.Spec, defaulted.Spec) {
+		return allowed()
+	}
+
+	// spec 전체를 교체한다. "add"는 path가 이미 있으면 replace처럼 동작하므로 spec이 없는 경우도 처리됨.
+	patch, err := json.Marshal([]jsonPatchOperation{{Op: "add", Path: "/spec", Value: defaulted.Spec}})
+	if err != nil {
+		return badRequest(err)
+	}
+	klog.FromContext(ctx).V(2).Info("defaulting Kluster spec", "patch", string(patch))
+	return patched(patch)
+}
+
+// 기본값은 replicas가 primary node pool의 count를 덮어쓰므로(scale subresource는 replicas만 바꾼다),
+// 수정 요청에서 count만 바뀌고 replicas는 그대로면 사용자가 count를 고친 것으로 보고 replicas를 count에 맞춘다.
+func syncReplicasFromCount(spec, old *v1alpha1.KlusterSpec) {
+	if spec.Replicas == nil || !equality.Semantic.DeepEqual(spec.Replicas, old.Replicas) {
+		return
+	}
+	count, ok := primaryPoolCount(spec)
+	if oldCount, oldOK := primaryPoolCount(old); !ok || (oldOK && count == oldCount) {
+		return
+	}
+	replicas := int32(count)
+	spec.Replicas = &replicas
+}
+
+func primaryPoolCount(spec *v1alpha1.KlusterSpec) (int, bool) {
+	primary := spec.PrimaryNodePool
+	if primary == "" && len(spec.NodePools) > 0 {
+		primary = spec.NodePools[0].Name
+	}
+	for _, pool := range spec.NodePools {
+		if pool.Name == primary {
+			return pool.Count, true
+		}
+	}
+	return 0, false
+}
+
+// Kluster 생성 / 수정 요청을 검증한다. spec이 잘못된 경우 digitalocean.Create가 실패하기 전에 요청 자체를 거절함.
+// 수정 요청은 spec이 바뀔 때만 검증하고, namespace의 KlusterPolicy도 검사한다.
+func (s *Server) validateKlusterReview(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	kluster := &v1alpha1.Kluster{}
+	if err := json.Unmarshal(req.Object.Raw, kluster); err != nil {
+		return badRequest(fmt.Errorf("decoding Kluster: %w", err))
+	}
+
+	if kluster.Namespace == "" {
+		kluster.Namespace = req.Namespace
+	}
+
+	var errs field.ErrorList
+	switch req.Operation {
+	case admissionv1.Create:
+		errs = validation.ValidateKluster(kluster)
+		errs = append(errs, s.checkPolicy(ctx, kluster, 0)...)
+	case admissionv1.Update:
+		old := &v1alpha1.Kluster{}
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return badRequest(fmt.Errorf("decoding old Kluster: %w", err))
+		}
+		// finalizer / label / annotation만 바뀌는 요청과 삭제 중인 object는 검증하지 않는다.
+		// 저장된 object가 나중에 추가된 validation 규칙이나 policy를 어기더라도 controller가 finalizer를 지울 수 있어야 삭제가 끝난다.
+		if kluster.DeletionTimestamp != nil || equality.Semantic.DeepEqual(kluster.Spec, old.Spec) {
+			return allowed()
+		}
+		// template이 채우는 값도 바꿀 수 없으므로 양쪽 다 template과 합친 spec으로 비교한다.
+		// template을 아직 찾을 수 없으면 Kluster에 적힌 값만 비교하고, 나머지는 controller가 template을 찾은 뒤에 검증함.
+		spec, specErr := s.policies.Resolve(kluster)
+		oldSpec, oldErr := s.policies.Resolve(old)
+		if specErr == nil && oldErr == nil {
+			errs = validation.ValidateResolvedKlusterUpdate(kluster, spec, oldSpec)
+		} else {
+			errs = validation.ValidateKlusterUpdate(kluster, old)
+		}
+		// 노드 수를 늘리지 않는 변경은 namespace 노드 수 한도를 넘은 상태에서도 허용한다.
+		current := 0
+		if oldErr == nil {
+			current = validation.KlusterNodes(oldSpec)
+		}
+		errs = append(errs, s.checkPolicy(ctx, kluster, current)...)
+	default:
+		return allowed()
+	}
+
+	if len(errs) == 0 {
+		return allowed()
+	}
+	gk := v1alpha1.SchemeGroupVersion.WithKind("Kluster").GroupKind()
+	return denied(apierrors.NewInvalid(gk, req.Name, errs).ErrStatus)
+}
+
+// template과 합친 spec으로 policy를 검사한다. template이 아직 없으면 controller가 template을 찾은 뒤에 검사함.
+func (s *Server) checkPolicy(ctx context.Context, kluster *v1alpha1.Kluster, current int) field.ErrorList {
+	spec, err := s.policies.Resolve(kluster)
+	if err != nil {
+		klog.FromContext(ctx).V(2).Info("skipping KlusterPolicy check", "kluster", klog.KObj(kluster), "error", err.Error())
+		return nil
+	}
+	errs, err := s.policies.Check(kluster, spec, current)
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath("spec"), err)}
+	}
+	return errs
+}
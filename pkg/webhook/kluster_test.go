@@ -0,0 +1,314 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/client/clientset/versioned/fake"
+	klusterscheme "github.com/inspirit941/kluster/pkg/client/clientset/versioned/scheme"
+	"github.com/inspirit941/kluster/pkg/client/informers/externalversions"
+	"github.com/inspirit941/kluster/pkg/policy"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"strings"
+	"testing"
+)
+
+func replicasSpec(replicas *int32, counts ...int) v1alpha1.KlusterSpec {
+	spec := v1alpha1.KlusterSpec{PrimaryNodePool: "pool-0", Replicas: replicas}
+	for i, count := range counts {
+		spec.NodePools = append(spec.NodePools, v1alpha1.NodePool{Name: fmt.Sprintf("pool-%d", i), Size: "s-2vcpu-2gb", Count: count})
+	}
+	return spec
+}
+
+// mutating webhook이 수정 요청에 적용하는 순서(syncReplicasFromCount 후 기본값)대로 primary pool의 count와 replicas를 확인한다.
+func TestSyncReplicasFromCount(t *testing.T) {
+	tests := []struct {
+		name         string
+		old          v1alpha1.KlusterSpec
+		spec         v1alpha1.KlusterSpec
+		wantReplicas int32
+		wantCount    int
+	}{
+		{
+			name:         "count edited",
+			old:          replicasSpec(pointer.Int32(3), 3),
+			spec:         replicasSpec(pointer.Int32(3), 5),
+			wantReplicas: 5,
+			wantCount:    5,
+		},
+		{
+			name:         "replicas scaled",
+			old:          replicasSpec(pointer.Int32(3), 3),
+			spec:         replicasSpec(pointer.Int32(4), 3),
+			wantReplicas: 4,
+			wantCount:    4,
+		},
+		{
+			name:         "both edited, replicas wins",
+			old:          replicasSpec(pointer.Int32(3), 3),
+			spec:         replicasSpec(pointer.Int32(4), 5),
+			wantReplicas: 4,
+			wantCount:    4,
+		},
+		{
+			name:         "other pool edited",
+			old:          replicasSpec(pointer.Int32(3), 3, 1),
+			spec:         replicasSpec(pointer.Int32(3), 3, 2),
+			wantReplicas: 3,
+			wantCount:    3,
+		},
+		{
+			name:         "replicas not set",
+			old:          replicasSpec(nil, 3),
+			spec:         replicasSpec(nil, 5),
+			wantReplicas: 5,
+			wantCount:    5,
+		},
+		{
+			name: "primary pool defaults to the first pool",
+			old: func() v1alpha1.KlusterSpec {
+				spec := replicasSpec(pointer.Int32(3), 3)
+				spec.PrimaryNodePool = ""
+				return spec
+			}(),
+			spec: func() v1alpha1.KlusterSpec {
+				spec := replicasSpec(pointer.Int32(3), 2)
+				spec.PrimaryNodePool = ""
+				return spec
+			}(),
+			wantReplicas: 2,
+			wantCount:    2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kluster := &v1alpha1.Kluster{Spec: tt.spec}
+			kluster.Namespace = "default"
+			syncReplicasFromCount(&kluster.Spec, &tt.old)
+			klusterscheme.Scheme.Default(kluster)
+			if got := pointer.Int32Deref(kluster.Spec.Replicas, 0); got != tt.wantReplicas {
+				t.Errorf("replicas = %d, want %d", got, tt.wantReplicas)
+			}
+			if got := kluster.Spec.NodePools[0].Count; got != tt.wantCount {
+				t.Errorf("primary pool count = %d, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+// template이 채우는 값도 수정 요청에서 바꿀 수 없어야 한다.
+func TestValidateKlusterReviewUpdate(t *testing.T) {
+	informers := externalversions.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Inspirit941().V1alpha1()
+	s := &Server{policies: policy.NewChecker(informers)}
+	pools := []v1alpha1.NodePool{{Name: "pool-0", Size: "s-2vcpu-2gb", Count: 3}}
+	for _, template := range []*v1alpha1.KlusterTemplate{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nyc", Namespace: "default"},
+			Spec:       v1alpha1.KlusterTemplateSpec{Region: "nyc1", Version: "1.25.4-do.0", TokenSecret: "default/dosecret", NodePools: pools},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "sfo", Namespace: "default"},
+			Spec:       v1alpha1.KlusterTemplateSpec{Region: "sfo3", Version: "1.25.4-do.0", TokenSecret: "default/dosecret", NodePools: pools},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nyc-large", Namespace: "default"},
+			Spec: v1alpha1.KlusterTemplateSpec{Region: "nyc1", Version: "1.25.4-do.0", TokenSecret: "default/dosecret",
+				NodePools: []v1alpha1.NodePool{{Name: "pool-0", Size: "s-4vcpu-8gb", Count: 3}}},
+		},
+	} {
+		if err := informers.KlusterTemplates().Informer().GetIndexer().Add(template); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		old      string
+		template string
+		tags     []string
+		wantErr  string
+	}{
+		{name: "same template", old: "nyc", template: "nyc", tags: []string{"team-a"}},
+		{name: "template with a different region", old: "nyc", template: "sfo", wantErr: "spec.region"},
+		{name: "template with a different node size", old: "nyc", template: "nyc-large", wantErr: "spec.nodePools[0].size"},
+		{name: "template not found yet", old: "nyc", template: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := &v1alpha1.Kluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kluster-0", Namespace: "default"},
+				Spec:       v1alpha1.KlusterSpec{Name: "kluster-0", Template: tt.old},
+			}
+			kluster := old.DeepCopy()
+			kluster.Spec.Template, kluster.Spec.Tags = tt.template, tt.tags
+			req := &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Name:      "kluster-0",
+				Namespace: "default",
+				Object:    runtime.RawExtension{Raw: mustMarshal(t, kluster)},
+				OldObject: runtime.RawExtension{Raw: mustMarshal(t, old)},
+			}
+			resp := s.validateKlusterReview(context.Background(), req)
+			if tt.wantErr == "" {
+				if !resp.Allowed {
+					t.Errorf("request denied: %s", resp.Result.Message)
+				}
+				return
+			}
+			if resp.Allowed || !strings.Contains(resp.Result.Message, tt.wantErr) {
+				t.Errorf("response = %+v, want denied with %s", resp, tt.wantErr)
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, obj interface{}) []byte {
+	t.Helper()
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestValidateKlusterReview(t *testing.T) {
+	s := &Server{policies: policy.NewChecker(externalversions.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Inspirit941().V1alpha1())}
+	valid := &v1alpha1.Kluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kluster-0", Namespace: "default"},
+		Spec: v1alpha1.KlusterSpec{
+			Name:        "kluster-0",
+			Region:      "nyc1",
+			Version:     "1.25.4-do.0",
+			TokenSecret: "default/dosecret",
+			NodePools:   []v1alpha1.NodePool{{Name: "pool-0", Size: "s-2vcpu-2gb", Count: 3}},
+		},
+	}
+	invalid := valid.DeepCopy()
+	invalid.Spec.Region = "new-york"
+	relabeled := invalid.DeepCopy()
+	relabeled.Labels = map[string]string{"team": "a"}
+	deleting := invalid.DeepCopy()
+	deleting.Spec.Tags = []string{"team-a"}
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	regionChanged := valid.DeepCopy()
+	regionChanged.Spec.Region = "sfo3"
+
+	tests := []struct {
+		name      string
+		operation admissionv1.Operation
+		kluster   *v1alpha1.Kluster
+		old       *v1alpha1.Kluster
+		wantErr   string
+	}{
+		{name: "create valid", operation: admissionv1.Create, kluster: valid},
+		{name: "create invalid", operation: admissionv1.Create, kluster: invalid, wantErr: "spec.region"},
+		{name: "update region", operation: admissionv1.Update, kluster: regionChanged, old: valid, wantErr: "spec.region"},
+		// 나중에 추가된 규칙을 어기는 object도 label / finalizer 변경과 삭제는 막지 않는다.
+		{name: "update labels of an invalid object", operation: admissionv1.Update, kluster: relabeled, old: invalid},
+		{name: "update of a deleting object", operation: admissionv1.Update, kluster: deleting, old: invalid},
+		{name: "delete", operation: admissionv1.Delete, kluster: invalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &admissionv1.AdmissionRequest{
+				Operation: tt.operation,
+				Name:      tt.kluster.Name,
+				Namespace: "default",
+				Object:    runtime.RawExtension{Raw: mustMarshal(t, tt.kluster)},
+			}
+			if tt.old != nil {
+				req.OldObject = runtime.RawExtension{Raw: mustMarshal(t, tt.old)}
+			}
+			resp := s.validateKlusterReview(context.Background(), req)
+			if tt.wantErr == "" {
+				if !resp.Allowed {
+					t.Errorf("request denied: %s", resp.Result.Message)
+				}
+				return
+			}
+			if resp.Allowed || !strings.Contains(resp.Result.Message, tt.wantErr) {
+				t.Fatalf("response = %+v, want denied with %s", resp, tt.wantErr)
+			}
+			if resp.Result.Reason != metav1.StatusReasonInvalid {
+				t.Errorf("reason = %s, want %s", resp.Result.Reason, metav1.StatusReasonInvalid)
+			}
+		})
+	}
+}
+
+func TestDefaultKlusterReview(t *testing.T) {
+	defaulted := &v1alpha1.Kluster{ObjectMeta: metav1.ObjectMeta{Name: "kluster-0", Namespace: "default"}}
+	klusterscheme.Scheme.Default(defaulted)
+	scaled := defaulted.DeepCopy()
+	scaled.Spec.NodePools[0].Count = 5
+
+	tests := []struct {
+		name         string
+		operation    admissionv1.Operation
+		kluster      *v1alpha1.Kluster
+		old          *v1alpha1.Kluster
+		wantPatch    bool
+		wantReplicas int32
+	}{
+		{
+			// create 요청의 object에는 namespace가 없을 수 있다. tokenSecret은 요청의 namespace로 채운다.
+			name:         "empty spec",
+			operation:    admissionv1.Create,
+			kluster:      &v1alpha1.Kluster{ObjectMeta: metav1.ObjectMeta{Name: "kluster-0"}},
+			wantPatch:    true,
+			wantReplicas: int32(v1alpha1.DefaultNodePoolCount),
+		},
+		{name: "already defaulted", operation: admissionv1.Create, kluster: defaulted},
+		{name: "primary pool count edited", operation: admissionv1.Update, kluster: scaled, old: defaulted, wantPatch: true, wantReplicas: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &admissionv1.AdmissionRequest{
+				Operation: tt.operation,
+				Name:      "kluster-0",
+				Namespace: "default",
+				Object:    runtime.RawExtension{Raw: mustMarshal(t, tt.kluster)},
+			}
+			if tt.old != nil {
+				req.OldObject = runtime.RawExtension{Raw: mustMarshal(t, tt.old)}
+			}
+			resp := defaultKlusterReview(context.Background(), req)
+			if !resp.Allowed {
+				t.Fatalf("request denied: %s", resp.Result.Message)
+			}
+			if !tt.wantPatch {
+				if resp.Patch != nil {
+					t.Errorf("patch = %s, want none", resp.Patch)
+				}
+				return
+			}
+			var patch []struct {
+				Op    string               `json:"op"`
+				Path  string               `json:"path"`
+				Value v1alpha1.KlusterSpec `json:"value"`
+			}
+			if err := json.Unmarshal(resp.Patch, &patch); err != nil {
+				t.Fatal(err)
+			}
+			if len(patch) != 1 || patch[0].Op != "add" || patch[0].Path != "/spec" {
+				t.Fatalf("patch = %s, want one add of /spec", resp.Patch)
+			}
+			spec := patch[0].Value
+			if spec.TokenSecret != "default/"+v1alpha1.DefaultTokenSecretName {
+				t.Errorf("tokenSecret = %q", spec.TokenSecret)
+			}
+			if got := pointer.Int32Deref(spec.Replicas, 0); got != tt.wantReplicas {
+				t.Errorf("replicas = %d, want %d", got, tt.wantReplicas)
+			}
+			if got := spec.NodePools[0].Count; got != int(tt.wantReplicas) {
+				t.Errorf("primary pool count = %d, want %d", got, tt.wantReplicas)
+			}
+		})
+	}
+}
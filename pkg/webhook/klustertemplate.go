@@ -0,0 +1,36 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1"
+	"github.com/inspirit941/kluster/pkg/apis/inspirit941.dev/v1alpha1/validation"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// KlusterTemplate 생성 / 수정 요청을 검증한다. template의 값이 있는 필드는 Kluster와 같은 규칙으로 검증함.
+// 수정 요청은 Kluster 수정과 같이 region / vpcUUID / node pool size 등 바꿀 수 없는 필드도 확인한다.
+func validateKlusterTemplateReview(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return allowed()
+	}
+	template := &v1alpha1.KlusterTemplate{}
+	if err := json.Unmarshal(req.Object.Raw, template); err != nil {
+		return badRequest(fmt.Errorf("decoding KlusterTemplate: %w", err))
+	}
+	errs := validation.ValidateKlusterTemplate(template)
+	if req.Operation == admissionv1.Update {
+		old := &v1alpha1.KlusterTemplate{}
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return badRequest(fmt.Errorf("decoding old KlusterTemplate: %w", err))
+		}
+		errs = validation.ValidateKlusterTemplateUpdate(template, old)
+	}
+	if len(errs) == 0 {
+		return allowed()
+	}
+	gk := v1alpha1.SchemeGroupVersion.WithKind("KlusterTemplate").GroupKind()
+	return denied(apierrors.NewInvalid(gk, req.Name, errs).ErrStatus)
+}
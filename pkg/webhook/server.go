@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"github.com/inspirit941/kluster/pkg/policy"
+	"io"
+	admissionv1 "k8s.io/api/admission/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"net/http"
+	"time"
+)
+
+var (
+	scheme = runtime.NewScheme()
+	codecs = serializer.NewCodecFactory(scheme)
+)
+
+func init() {
+	utilruntime.Must(admissionv1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+}
+
+// Server: operator binary 안에서 같이 동작하는 HTTPS admission webhook 서버.
+// API server는 webhook을 https로만 호출하므로 인증서가 반드시 필요하다.
+// policies는 Kluster 요청을 namespace의 KlusterPolicy로 검사한다.
+type Server struct {
+	addr     string
+	certFile string
+	keyFile  string
+	mux      *http.ServeMux
+	policies *policy.Checker
+}
+
+func NewServer(addr, certFile, keyFile string, policies *policy.Checker) *Server {
+	s := &Server{
+		addr:     addr,
+		certFile: certFile,
+		keyFile:  keyFile,
+		mux:      http.NewServeMux(),
+		policies: policies,
+	}
+	s.mux.Handle("/mutate-kluster", admitFunc(defaultKlusterReview))
+	s.mux.Handle("/validate-kluster", admitFunc(s.validateKlusterReview))
+	s.mux.Handle("/validate-klustertemplate", admitFunc(validateKlusterTemplateReview))
+	s.mux.HandleFunc("/convert", serveConversion)
+	return s
+}
+
+// ch가 닫힐 때까지 webhook 요청을 처리한다.
+func (s *Server) Run(ch <-chan struct{}) error {
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig:         &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+	// policy 검사에 lister를 사용하므로 cache가 채워진 뒤에 요청을 받는다.
+	if !cache.WaitForCacheSync(ch, s.policies.HasSynced) {
+		return fmt.Errorf("waiting for KlusterPolicy cache to sync")
+	}
+	go func() {
+		<-ch
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			klog.ErrorS(err, "shutting down webhook server")
+		}
+	}()
+
+	klog.InfoS("starting webhook server", "addr", s.addr)
+	if err := srv.ListenAndServeTLS(s.certFile, s.keyFile); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// admitFunc: AdmissionRequest를 받아 AdmissionResponse를 돌려주는 함수.
+// decode / encode 같은 공통 처리는 ServeHTTP에서 한다.
+type admitFunc func(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+func (f admitFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+		http.Error(w, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review := &admissionv1.AdmissionReview{}
+	if _, _, err := codecs.UniversalDeserializer().Decode(body, nil, review); err != nil {
+		http.Error(w, fmt.Sprintf("decoding admission review: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "admission review has no request", http.StatusBadRequest)
+		return
+	}
+
+	logger := klog.LoggerWithValues(klog.Background(),
+		"kluster", klog.KRef(review.Request.Namespace, review.Request.Name),
+		"uid", review.Request.UID,
+		"operation", review.Request.Operation,
+	)
+	ctx := klog.NewContext(r.Context(), logger)
+
+	response := f(ctx, review.Request)
+	response.UID = review.Request.UID
+	logger.V(2).Info("admission request handled", "path", r.URL.Path, "allowed", response.Allowed)
+
+	review.Response = response
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		logger.Error(err, "writing admission response")
+	}
+}
+
+func allowed() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+func denied(status metav1.Status) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: false, Result: &status}
+}
+
+// RFC 6902 JSON patch operation
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+func patched(patch []byte) *admissionv1.AdmissionResponse {
+	patchType := admissionv1.PatchTypeJSONPatch
+	return &admissionv1.AdmissionResponse{Allowed: true, Patch: patch, PatchType: &patchType}
+}
+
+func badRequest(err error) *admissionv1.AdmissionResponse {
+	return denied(metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusBadRequest,
+		Reason:  metav1.StatusReasonBadRequest,
+		Message: err.Error(),
+	})
+}
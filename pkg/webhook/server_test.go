@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdmitFuncServeHTTP(t *testing.T) {
+	review := func(request *admissionv1.AdmissionRequest) string {
+		raw, err := json.Marshal(&admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request:  request,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(raw)
+	}
+	handler := admitFunc(func(_ context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		if req.Name == "denied" {
+			return denied(metav1.Status{Message: "denied"})
+		}
+		return allowed()
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		wantCode    int
+		wantAllowed bool
+	}{
+		{
+			name:        "allowed",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        review(&admissionv1.AdmissionRequest{UID: "uid-1", Name: "kluster"}),
+			wantCode:    http.StatusOK,
+			wantAllowed: true,
+		},
+		{
+			name:        "denied",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        review(&admissionv1.AdmissionRequest{UID: "uid-1", Name: "denied"}),
+			wantCode:    http.StatusOK,
+		},
+		{
+			name:        "GET",
+			method:      http.MethodGet,
+			contentType: "application/json",
+			wantCode:    http.StatusMethodNotAllowed,
+		},
+		{
+			name:        "yaml body",
+			method:      http.MethodPost,
+			contentType: "application/yaml",
+			body:        review(&admissionv1.AdmissionRequest{UID: "uid-1"}),
+			wantCode:    http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "not a review",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        "{",
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:        "review without request",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        review(nil),
+			wantCode:    http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/validate-kluster", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.wantCode {
+				t.Fatalf("status code = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			got := &admissionv1.AdmissionReview{}
+			if err := json.Unmarshal(w.Body.Bytes(), got); err != nil {
+				t.Fatal(err)
+			}
+			// API server는 같은 UID의 response만 받는다.
+			if got.Response == nil || got.Response.UID != types.UID("uid-1") {
+				t.Fatalf("response = %+v, want UID uid-1", got.Response)
+			}
+			if got.Response.Allowed != tt.wantAllowed {
+				t.Errorf("allowed = %v, want %v", got.Response.Allowed, tt.wantAllowed)
+			}
+			if got.Request != nil {
+				t.Errorf("response review still has the request")
+			}
+		})
+	}
+}